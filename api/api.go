@@ -0,0 +1,741 @@
+package api
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/example_scheduler"
+)
+
+//Scheduler is the set of operations the API exposes
+type Scheduler interface {
+	SubmitJob(job *example_scheduler.JobSpec) error
+	SubmitJobOnce(job *example_scheduler.JobSpec, key string) (*example_scheduler.JobSpec, bool, error)
+	SubmitJobs(jobs []*example_scheduler.JobSpec) error
+	Jobs() []example_scheduler.JobSummary
+	Tasks() []example_scheduler.TaskSummary
+	KillTask(taskId string, scale bool) (string, error)
+	RemoveJob(jobId string) (string, error)
+	Kill(id string) (example_scheduler.KillSummary, error)
+	Scale(jobId string, instances int, selection string) (string, error)
+	UpdateJob(job *example_scheduler.JobSpec) error
+	Deployments() []example_scheduler.DeploymentSummary
+	ApproveDeployment(jobId string) error
+	RollbackDeployment(jobId string) error
+	Queue() example_scheduler.QueueSummary
+	Runs(jobId string) ([]example_scheduler.CronRunSummary, error)
+	Indexes(jobId string) ([]example_scheduler.IndexSummary, error)
+	RetryIndexes(jobId string) ([]int, error)
+	Pipeline() []example_scheduler.PipelineSummary
+	HostFilter() example_scheduler.HostFilter
+	SetHostFilter(filter example_scheduler.HostFilter)
+	Maintenance() example_scheduler.Maintenance
+	Pause(reason string) example_scheduler.Maintenance
+	Resume() example_scheduler.Maintenance
+	Audit(filter example_scheduler.AuditFilter) []example_scheduler.AuditEntry
+	Offers() example_scheduler.OfferStats
+	Healthy() error
+	Ready() error
+	AuditRequest(principal, method, path string, status int)
+	Subscribe(filter example_scheduler.EventFilter, since int64) ([]example_scheduler.Event, <-chan example_scheduler.Event, func(), error)
+}
+
+//Server is the HTTP management API of the scheduler. It runs alongside the
+//driver so operators can manage jobs and tasks of a running framework
+type Server struct {
+	scheduler Scheduler
+	mux       *http.ServeMux
+
+	//The tokens required by the calls, nil for none, and whether the GET
+	//calls need one
+	tokens    *Tokens
+	openReads bool
+}
+
+//BatchResponse is the body of the answer to the POST /v1/jobs of an array
+//of specs: the IDs of the jobs submitted and the jobs, in the order of the
+//array
+type BatchResponse struct {
+	JobIDs []string                     `json:"job_ids"`
+	Jobs   []*example_scheduler.JobSpec `json:"jobs"`
+}
+
+//PauseRequest is the body of POST /v1/maintenance/pause, which may be
+//empty
+type PauseRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+//maxIdempotencyKey is the longest Idempotency-Key header taken
+const maxIdempotencyKey = 255
+
+//ScaleRequest is the body of PUT /v1/jobs/{id}/scale
+type ScaleRequest struct {
+	Instances int `json:"instances"`
+
+	//KillSelection chooses the tasks killed when scaling down:
+	//newest-first or least-healthy-first, the default
+	KillSelection string `json:"kill_selection,omitempty"`
+}
+
+//KillResponse is the body of the kills accepted by DELETE /v1/tasks/{id}
+//and DELETE /v1/jobs/{id}. The kill is polled at GET /v1/kills/{id}
+type KillResponse struct {
+	ID string `json:"id"`
+}
+
+//ScaleResponse is the body of the answer to PUT /v1/jobs/{id}/scale, with
+//the deployment bringing the job to its new number of instances
+type ScaleResponse struct {
+	Instances    int    `json:"instances"`
+	DeploymentID string `json:"deployment_id"`
+}
+
+//Error is the body of every failed request
+type Error struct {
+	Error string `json:"error"`
+}
+
+//NewServer creates the API for the given scheduler
+func NewServer(scheduler Scheduler) *Server {
+	s := &Server{
+		scheduler: scheduler,
+		mux:       http.NewServeMux(),
+	}
+
+	routes := s.routes()
+	for _, r := range routes {
+		s.mux.HandleFunc(r.pattern, r.handler)
+	}
+
+	//A document that drifted from the routes is a bug of the build, like
+	//a route registered twice
+	if err := checkOpenAPI(routes); err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+//ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := s.authenticate(r)
+	if id.principal == "" && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="scheduler"`)
+		writeError(w, http.StatusUnauthorized, "a valid bearer token is required")
+		return
+	}
+
+	if r.Method == "GET" || r.Method == "HEAD" {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	//Every call changing the scheduler is audited with its principal, the
+	//ones its role doesn't allow too
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	if id.principal != "" && !permitted(id.role, r) {
+		writeError(recorder, http.StatusForbidden, fmt.Sprintf("%s is a %s, the call needs the %s role", id.principal, id.role, requiredRole(r)))
+	} else {
+		s.mux.ServeHTTP(recorder, r)
+	}
+	s.scheduler.AuditRequest(id.principal, r.Method, r.URL.Path, recorder.status)
+}
+
+//ListenAndServe serves the API on addr. It blocks until the server fails
+func (s *Server) ListenAndServe(addr string) error {
+	log.Infof("Serving the API on %s", addr)
+	return http.ListenAndServe(addr, s)
+}
+
+//ListenAndServeTLS serves the API over HTTPS with the certificate and key
+//files. With clientCA, the file of the authorities of the clients, only the
+//clients with a certificate they signed are accepted
+func (s *Server) ListenAndServeTLS(addr, certFile, keyFile, clientCA string) error {
+	server := &http.Server{Addr: addr, Handler: s}
+	if clientCA != "" {
+		pool, err := loadCertPool(clientCA)
+		if err != nil {
+			return err
+		}
+		server.TLSConfig = &tls.Config{
+			ClientCAs:  pool,
+			ClientAuth: tls.RequireAndVerifyClientCert,
+		}
+	}
+
+	log.Infof("Serving the API over TLS on %s", addr)
+	return server.ListenAndServeTLS(certFile, keyFile)
+}
+
+//loadCertPool reads the PEM file of the authorities of the clients
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in %s", path)
+	}
+
+	return pool, nil
+}
+
+//jobs handles GET and POST /v1/jobs. A POST with an Idempotency-Key
+//header that was already given with the same spec answers the job it
+//submitted, with 200 instead of 201 and the Idempotent-Replayed header. A
+//POST of an array of specs submits them all, or none
+func (s *Server) jobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "GET" {
+		writeJSON(w, http.StatusOK, s.scheduler.Jobs())
+		return
+	}
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job spec: "+err.Error())
+		return
+	}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		s.submitBatch(w, r, body)
+		return
+	}
+
+	var job example_scheduler.JobSpec
+	if err := json.Unmarshal(body, &job); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job spec: "+err.Error())
+		return
+	}
+
+	key := r.Header.Get("Idempotency-Key")
+	if len(key) > maxIdempotencyKey {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("the idempotency key can't be longer than %d bytes", maxIdempotencyKey))
+		return
+	}
+	if key == "" {
+		if err := s.scheduler.SubmitJob(&job); err != nil {
+			writeSchedulerError(w, err)
+			return
+		}
+
+		w.Header().Set("Location", "/v1/jobs/"+job.ID)
+		writeJSON(w, http.StatusCreated, &job)
+		return
+	}
+
+	submitted, replayed, err := s.scheduler.SubmitJobOnce(&job, key)
+	if err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v1/jobs/"+submitted.ID)
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+		writeJSON(w, http.StatusOK, submitted)
+		return
+	}
+	writeJSON(w, http.StatusCreated, submitted)
+}
+
+//submitBatch handles the POST /v1/jobs of an array of specs, submitted all
+//at once. The error of a job tells its position in the array
+func (s *Server) submitBatch(w http.ResponseWriter, r *http.Request, body json.RawMessage) {
+	if r.Header.Get("Idempotency-Key") != "" {
+		writeError(w, http.StatusBadRequest, "idempotency keys are only taken for a single job")
+		return
+	}
+
+	var jobs []*example_scheduler.JobSpec
+	if err := json.Unmarshal(body, &jobs); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job specs: "+err.Error())
+		return
+	}
+	if len(jobs) == 0 {
+		writeError(w, http.StatusBadRequest, "the batch has no job")
+		return
+	}
+	for i, job := range jobs {
+		if job == nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("job %d: no spec", i))
+			return
+		}
+	}
+
+	if err := s.scheduler.SubmitJobs(jobs); err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	resp := BatchResponse{JobIDs: make([]string, 0, len(jobs)), Jobs: jobs}
+	for _, job := range jobs {
+		resp.JobIDs = append(resp.JobIDs, job.ID)
+	}
+	writeJSON(w, http.StatusCreated, &resp)
+}
+
+//job handles PUT and DELETE /v1/jobs/{id}, PUT /v1/jobs/{id}/scale,
+//GET /v1/jobs/{id}/runs, GET /v1/jobs/{id}/indexes and
+//POST /v1/jobs/{id}/retry
+func (s *Server) job(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/jobs/"), "/")
+	if len(parts) == 1 && parts[0] != "" && r.Method == "DELETE" {
+		s.removeJob(w, r, parts[0])
+		return
+	}
+	if len(parts) == 1 && parts[0] != "" {
+		s.updateJob(w, r, parts[0])
+		return
+	}
+	if len(parts) == 2 && parts[0] != "" && parts[1] == "runs" {
+		s.runs(w, r, parts[0])
+		return
+	}
+	if len(parts) == 2 && parts[0] != "" && parts[1] == "indexes" {
+		s.indexes(w, r, parts[0])
+		return
+	}
+	if len(parts) == 2 && parts[0] != "" && parts[1] == "retry" {
+		s.retry(w, r, parts[0])
+		return
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "scale" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if r.Method != "PUT" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req ScaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid scale request: "+err.Error())
+		return
+	}
+
+	id, err := s.scheduler.Scale(parts[0], req.Instances, req.KillSelection)
+	if err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v1/deployments/"+id)
+	writeJSON(w, http.StatusOK, &ScaleResponse{Instances: req.Instances, DeploymentID: id})
+}
+
+//removeJob handles DELETE /v1/jobs/{id}, which kills every task of the
+//job and forgets it
+func (s *Server) removeJob(w http.ResponseWriter, r *http.Request, jobId string) {
+	id, err := s.scheduler.RemoveJob(jobId)
+	if err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	writeKill(w, id)
+}
+
+//updateJob handles PUT /v1/jobs/{id}, which deploys the new spec of the
+//job
+func (s *Server) updateJob(w http.ResponseWriter, r *http.Request, jobId string) {
+	if r.Method != "PUT" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var job example_scheduler.JobSpec
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job spec: "+err.Error())
+		return
+	}
+	if job.ID == "" {
+		job.ID = jobId
+	}
+	if job.ID != jobId {
+		writeError(w, http.StatusBadRequest, "the job id can't change")
+		return
+	}
+
+	if err := s.scheduler.UpdateJob(&job); err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &job)
+}
+
+//runs handles GET /v1/jobs/{id}/runs, the runs of a cron job
+func (s *Server) runs(w http.ResponseWriter, r *http.Request, jobId string) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	runs, err := s.scheduler.Runs(jobId)
+	if err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, runs)
+}
+
+//indexes handles GET /v1/jobs/{id}/indexes, the state of each index of an
+//indexed job
+func (s *Server) indexes(w http.ResponseWriter, r *http.Request, jobId string) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	indexes, err := s.scheduler.Indexes(jobId)
+	if err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, indexes)
+}
+
+//retry handles POST /v1/jobs/{id}/retry, which launches again the failed
+//indexes of an indexed job. It returns the indexes retried
+func (s *Server) retry(w http.ResponseWriter, r *http.Request, jobId string) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	retried, err := s.scheduler.RetryIndexes(jobId)
+	if err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, retried)
+}
+
+//deployments handles GET /v1/deployments
+func (s *Server) deployments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.scheduler.Deployments())
+}
+
+//deploymentByID handles GET /v1/deployments/{id}. Only the last
+//deployment of each job is known
+func (s *Server) deploymentByID(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	for _, d := range s.scheduler.Deployments() {
+		if d.ID == id {
+			writeJSON(w, http.StatusOK, &d)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "unknown deployment")
+}
+
+//queue handles GET /v1/queue
+func (s *Server) queue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.scheduler.Queue())
+}
+
+//pipeline handles GET /v1/pipeline
+func (s *Server) pipeline(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.scheduler.Pipeline())
+}
+
+//audit handles GET /v1/audit, filtered by the action, job, task, agent,
+//since (RFC 3339) and limit query parameters
+func (s *Server) audit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := example_scheduler.AuditFilter{
+		Action:  query.Get("action"),
+		JobID:   query.Get("job"),
+		TaskID:  query.Get("task"),
+		AgentID: query.Get("agent"),
+	}
+	if since := query.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid since: "+err.Error())
+			return
+		}
+		filter.Since = t
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "invalid limit: "+limit)
+			return
+		}
+		filter.Limit = n
+	}
+
+	writeJSON(w, http.StatusOK, s.scheduler.Audit(filter))
+}
+
+//deployment handles GET /v1/deployments/{id},
+//POST /v1/deployments/{job}/approve and POST /v1/deployments/{job}/rollback
+func (s *Server) deployment(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/deployments/"), "/")
+	if len(parts) == 1 && parts[0] != "" {
+		s.deploymentByID(w, r, parts[0])
+		return
+	}
+	if len(parts) != 2 || parts[0] == "" || (parts[1] != "approve" && parts[1] != "rollback") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	action := s.scheduler.ApproveDeployment
+	if parts[1] == "rollback" {
+		action = s.scheduler.RollbackDeployment
+	}
+
+	if err := action(parts[0]); err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+//tasks handles GET /v1/tasks, filtered by the job and state query
+//parameters. state takes several states separated by commas
+func (s *Server) tasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	jobId := r.URL.Query().Get("job")
+	states := make(map[string]bool)
+	for _, state := range strings.Split(r.URL.Query().Get("state"), ",") {
+		if state = strings.TrimSpace(state); state != "" {
+			states[taskState(state)] = true
+		}
+	}
+
+	tasks := []example_scheduler.TaskSummary{}
+	for _, t := range s.scheduler.Tasks() {
+		if (jobId == "" || t.JobID == jobId) && (len(states) == 0 || states[t.State]) {
+			tasks = append(tasks, t)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+//taskState returns the Mesos name of a task state given as running,
+//TASK_RUNNING or any case of them
+func taskState(state string) string {
+	state = strings.ToUpper(state)
+	if !strings.HasPrefix(state, "TASK_") {
+		state = "TASK_" + state
+	}
+
+	return state
+}
+
+//task handles DELETE /v1/tasks/{id}. With ?scale=true the job of the task
+//is scaled down instead of replacing it
+func (s *Server) task(w http.ResponseWriter, r *http.Request) {
+	taskId := strings.TrimPrefix(r.URL.Path, "/v1/tasks/")
+	if taskId == "" || strings.Contains(taskId, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if r.Method != "DELETE" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	id, err := s.scheduler.KillTask(taskId, r.URL.Query().Get("scale") == "true")
+	if err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	writeKill(w, id)
+}
+
+//kill handles GET /v1/kills/{id}, polled until the tasks killed reach a
+//terminal state
+func (s *Server) kill(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/v1/kills/")
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	summary, err := s.scheduler.Kill(id)
+	if err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &summary)
+}
+
+//writeKill answers a kill accepted, pointing at where to poll it
+func writeKill(w http.ResponseWriter, id string) {
+	w.Header().Set("Location", "/v1/kills/"+id)
+	writeJSON(w, http.StatusAccepted, &KillResponse{ID: id})
+}
+
+//offers handles GET /v1/offers
+func (s *Server) offers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.scheduler.Offers())
+}
+
+//hosts handles GET and PUT /v1/hosts
+func (s *Server) hosts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, s.scheduler.HostFilter())
+	case "PUT":
+		var filter example_scheduler.HostFilter
+		if err := json.NewDecoder(r.Body).Decode(&filter); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid host filter: "+err.Error())
+			return
+		}
+
+		s.scheduler.SetHostFilter(filter)
+		writeJSON(w, http.StatusOK, s.scheduler.HostFilter())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+//maintenance handles GET /v1/maintenance
+func (s *Server) maintenance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.scheduler.Maintenance())
+}
+
+//maintenanceAction handles POST /v1/maintenance/pause and
+//POST /v1/maintenance/resume
+func (s *Server) maintenanceAction(w http.ResponseWriter, r *http.Request) {
+	action := strings.TrimPrefix(r.URL.Path, "/v1/maintenance/")
+	if action != "pause" && action != "resume" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if action == "resume" {
+		writeJSON(w, http.StatusOK, s.scheduler.Resume())
+		return
+	}
+
+	var req PauseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, "invalid pause request: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.scheduler.Pause(req.Reason))
+}
+
+//writeSchedulerError maps the errors of the scheduler operations to status
+//codes. Anything else is a validation error of the request. The error of a
+//job of a batch gets the status of its cause
+func writeSchedulerError(w http.ResponseWriter, err error) {
+	cause := err
+	if batchErr, ok := err.(*example_scheduler.BatchError); ok {
+		cause = batchErr.Err
+	}
+
+	switch cause {
+	case example_scheduler.ErrUnknownJob, example_scheduler.ErrUnknownTask, example_scheduler.ErrUnknownKill:
+		writeError(w, http.StatusNotFound, err.Error())
+	case example_scheduler.ErrJobExists, example_scheduler.ErrNotWaiting, example_scheduler.ErrNoPrevious,
+		example_scheduler.ErrHasDependents:
+		writeError(w, http.StatusConflict, err.Error())
+	case example_scheduler.ErrQueueFull:
+		writeError(w, http.StatusTooManyRequests, err.Error())
+	case example_scheduler.ErrNotRegistered:
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+	case example_scheduler.ErrEventsGone:
+		writeError(w, http.StatusGone, err.Error())
+	case example_scheduler.ErrKeyReused:
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		writeError(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, &Error{Error: msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warnln("Unable to write the API response:", err)
+	}
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//The roles of the principals, each one allowed what the ones before are
+const (
+	//RoleViewer only reads the state of the scheduler
+	RoleViewer = "viewer"
+	//RoleOperator also kills and scales the tasks, retries the indexes and
+	//approves or rolls back the deployments
+	RoleOperator = "operator"
+	//RoleAdmin also submits, updates and removes the jobs and sets the host
+	//filter
+	RoleAdmin = "admin"
+)
+
+//roleRanks orders the roles
+var roleRanks = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+//identity is the principal a token authenticates, with its role
+type identity struct {
+	principal string
+	role      string
+}
+
+//Tokens are the bearer tokens accepted by the API, each one with the
+//principal it authenticates and its role, loaded from a file
+type Tokens struct {
+	path string
+
+	mutex      sync.RWMutex
+	identities map[string]identity
+}
+
+//LoadTokens reads the tokens file: a principal, its token and optionally
+//its role per line, separated by spaces. The principals without a role are
+//admins. Empty lines and the ones starting with # are skipped
+func LoadTokens(path string) (*Tokens, error) {
+	t := &Tokens{path: path}
+	if err := t.Reload(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+//Reload reads the tokens file again, the tokens removed stop working. On
+//error the tokens loaded before are kept
+func (t *Tokens) Reload() error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	identities := make(map[string]identity)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 && len(fields) != 3 {
+			return fmt.Errorf("%s:%d: expected a principal, a token and optionally a role", t.path, n)
+		}
+		if _, ok := identities[fields[1]]; ok {
+			return fmt.Errorf("%s:%d: token of %s already given to another principal", t.path, n, fields[0])
+		}
+		id := identity{principal: fields[0], role: RoleAdmin}
+		if len(fields) == 3 {
+			id.role = fields[2]
+		}
+		if roleRanks[id.role] == 0 {
+			return fmt.Errorf("%s:%d: unknown role %q, expected %s, %s or %s", t.path, n, id.role, RoleViewer, RoleOperator, RoleAdmin)
+		}
+		identities[fields[1]] = id
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	t.identities = identities
+	t.mutex.Unlock()
+	log.Infof("%d API tokens loaded from %s", len(identities), t.path)
+
+	return nil
+}
+
+//identity returns the principal of the token and its role, empty if the
+//token isn't known. Every token is compared in constant time
+func (t *Tokens) identity(token string) identity {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	var id identity
+	for known, i := range t.identities {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			id = i
+		}
+	}
+
+	return id
+}
+
+//RequireTokens makes the API refuse the calls without one of the tokens,
+//in the Authorization header as a bearer token. With openReads the GET
+//calls don't need one. The probes, the dashboard page and the OpenAPI
+//document are always open, the supervisors and the browsers don't send a
+//token
+func (s *Server) RequireTokens(tokens *Tokens, openReads bool) {
+	s.tokens = tokens
+	s.openReads = openReads
+}
+
+//authenticate returns the principal of the bearer token of the request and
+//its role, empty without a known token
+func (s *Server) authenticate(r *http.Request) identity {
+	header := r.Header.Get("Authorization")
+	if s.tokens == nil || !strings.HasPrefix(header, "Bearer ") {
+		return identity{}
+	}
+
+	return s.tokens.identity(strings.TrimPrefix(header, "Bearer "))
+}
+
+//requiredRole returns the role the request needs: reading is for viewers,
+//the calls acting on the tasks already submitted are for operators and
+//the ones changing the jobs, or any other change, for admins
+func requiredRole(r *http.Request) string {
+	if r.Method == "GET" || r.Method == "HEAD" {
+		return RoleViewer
+	}
+
+	path := r.URL.Path
+	switch {
+	case r.Method == "DELETE" && strings.HasPrefix(path, "/v1/tasks/"),
+		r.Method == "PUT" && strings.HasPrefix(path, "/v1/jobs/") && strings.HasSuffix(path, "/scale"),
+		r.Method == "POST" && strings.HasPrefix(path, "/v1/jobs/") && strings.HasSuffix(path, "/retry"),
+		r.Method == "POST" && strings.HasPrefix(path, "/v1/deployments/"),
+		r.Method == "POST" && strings.HasPrefix(path, "/v1/maintenance/"):
+		return RoleOperator
+	}
+
+	return RoleAdmin
+}
+
+//permitted reports if the role may make the request
+func permitted(role string, r *http.Request) bool {
+	return roleRanks[role] >= roleRanks[requiredRole(r)]
+}
+
+//authorized reports if the request may go on without a principal
+func (s *Server) authorized(r *http.Request) bool {
+	if s.tokens == nil {
+		return true
+	}
+	if r.Method != "GET" && r.Method != "HEAD" {
+		return false
+	}
+
+	switch r.URL.Path {
+	case "/", "/ui/", "/healthz", "/readyz", "/v1/openapi.json":
+		return true
+	}
+
+	return s.openReads
+}
+
+//statusRecorder keeps the status code of the answer, for the audit log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"net/http"
+)
+
+//root redirects / to the dashboard, any other path not handled elsewhere
+//is not found
+func (s *Server) root(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	http.Redirect(w, r, "/ui/", http.StatusFound)
+}
+
+//dashboard handles GET /ui/, a single page showing the jobs, the health
+//of their instances, the last status updates and the offers, built on the
+//rest of the API so it needs nothing else to be served
+func (s *Server) dashboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if r.URL.Path != "/ui/" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(dashboardPage))
+}
+
+//dashboardPage refreshes the jobs, the tasks and the offers every few
+//seconds and follows the status updates on /v1/events
+const dashboardPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Scheduler</title>
+<style>
+body { font-family: sans-serif; margin: 1em 2em; color: #222; }
+h1 { font-size: 1.4em; }
+h2 { font-size: 1.1em; margin-top: 1.5em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 0.25em 0.75em; border-bottom: 1px solid #ddd; font-size: 0.9em; }
+th { background: #f4f4f4; }
+.ok { color: #1a7f37; }
+.warn { color: #9a6700; }
+.bad { color: #cf222e; }
+#stats span { margin-right: 2em; }
+#error { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Scheduler</h1>
+<div id="error"></div>
+
+<h2>Offers</h2>
+<div id="stats"></div>
+
+<h2>Jobs</h2>
+<table>
+<thead><tr><th>Job</th><th>Type</th><th>Priority</th><th>Instances</th><th>Running</th><th>Ready</th><th>Unhealthy</th><th>Pending</th></tr></thead>
+<tbody id="jobs"></tbody>
+</table>
+
+<h2>Tasks</h2>
+<table>
+<thead><tr><th>Task</th><th>Job</th><th>Host</th><th>State</th><th>Healthy</th><th>Ready</th><th>Updated</th></tr></thead>
+<tbody id="tasks"></tbody>
+</table>
+
+<h2>Status updates</h2>
+<table>
+<thead><tr><th>Time</th><th>Task</th><th>State</th><th>Reason</th><th>Message</th></tr></thead>
+<tbody id="updates"></tbody>
+</table>
+
+<script>
+var maxUpdates = 50;
+
+function cell(row, text, cls) {
+  var td = document.createElement("td");
+  td.textContent = text === undefined || text === null || text === "" ? "-" : text;
+  if (cls) td.className = cls;
+  row.appendChild(td);
+}
+
+function stateClass(state) {
+  if (state === "TASK_RUNNING" || state === "TASK_FINISHED") return "ok";
+  if (state === "TASK_FAILED" || state === "TASK_LOST" || state === "TASK_ERROR" || state === "TASK_GONE") return "bad";
+  return "warn";
+}
+
+function get(path, render) {
+  fetch(path).then(function (resp) {
+    if (!resp.ok) throw new Error(path + ": " + resp.status);
+    return resp.json();
+  }).then(function (data) {
+    document.getElementById("error").textContent = "";
+    render(data);
+  }).catch(function (err) {
+    document.getElementById("error").textContent = err.message;
+  });
+}
+
+function refresh() {
+  get("/v1/offers", function (stats) {
+    var el = document.getElementById("stats");
+    el.innerHTML = "";
+    ["received", "accepted", "declined", "rescinded", "held"].forEach(function (name) {
+      var span = document.createElement("span");
+      span.textContent = name + ": " + stats[name];
+      el.appendChild(span);
+    });
+  });
+
+  get("/v1/jobs", function (jobs) {
+    var body = document.getElementById("jobs");
+    body.innerHTML = "";
+    jobs.forEach(function (job) {
+      var row = document.createElement("tr");
+      cell(row, job.id);
+      cell(row, job.type);
+      cell(row, String(job.priority));
+      cell(row, String(job.instances));
+      cell(row, String(job.running), job.running < job.instances ? "warn" : "ok");
+      cell(row, String(job.ready), job.ready < job.instances ? "warn" : "ok");
+      cell(row, String(job.unhealthy), job.unhealthy > 0 ? "bad" : "");
+      cell(row, String(job.pending), job.pending > 0 ? "warn" : "");
+      body.appendChild(row);
+    });
+  });
+
+  get("/v1/tasks?state=staging,starting,running,killing,unreachable", function (tasks) {
+    var body = document.getElementById("tasks");
+    body.innerHTML = "";
+    tasks.forEach(function (t) {
+      var row = document.createElement("tr");
+      cell(row, t.id);
+      cell(row, t.job_id);
+      cell(row, t.hostname);
+      cell(row, t.state, stateClass(t.state));
+      cell(row, t.healthy === undefined ? "" : String(t.healthy), t.healthy === false ? "bad" : "");
+      cell(row, String(t.ready));
+      cell(row, t.updated);
+      body.appendChild(row);
+    });
+  });
+}
+
+function follow() {
+  var events = new EventSource("/v1/events?type=status");
+  events.addEventListener("status", function (msg) {
+    var e = JSON.parse(msg.data);
+    var body = document.getElementById("updates");
+    var row = document.createElement("tr");
+    cell(row, e.time);
+    cell(row, e.task_id);
+    cell(row, e.state, stateClass(e.state));
+    cell(row, e.reason);
+    cell(row, e.message);
+    body.insertBefore(row, body.firstChild);
+    while (body.children.length > maxUpdates) body.removeChild(body.lastChild);
+  });
+  //A stream refused, for example after a restart of the scheduler, is
+  //opened again from now on
+  events.onerror = function () {
+    if (events.readyState === EventSource.CLOSED) setTimeout(follow, 5000);
+  };
+}
+
+refresh();
+setInterval(refresh, 5000);
+follow();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"golang.org/x/net/websocket"
+	"minimal-mesos-go-framework/example_scheduler"
+)
+
+//keepAliveInterval is how often an idle event stream gets a comment, so
+//the proxies in between don't close it
+const keepAliveInterval = 15 * time.Second
+
+//subscription reads the filters of the events, the job and type parameters
+//with the values separated by commas, and the sequence number to replay
+//them from, the since parameter or else the Last-Event-ID header an
+//EventSource sends when it reconnects
+func subscription(r *http.Request) (example_scheduler.EventFilter, int64, error) {
+	var filter example_scheduler.EventFilter
+	query := r.URL.Query()
+	if jobs := query.Get("job"); jobs != "" {
+		filter.JobIDs = strings.Split(jobs, ",")
+	}
+	if types := query.Get("type"); types != "" {
+		filter.Types = strings.Split(types, ",")
+		for _, t := range filter.Types {
+			if err := example_scheduler.ValidEventType(t); err != nil {
+				return filter, 0, err
+			}
+		}
+	}
+
+	since := query.Get("since")
+	if since == "" {
+		since = r.Header.Get("Last-Event-ID")
+	}
+	if since == "" {
+		return filter, 0, nil
+	}
+	seq, err := strconv.ParseInt(since, 10, 64)
+	if err != nil || seq < 0 {
+		return filter, 0, fmt.Errorf("invalid sequence number %q", since)
+	}
+
+	return filter, seq, nil
+}
+
+//events handles GET /v1/events, a stream of Server-Sent Events with the
+//events of the scheduler matching the filters, from the connection on or
+//replayed from a sequence number. Each event has its type as event name,
+//its sequence number as id and itself in JSON as data. The stream ends if
+//the client falls too far behind
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	filter, since, err := subscription(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	replay, events, cancel, err := s.scheduler.Subscribe(filter, since)
+	if err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	for i := range replay {
+		if err := writeEvent(w, &replay[i]); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, &e); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+//writeEvent writes the event as a Server-Sent Event. An event that can't
+//be encoded is skipped
+func writeEvent(w http.ResponseWriter, e *example_scheduler.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.WithError(err).Warnln("Unable to encode the event")
+		return nil
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Seq, e.Type, data)
+	return err
+}
+
+//eventsSocket handles GET /v1/events/ws, the events of GET /v1/events over
+//a WebSocket, each one a text message with the event in JSON. It takes the
+//same filters and sequence number to replay from. The messages of the
+//client are ignored, the socket is closed if it falls too far behind
+func (s *Server) eventsSocket(w http.ResponseWriter, r *http.Request) {
+	filter, since, err := subscription(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	//Subscribe before the handshake so the errors get a status code
+	replay, events, cancel, err := s.scheduler.Subscribe(filter, since)
+	if err != nil {
+		writeSchedulerError(w, err)
+		return
+	}
+	defer cancel()
+
+	//The browsers send no Authorization header on the upgrade, so a page of
+	//another site could read the events through the browser of someone
+	//who reaches the API. Only the pages of the API itself are accepted,
+	//and the clients that aren't browsers, which send no Origin
+	server := websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			origin, err := websocket.Origin(config, r)
+			if err != nil {
+				return err
+			}
+			if origin != nil && origin.Host != r.Host {
+				return fmt.Errorf("origin %s not allowed", origin)
+			}
+
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			//Reading is the only way to notice the client left
+			gone := make(chan struct{})
+			go func() {
+				var msg []byte
+				for websocket.Message.Receive(ws, &msg) == nil {
+				}
+				close(gone)
+			}()
+
+			for i := range replay {
+				if err := websocket.JSON.Send(ws, &replay[i]); err != nil {
+					return
+				}
+			}
+			for {
+				select {
+				case <-gone:
+					return
+				case e, ok := <-events:
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(ws, &e); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+	server.ServeHTTP(w, r)
+}
@@ -0,0 +1,404 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"minimal-mesos-go-framework/api/pb"
+	"minimal-mesos-go-framework/example_scheduler"
+)
+
+//grpcRoles is the role each gRPC method needs, like the REST call doing
+//the same. The methods not listed only read, they are for viewers
+var grpcRoles = map[string]string{
+	"/pb.Scheduler/SubmitJob":          RoleAdmin,
+	"/pb.Scheduler/UpdateJob":          RoleAdmin,
+	"/pb.Scheduler/RemoveJob":          RoleAdmin,
+	"/pb.Scheduler/KillTask":           RoleOperator,
+	"/pb.Scheduler/Scale":              RoleOperator,
+	"/pb.Scheduler/ApproveDeployment":  RoleOperator,
+	"/pb.Scheduler/RollbackDeployment": RoleOperator,
+}
+
+//GRPCServer is the management API over gRPC, the service of
+//api/pb/scheduler.proto. It takes the tokens and roles of the REST API,
+//the token in the authorization metadata as a bearer token
+type GRPCServer struct {
+	scheduler Scheduler
+
+	//The tokens required by the calls, nil for none, and whether the
+	//reading calls need one
+	tokens    *Tokens
+	openReads bool
+}
+
+//NewGRPCServer creates the gRPC API of the scheduler
+func NewGRPCServer(scheduler Scheduler) *GRPCServer {
+	return &GRPCServer{scheduler: scheduler}
+}
+
+//RequireTokens makes the gRPC API refuse the calls without one of the
+//tokens. With openReads the reading calls don't need one
+func (s *GRPCServer) RequireTokens(tokens *Tokens, openReads bool) {
+	s.tokens = tokens
+	s.openReads = openReads
+}
+
+//ListenAndServe serves the gRPC API on addr. It blocks until the server
+//fails
+func (s *GRPCServer) ListenAndServe(addr string) error {
+	log.Infof("Serving the gRPC API on %s", addr)
+	return s.serve(addr)
+}
+
+//ListenAndServeTLS serves the gRPC API over TLS with the certificate and
+//key files. With clientCA only the clients with a certificate signed by
+//one of its authorities are accepted
+func (s *GRPCServer) ListenAndServeTLS(addr, certFile, keyFile, clientCA string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if clientCA != "" {
+		pool, err := loadCertPool(clientCA)
+		if err != nil {
+			return err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	log.Infof("Serving the gRPC API over TLS on %s", addr)
+	return s.serve(addr, grpc.Creds(credentials.NewTLS(config)))
+}
+
+//serve listens on addr and serves the API with the options
+func (s *GRPCServer) serve(addr string, opts ...grpc.ServerOption) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	opts = append(opts, grpc.UnaryInterceptor(s.unary), grpc.StreamInterceptor(s.stream))
+	server := grpc.NewServer(opts...)
+	pb.RegisterSchedulerServer(server, s)
+	return server.Serve(listener)
+}
+
+//authorize checks the token of the call against the role its method
+//needs. Every call changing the scheduler is audited with its principal
+//and the gRPC status code of its answer, the ones refused too
+func (s *GRPCServer) authorize(ctx context.Context, method string, call func() error) error {
+	role, changes := grpcRoles[method]
+	if !changes {
+		role = RoleViewer
+	}
+
+	var id identity
+	if s.tokens != nil {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, header := range md.Get("authorization") {
+			if strings.HasPrefix(header, "Bearer ") {
+				id = s.tokens.identity(strings.TrimPrefix(header, "Bearer "))
+			}
+		}
+		if id.principal == "" && (changes || !s.openReads) {
+			return status.Error(codes.Unauthenticated, "a valid bearer token is required")
+		}
+	}
+
+	var err error
+	if id.principal != "" && roleRanks[id.role] < roleRanks[role] {
+		err = status.Errorf(codes.PermissionDenied, "%s is a %s, the call needs the %s role", id.principal, id.role, role)
+	} else {
+		err = call()
+	}
+	if changes {
+		s.scheduler.AuditRequest(id.principal, "GRPC", method, int(status.Code(err)))
+	}
+
+	return err
+}
+
+func (s *GRPCServer) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var resp interface{}
+	err := s.authorize(ctx, info.FullMethod, func() error {
+		var err error
+		resp, err = handler(ctx, req)
+		return err
+	})
+
+	return resp, err
+}
+
+func (s *GRPCServer) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return s.authorize(ss.Context(), info.FullMethod, func() error {
+		return handler(srv, ss)
+	})
+}
+
+//SubmitJob implements pb.SchedulerServer
+func (s *GRPCServer) SubmitJob(ctx context.Context, req *pb.JobSpec) (*pb.JobSpec, error) {
+	var job example_scheduler.JobSpec
+	if err := json.Unmarshal(req.Json, &job); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid job spec: "+err.Error())
+	}
+	if err := s.scheduler.SubmitJob(&job); err != nil {
+		return nil, grpcError(err)
+	}
+
+	return jobSpec(&job)
+}
+
+//UpdateJob implements pb.SchedulerServer
+func (s *GRPCServer) UpdateJob(ctx context.Context, req *pb.JobSpec) (*pb.JobSpec, error) {
+	var job example_scheduler.JobSpec
+	if err := json.Unmarshal(req.Json, &job); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid job spec: "+err.Error())
+	}
+	if err := s.scheduler.UpdateJob(&job); err != nil {
+		return nil, grpcError(err)
+	}
+
+	return jobSpec(&job)
+}
+
+//RemoveJob implements pb.SchedulerServer
+func (s *GRPCServer) RemoveJob(ctx context.Context, req *pb.JobRequest) (*pb.KillResponse, error) {
+	id, err := s.scheduler.RemoveJob(req.JobId)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.KillResponse{Id: id}, nil
+}
+
+//ListJobs implements pb.SchedulerServer
+func (s *GRPCServer) ListJobs(ctx context.Context, req *pb.ListRequest) (*pb.JobList, error) {
+	list := &pb.JobList{}
+	for _, j := range s.scheduler.Jobs() {
+		list.Jobs = append(list.Jobs, &pb.Job{
+			Id:        j.ID,
+			Type:      j.Type,
+			Priority:  int32(j.Priority),
+			Instances: int32(j.Instances),
+			Tasks:     int32(j.Tasks),
+			Running:   int32(j.Running),
+			Ready:     int32(j.Ready),
+			Unhealthy: int32(j.Unhealthy),
+			Pending:   int32(j.Pending),
+		})
+	}
+
+	return list, nil
+}
+
+//ListTasks implements pb.SchedulerServer
+func (s *GRPCServer) ListTasks(ctx context.Context, req *pb.ListRequest) (*pb.TaskList, error) {
+	list := &pb.TaskList{}
+	for _, t := range s.scheduler.Tasks() {
+		list.Tasks = append(list.Tasks, &pb.Task{
+			Id:            t.ID,
+			JobId:         t.JobID,
+			Hostname:      t.Hostname,
+			AgentId:       t.AgentID,
+			State:         t.State,
+			Launched:      timestampOf(t.Launched),
+			Updated:       timestampOf(t.Updated),
+			StatusMessage: t.StatusMessage,
+			Ready:         t.Ready,
+			Cpus:          t.Cpus,
+			Mem:           t.Mem,
+			Disk:          t.Disk,
+			Gpus:          t.Gpus,
+		})
+	}
+
+	return list, nil
+}
+
+//KillTask implements pb.SchedulerServer
+func (s *GRPCServer) KillTask(ctx context.Context, req *pb.KillTaskRequest) (*pb.KillResponse, error) {
+	id, err := s.scheduler.KillTask(req.TaskId, req.Scale)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.KillResponse{Id: id}, nil
+}
+
+//GetKill implements pb.SchedulerServer
+func (s *GRPCServer) GetKill(ctx context.Context, req *pb.KillRequest) (*pb.Kill, error) {
+	k, err := s.scheduler.Kill(req.Id)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.Kill{
+		Id:        k.ID,
+		JobId:     k.JobID,
+		TaskId:    k.TaskID,
+		Requested: timestampOf(k.Requested),
+		Tasks:     k.Tasks,
+		Remaining: k.Remaining,
+		Done:      k.Done,
+	}, nil
+}
+
+//Scale implements pb.SchedulerServer
+func (s *GRPCServer) Scale(ctx context.Context, req *pb.ScaleRequest) (*pb.ScaleResponse, error) {
+	id, err := s.scheduler.Scale(req.JobId, int(req.Instances), req.KillSelection)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.ScaleResponse{Instances: req.Instances, DeploymentId: id}, nil
+}
+
+//ListDeployments implements pb.SchedulerServer
+func (s *GRPCServer) ListDeployments(ctx context.Context, req *pb.ListRequest) (*pb.DeploymentList, error) {
+	list := &pb.DeploymentList{}
+	for _, d := range s.scheduler.Deployments() {
+		list.Deployments = append(list.Deployments, &pb.Deployment{
+			Id:         d.ID,
+			JobId:      d.JobID,
+			Version:    int32(d.Version),
+			Strategy:   d.Strategy,
+			State:      d.State,
+			Started:    timestampOf(d.Started),
+			Updated:    timestampOf(d.Updated),
+			OldTasks:   int32(d.OldTasks),
+			NewTasks:   int32(d.NewTasks),
+			ReadyTasks: int32(d.ReadyTasks),
+			Failures:   int32(d.Failures),
+			Rollback:   d.Rollback,
+		})
+	}
+
+	return list, nil
+}
+
+//ApproveDeployment implements pb.SchedulerServer
+func (s *GRPCServer) ApproveDeployment(ctx context.Context, req *pb.JobRequest) (*pb.Empty, error) {
+	if err := s.scheduler.ApproveDeployment(req.JobId); err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+//RollbackDeployment implements pb.SchedulerServer
+func (s *GRPCServer) RollbackDeployment(ctx context.Context, req *pb.JobRequest) (*pb.Empty, error) {
+	if err := s.scheduler.RollbackDeployment(req.JobId); err != nil {
+		return nil, grpcError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+//WatchEvents implements pb.SchedulerServer. The stream ends if the client
+//falls too far behind, like the one of GET /v1/events
+func (s *GRPCServer) WatchEvents(req *pb.EventFilter, stream pb.Scheduler_WatchEventsServer) error {
+	filter := example_scheduler.EventFilter{Types: req.Types, JobIDs: req.JobIds}
+	for _, t := range filter.Types {
+		if err := example_scheduler.ValidEventType(t); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	replay, events, cancel, err := s.scheduler.Subscribe(filter, req.Since)
+	if err != nil {
+		return grpcError(err)
+	}
+	defer cancel()
+
+	for i := range replay {
+		if err := stream.Send(eventOf(&replay[i])); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "the client fell too far behind the events")
+			}
+			if err := stream.Send(eventOf(&e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+//jobSpec encodes the job spec for an answer
+func jobSpec(job *example_scheduler.JobSpec) (*pb.JobSpec, error) {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &pb.JobSpec{Json: data}, nil
+}
+
+//eventOf converts an event of the scheduler
+func eventOf(e *example_scheduler.Event) *pb.Event {
+	return &pb.Event{
+		Seq:          e.Seq,
+		Time:         timestampOf(e.Time),
+		Type:         e.Type,
+		JobId:        e.JobID,
+		TaskId:       e.TaskID,
+		AgentId:      e.AgentID,
+		Hostname:     e.Hostname,
+		State:        e.State,
+		From:         e.From,
+		Message:      e.Message,
+		DeploymentId: e.DeploymentID,
+		Version:      int32(e.Version),
+	}
+}
+
+//timestampOf converts a time, nil for the zero time
+func timestampOf(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &timestamp.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+}
+
+//grpcError maps the errors of the scheduler operations to status codes,
+//the way writeSchedulerError does to HTTP status codes
+func grpcError(err error) error {
+	switch err {
+	case example_scheduler.ErrUnknownJob, example_scheduler.ErrUnknownTask, example_scheduler.ErrUnknownKill:
+		return status.Error(codes.NotFound, err.Error())
+	case example_scheduler.ErrJobExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case example_scheduler.ErrNotWaiting, example_scheduler.ErrNoPrevious, example_scheduler.ErrHasDependents:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case example_scheduler.ErrQueueFull:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case example_scheduler.ErrNotRegistered:
+		return status.Error(codes.Unavailable, err.Error())
+	case example_scheduler.ErrEventsGone:
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+
+	return status.Error(codes.InvalidArgument, err.Error())
+}
@@ -0,0 +1,719 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+//route is a pattern of the mux with its handler and the calls of the API
+//it answers, each one a method and a path of the OpenAPI document
+type route struct {
+	pattern    string
+	handler    http.HandlerFunc
+	operations []string
+}
+
+//routes are the patterns the API serves. Every call goes through them, so
+//checkOpenAPI keeps the OpenAPI document in line with what is served. The
+//pages of the dashboard aren't calls of the API, they have no operations
+func (s *Server) routes() []route {
+	return []route{
+		{"/v1/jobs", s.jobs, []string{"GET /v1/jobs", "POST /v1/jobs"}},
+		{"/v1/jobs/", s.job, []string{"PUT /v1/jobs/{id}", "DELETE /v1/jobs/{id}", "PUT /v1/jobs/{id}/scale",
+			"GET /v1/jobs/{id}/runs", "GET /v1/jobs/{id}/indexes", "POST /v1/jobs/{id}/retry"}},
+		{"/v1/tasks", s.tasks, []string{"GET /v1/tasks"}},
+		{"/v1/tasks/", s.task, []string{"DELETE /v1/tasks/{id}"}},
+		{"/v1/kills/", s.kill, []string{"GET /v1/kills/{id}"}},
+		{"/v1/hosts", s.hosts, []string{"GET /v1/hosts", "PUT /v1/hosts"}},
+		{"/v1/offers", s.offers, []string{"GET /v1/offers"}},
+		{"/v1/maintenance", s.maintenance, []string{"GET /v1/maintenance"}},
+		{"/v1/maintenance/", s.maintenanceAction, []string{"POST /v1/maintenance/pause", "POST /v1/maintenance/resume"}},
+		{"/v1/deployments", s.deployments, []string{"GET /v1/deployments"}},
+		{"/v1/deployments/", s.deployment, []string{"GET /v1/deployments/{id}", "POST /v1/deployments/{id}/approve",
+			"POST /v1/deployments/{id}/rollback"}},
+		{"/v1/queue", s.queue, []string{"GET /v1/queue"}},
+		{"/v1/pipeline", s.pipeline, []string{"GET /v1/pipeline"}},
+		{"/v1/audit", s.audit, []string{"GET /v1/audit"}},
+		{"/v1/events", s.events, []string{"GET /v1/events"}},
+		{"/v1/events/ws", s.eventsSocket, []string{"GET /v1/events/ws"}},
+		{"/v1/openapi.json", s.openAPI, []string{"GET /v1/openapi.json"}},
+		{"/healthz", s.probe(s.scheduler.Healthy), []string{"GET /healthz"}},
+		{"/readyz", s.probe(s.scheduler.Ready), []string{"GET /readyz"}},
+		{"/ui/", s.dashboard, nil},
+		{"/", s.root, nil},
+	}
+}
+
+//checkOpenAPI returns how the OpenAPI document differs from the routes: the
+//operations it doesn't describe, the ones it describes that no route
+//answers and the operations whose path isn't under their pattern
+func checkOpenAPI(routes []route) error {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(openAPIDocument), &doc); err != nil {
+		return fmt.Errorf("invalid OpenAPI document: %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range doc.Paths {
+		for method := range item {
+			if method != "parameters" {
+				documented[strings.ToUpper(method)+" "+path] = true
+			}
+		}
+	}
+
+	var problems []string
+	for _, r := range routes {
+		for _, op := range r.operations {
+			path := op[strings.Index(op, " ")+1:]
+			if path != r.pattern && !(strings.HasSuffix(r.pattern, "/") && strings.HasPrefix(path, r.pattern)) {
+				problems = append(problems, fmt.Sprintf("%s isn't under the pattern %s", op, r.pattern))
+			}
+			if !documented[op] {
+				problems = append(problems, op+" isn't documented")
+			}
+			delete(documented, op)
+		}
+	}
+	for op := range documented {
+		problems = append(problems, op+" is documented but not served")
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return fmt.Errorf("the OpenAPI document doesn't match the routes: %s", strings.Join(problems, ", "))
+}
+
+//openAPI handles GET /v1/openapi.json, the OpenAPI document of the API for
+//the tools generating clients or documentation
+func (s *Server) openAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(openAPIDocument))
+}
+
+//openAPIDocument describes the calls of the API with their parameters and
+//answers. The job specs are described by the README, the document only
+//lists their main fields. Its paths and methods must be the operations of
+//the routes, NewServer checks them
+const openAPIDocument = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "Mesos framework management API",
+    "version": "1"
+  },
+  "components": {
+    "securitySchemes": {
+      "bearer": {"type": "http", "scheme": "bearer"}
+    },
+    "parameters": {
+      "job": {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}},
+      "id": {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}
+    },
+    "responses": {
+      "Error": {
+        "description": "The call failed",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}
+      },
+      "Kill": {
+        "description": "The kill was requested, it is polled at the Location",
+        "headers": {"Location": {"schema": {"type": "string"}}},
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/KillResponse"}}}
+      }
+    },
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "properties": {"error": {"type": "string"}}
+      },
+      "Status": {
+        "type": "object",
+        "properties": {"status": {"type": "string"}}
+      },
+      "JobSpec": {
+        "type": "object",
+        "description": "A job, every field is described in the README",
+        "required": ["id"],
+        "properties": {
+          "id": {"type": "string"},
+          "type": {"type": "string", "enum": ["service", "batch"]},
+          "instances": {"type": "integer"},
+          "priority": {"type": "integer"},
+          "image": {"type": "string"},
+          "cmd": {"type": "string"},
+          "args": {"type": "array", "items": {"type": "string"}},
+          "cpus": {"type": "number"},
+          "mem": {"type": "number"},
+          "disk": {"type": "number"},
+          "gpus": {"type": "number"},
+          "env": {"type": "object", "additionalProperties": {"type": "string"}},
+          "depends_on": {"type": "array", "items": {"type": "string"}}
+        },
+        "additionalProperties": true
+      },
+      "BatchResponse": {
+        "type": "object",
+        "properties": {
+          "job_ids": {"type": "array", "items": {"type": "string"}},
+          "jobs": {"type": "array", "items": {"$ref": "#/components/schemas/JobSpec"}}
+        }
+      },
+      "JobSummary": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "type": {"type": "string"},
+          "priority": {"type": "integer"},
+          "instances": {"type": "integer"},
+          "tasks": {"type": "integer"},
+          "running": {"type": "integer"},
+          "ready": {"type": "integer"},
+          "unhealthy": {"type": "integer"},
+          "pending": {"type": "integer"}
+        }
+      },
+      "TaskSummary": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "job_id": {"type": "string"},
+          "hostname": {"type": "string"},
+          "agent_id": {"type": "string"},
+          "state": {"type": "string"},
+          "launched": {"type": "string", "format": "date-time"},
+          "updated": {"type": "string", "format": "date-time"},
+          "status_message": {"type": "string"},
+          "history": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "state": {"type": "string"},
+                "at": {"type": "string", "format": "date-time"}
+              }
+            }
+          },
+          "timed_out": {"type": "boolean"},
+          "healthy": {"type": "boolean"},
+          "ready": {"type": "boolean"},
+          "cpus": {"type": "number"},
+          "mem": {"type": "number"},
+          "disk": {"type": "number"},
+          "gpus": {"type": "number"},
+          "message": {
+            "type": "object",
+            "properties": {
+              "task_id": {"type": "string"},
+              "type": {"type": "string"},
+              "data": {"type": "string"},
+              "received": {"type": "string", "format": "date-time"}
+            }
+          }
+        }
+      },
+      "KillResponse": {
+        "type": "object",
+        "properties": {"id": {"type": "string"}}
+      },
+      "KillSummary": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "job_id": {"type": "string"},
+          "task_id": {"type": "string"},
+          "requested": {"type": "string", "format": "date-time"},
+          "tasks": {"type": "array", "items": {"type": "string"}},
+          "remaining": {"type": "array", "items": {"type": "string"}},
+          "done": {"type": "boolean"}
+        }
+      },
+      "ScaleRequest": {
+        "type": "object",
+        "required": ["instances"],
+        "properties": {
+          "instances": {"type": "integer"},
+          "kill_selection": {"type": "string", "enum": ["newest-first", "least-healthy-first"]}
+        }
+      },
+      "ScaleResponse": {
+        "type": "object",
+        "properties": {
+          "instances": {"type": "integer"},
+          "deployment_id": {"type": "string"}
+        }
+      },
+      "DeploymentSummary": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "job_id": {"type": "string"},
+          "version": {"type": "integer"},
+          "strategy": {"type": "string"},
+          "state": {"type": "string"},
+          "started": {"type": "string", "format": "date-time"},
+          "updated": {"type": "string", "format": "date-time"},
+          "old_tasks": {"type": "integer"},
+          "new_tasks": {"type": "integer"},
+          "ready_tasks": {"type": "integer"},
+          "failures": {"type": "integer"},
+          "rollback": {"type": "boolean"},
+          "canary": {
+            "type": "object",
+            "properties": {
+              "task_id": {"type": "string"},
+              "state": {"type": "string"},
+              "ready_since": {"type": "string", "format": "date-time"},
+              "promoted": {"type": "boolean"}
+            }
+          }
+        }
+      },
+      "QueueSummary": {
+        "type": "object",
+        "properties": {
+          "depth": {"type": "integer"},
+          "launches": {
+            "type": "array",
+            "items": {
+              "type": "object",
+              "properties": {
+                "job_id": {"type": "string"},
+                "priority": {"type": "integer"},
+                "enqueued": {"type": "string", "format": "date-time"},
+                "wait": {"type": "number"},
+                "attempts": {"type": "integer"},
+                "backoff": {"type": "boolean"}
+              }
+            }
+          },
+          "oldest_wait": {"type": "number"},
+          "launched": {"type": "integer"},
+          "mean_wait": {"type": "number"},
+          "max_wait": {"type": "number"}
+        }
+      },
+      "CronRunSummary": {
+        "type": "object",
+        "properties": {
+          "run": {"type": "integer"},
+          "scheduled": {"type": "string", "format": "date-time"},
+          "ended": {"type": "string", "format": "date-time"},
+          "state": {"type": "string"},
+          "tasks": {"type": "array", "items": {"type": "string"}},
+          "succeeded": {"type": "integer"}
+        }
+      },
+      "IndexSummary": {
+        "type": "object",
+        "properties": {
+          "index": {"type": "integer"},
+          "state": {"type": "string", "enum": ["pending", "running", "succeeded", "failed"]},
+          "task_id": {"type": "string"},
+          "attempts": {"type": "integer"}
+        }
+      },
+      "PipelineSummary": {
+        "type": "object",
+        "properties": {
+          "job_id": {"type": "string"},
+          "depends_on": {"type": "array", "items": {"type": "string"}},
+          "state": {"type": "string"},
+          "upstream": {"type": "string"},
+          "succeeded": {"type": "boolean"}
+        }
+      },
+      "HostFilter": {
+        "type": "object",
+        "properties": {
+          "whitelist": {"type": "array", "items": {"type": "string"}},
+          "blacklist": {"type": "array", "items": {"type": "string"}}
+        }
+      },
+      "Maintenance": {
+        "type": "object",
+        "properties": {
+          "paused": {"type": "boolean"},
+          "reason": {"type": "string"},
+          "since": {"type": "string", "format": "date-time"}
+        }
+      },
+      "PauseRequest": {
+        "type": "object",
+        "properties": {"reason": {"type": "string"}}
+      },
+      "OfferStats": {
+        "type": "object",
+        "properties": {
+          "received": {"type": "integer"},
+          "accepted": {"type": "integer"},
+          "declined": {"type": "integer"},
+          "rescinded": {"type": "integer"},
+          "held": {"type": "integer"}
+        }
+      },
+      "AuditEntry": {
+        "type": "object",
+        "properties": {
+          "seq": {"type": "integer"},
+          "time": {"type": "string", "format": "date-time"},
+          "action": {"type": "string"},
+          "job_id": {"type": "string"},
+          "task_id": {"type": "string"},
+          "agent_id": {"type": "string"},
+          "hostname": {"type": "string"},
+          "offer_ids": {"type": "array", "items": {"type": "string"}},
+          "principal": {"type": "string"},
+          "reason": {"type": "string"}
+        }
+      },
+      "Event": {
+        "type": "object",
+        "properties": {
+          "seq": {"type": "integer"},
+          "time": {"type": "string", "format": "date-time"},
+          "type": {"type": "string", "enum": ["status", "accept", "decline", "deployment", "agent"]},
+          "job_id": {"type": "string"},
+          "task_id": {"type": "string"},
+          "agent_id": {"type": "string"},
+          "hostname": {"type": "string"},
+          "state": {"type": "string"},
+          "from": {"type": "string"},
+          "message": {"type": "string"},
+          "source": {"type": "string"},
+          "healthy": {"type": "boolean"},
+          "offer_ids": {"type": "array", "items": {"type": "string"}},
+          "reason": {"type": "string"},
+          "deployment_id": {"type": "string"},
+          "version": {"type": "integer"},
+          "strategy": {"type": "string"}
+        }
+      }
+    }
+  },
+  "security": [{"bearer": []}],
+  "paths": {
+    "/v1/jobs": {
+      "get": {
+        "summary": "List the jobs with the state of their instances",
+        "responses": {
+          "200": {"description": "The jobs", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/JobSummary"}}}}}
+        }
+      },
+      "post": {
+        "summary": "Submit a job, or an array of jobs all at once or none",
+        "parameters": [
+          {"name": "Idempotency-Key", "in": "header", "description": "Key of the submission, its retries with the same spec within a day answer the job submitted with 200", "schema": {"type": "string", "maxLength": 255}}
+        ],
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"oneOf": [
+          {"$ref": "#/components/schemas/JobSpec"},
+          {"type": "array", "items": {"$ref": "#/components/schemas/JobSpec"}}
+        ]}}}},
+        "responses": {
+          "200": {"description": "The job already submitted with the idempotency key", "headers": {"Idempotent-Replayed": {"schema": {"type": "string"}}}, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/JobSpec"}}}},
+          "201": {"description": "The job submitted, or the jobs of the array", "content": {"application/json": {"schema": {"oneOf": [
+            {"$ref": "#/components/schemas/JobSpec"},
+            {"$ref": "#/components/schemas/BatchResponse"}
+          ]}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"},
+          "422": {"$ref": "#/components/responses/Error"},
+          "429": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/jobs/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/job"}],
+      "put": {
+        "summary": "Deploy a new spec of the job",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/JobSpec"}}}},
+        "responses": {
+          "200": {"description": "The job updated", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/JobSpec"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "delete": {
+        "summary": "Kill the tasks of the job and remove it",
+        "responses": {
+          "202": {"$ref": "#/components/responses/Kill"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/jobs/{id}/scale": {
+      "parameters": [{"$ref": "#/components/parameters/job"}],
+      "put": {
+        "summary": "Change the number of instances of the job",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ScaleRequest"}}}},
+        "responses": {
+          "200": {"description": "The deployment scaling the job", "headers": {"Location": {"schema": {"type": "string"}}}, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ScaleResponse"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/jobs/{id}/runs": {
+      "parameters": [{"$ref": "#/components/parameters/job"}],
+      "get": {
+        "summary": "List the runs of a cron job",
+        "responses": {
+          "200": {"description": "The runs", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/CronRunSummary"}}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/jobs/{id}/indexes": {
+      "parameters": [{"$ref": "#/components/parameters/job"}],
+      "get": {
+        "summary": "List the indexes of an indexed job",
+        "responses": {
+          "200": {"description": "The indexes", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/IndexSummary"}}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/jobs/{id}/retry": {
+      "parameters": [{"$ref": "#/components/parameters/job"}],
+      "post": {
+        "summary": "Launch again the failed indexes of an indexed job",
+        "responses": {
+          "200": {"description": "The indexes retried", "content": {"application/json": {"schema": {"type": "array", "items": {"type": "integer"}}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/tasks": {
+      "get": {
+        "summary": "List the tasks",
+        "parameters": [
+          {"name": "job", "in": "query", "schema": {"type": "string"}},
+          {"name": "state", "in": "query", "description": "States separated by commas, like running or TASK_RUNNING", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {"description": "The tasks", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/TaskSummary"}}}}}
+        }
+      }
+    },
+    "/v1/tasks/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/id"}],
+      "delete": {
+        "summary": "Kill a task, its job launches a replacement unless scale is set",
+        "parameters": [{"name": "scale", "in": "query", "schema": {"type": "boolean"}}],
+        "responses": {
+          "202": {"$ref": "#/components/responses/Kill"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/kills/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/id"}],
+      "get": {
+        "summary": "Follow a kill until its tasks end",
+        "responses": {
+          "200": {"description": "The kill", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/KillSummary"}}}},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/deployments": {
+      "get": {
+        "summary": "List the last deployment of every job",
+        "responses": {
+          "200": {"description": "The deployments", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/DeploymentSummary"}}}}}
+        }
+      }
+    },
+    "/v1/deployments/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/id"}],
+      "get": {
+        "summary": "Get a deployment by its ID, the job and its version like web.3",
+        "responses": {
+          "200": {"description": "The deployment", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/DeploymentSummary"}}}},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/deployments/{id}/approve": {
+      "parameters": [{"$ref": "#/components/parameters/job"}],
+      "post": {
+        "summary": "Approve the cutover of the deployment of the job",
+        "responses": {
+          "202": {"description": "The cutover started"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/deployments/{id}/rollback": {
+      "parameters": [{"$ref": "#/components/parameters/job"}],
+      "post": {
+        "summary": "Roll the job back to its previous spec",
+        "responses": {
+          "202": {"description": "The rollback started"},
+          "404": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/queue": {
+      "get": {
+        "summary": "Describe the launch queue",
+        "responses": {
+          "200": {"description": "The queue", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/QueueSummary"}}}}
+        }
+      }
+    },
+    "/v1/pipeline": {
+      "get": {
+        "summary": "List the jobs with dependencies and their state",
+        "responses": {
+          "200": {"description": "The pipeline", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/PipelineSummary"}}}}}
+        }
+      }
+    },
+    "/v1/hosts": {
+      "get": {
+        "summary": "Get the host filter",
+        "responses": {
+          "200": {"description": "The host filter", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HostFilter"}}}}
+        }
+      },
+      "put": {
+        "summary": "Set the host filter",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HostFilter"}}}},
+        "responses": {
+          "200": {"description": "The host filter", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HostFilter"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/offers": {
+      "get": {
+        "summary": "Get the statistics of the offers",
+        "responses": {
+          "200": {"description": "The statistics", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/OfferStats"}}}}
+        }
+      }
+    },
+    "/v1/maintenance": {
+      "get": {
+        "summary": "Tell if the scheduling is paused",
+        "responses": {
+          "200": {"description": "The state of the pause", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Maintenance"}}}}
+        }
+      }
+    },
+    "/v1/maintenance/pause": {
+      "post": {
+        "summary": "Pause the scheduling: suppress the offers and launch no task until resumed",
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/PauseRequest"}}}},
+        "responses": {
+          "200": {"description": "The state of the pause", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Maintenance"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/maintenance/resume": {
+      "post": {
+        "summary": "Resume the scheduling and revive the offers",
+        "responses": {
+          "200": {"description": "The state of the pause", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Maintenance"}}}}
+        }
+      }
+    },
+    "/v1/audit": {
+      "get": {
+        "summary": "List the audit entries",
+        "parameters": [
+          {"name": "action", "in": "query", "schema": {"type": "string"}},
+          {"name": "job", "in": "query", "schema": {"type": "string"}},
+          {"name": "task", "in": "query", "schema": {"type": "string"}},
+          {"name": "agent", "in": "query", "schema": {"type": "string"}},
+          {"name": "since", "in": "query", "schema": {"type": "string", "format": "date-time"}},
+          {"name": "limit", "in": "query", "schema": {"type": "integer"}}
+        ],
+        "responses": {
+          "200": {"description": "The entries", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/AuditEntry"}}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/events": {
+      "get": {
+        "summary": "Stream the events as Server-Sent Events, /v1/events/ws streams them over a WebSocket",
+        "parameters": [
+          {"name": "job", "in": "query", "description": "Jobs separated by commas", "schema": {"type": "string"}},
+          {"name": "type", "in": "query", "description": "Types separated by commas", "schema": {"type": "string"}},
+          {"name": "since", "in": "query", "description": "Replay the events after this sequence number", "schema": {"type": "integer"}},
+          {"name": "Last-Event-ID", "in": "header", "schema": {"type": "integer"}}
+        ],
+        "responses": {
+          "200": {"description": "The events, each one as the data of a message", "content": {"text/event-stream": {"schema": {"$ref": "#/components/schemas/Event"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "410": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/events/ws": {
+      "get": {
+        "summary": "Stream the events over a WebSocket, one JSON message per event",
+        "parameters": [
+          {"name": "job", "in": "query", "description": "Jobs separated by commas", "schema": {"type": "string"}},
+          {"name": "type", "in": "query", "description": "Types separated by commas", "schema": {"type": "string"}},
+          {"name": "since", "in": "query", "description": "Replay the events after this sequence number", "schema": {"type": "integer"}}
+        ],
+        "responses": {
+          "101": {"description": "The WebSocket, each message an event", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Event"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "410": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/openapi.json": {
+      "get": {
+        "summary": "This document",
+        "security": [],
+        "responses": {
+          "200": {"description": "The OpenAPI document of the API", "content": {"application/json": {}}}
+        }
+      }
+    },
+    "/healthz": {
+      "get": {
+        "summary": "Liveness of the scheduler",
+        "security": [],
+        "responses": {
+          "200": {"description": "Alive", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Status"}}}},
+          "503": {"description": "Not alive", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Status"}}}}
+        }
+      }
+    },
+    "/readyz": {
+      "get": {
+        "summary": "Readiness of the scheduler",
+        "security": [],
+        "responses": {
+          "200": {"description": "Ready", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Status"}}}},
+          "503": {"description": "Not ready", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Status"}}}}
+        }
+      }
+    }
+  }
+}
+`
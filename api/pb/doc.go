@@ -0,0 +1,6 @@
+//Package pb holds the gRPC definition of the management API of the
+//scheduler, in scheduler.proto. The Go server interface and client are
+//generated from it with protoc and its Go plugin
+package pb
+
+//go:generate protoc --go_out=plugins=grpc:. scheduler.proto
@@ -0,0 +1,1752 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: scheduler.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Empty struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
+func (*Empty) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{0}
+}
+
+func (m *Empty) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Empty.Unmarshal(m, b)
+}
+func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+}
+func (m *Empty) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Empty.Merge(m, src)
+}
+func (m *Empty) XXX_Size() int {
+	return xxx_messageInfo_Empty.Size(m)
+}
+func (m *Empty) XXX_DiscardUnknown() {
+	xxx_messageInfo_Empty.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Empty proto.InternalMessageInfo
+
+type ListRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRequest) Reset()         { *m = ListRequest{} }
+func (m *ListRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()    {}
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{1}
+}
+
+func (m *ListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
+}
+func (m *ListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRequest.Merge(m, src)
+}
+func (m *ListRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRequest.Size(m)
+}
+func (m *ListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRequest proto.InternalMessageInfo
+
+type JobRequest struct {
+	JobId                string   `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobRequest) Reset()         { *m = JobRequest{} }
+func (m *JobRequest) String() string { return proto.CompactTextString(m) }
+func (*JobRequest) ProtoMessage()    {}
+func (*JobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{2}
+}
+
+func (m *JobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobRequest.Unmarshal(m, b)
+}
+func (m *JobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobRequest.Marshal(b, m, deterministic)
+}
+func (m *JobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobRequest.Merge(m, src)
+}
+func (m *JobRequest) XXX_Size() int {
+	return xxx_messageInfo_JobRequest.Size(m)
+}
+func (m *JobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobRequest proto.InternalMessageInfo
+
+func (m *JobRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+// JobSpec is a job spec encoded in JSON
+type JobSpec struct {
+	Json                 []byte   `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobSpec) Reset()         { *m = JobSpec{} }
+func (m *JobSpec) String() string { return proto.CompactTextString(m) }
+func (*JobSpec) ProtoMessage()    {}
+func (*JobSpec) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{3}
+}
+
+func (m *JobSpec) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobSpec.Unmarshal(m, b)
+}
+func (m *JobSpec) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobSpec.Marshal(b, m, deterministic)
+}
+func (m *JobSpec) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobSpec.Merge(m, src)
+}
+func (m *JobSpec) XXX_Size() int {
+	return xxx_messageInfo_JobSpec.Size(m)
+}
+func (m *JobSpec) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobSpec.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobSpec proto.InternalMessageInfo
+
+func (m *JobSpec) GetJson() []byte {
+	if m != nil {
+		return m.Json
+	}
+	return nil
+}
+
+type Job struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Priority             int32    `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`
+	Instances            int32    `protobuf:"varint,4,opt,name=instances,proto3" json:"instances,omitempty"`
+	Tasks                int32    `protobuf:"varint,5,opt,name=tasks,proto3" json:"tasks,omitempty"`
+	Running              int32    `protobuf:"varint,6,opt,name=running,proto3" json:"running,omitempty"`
+	Ready                int32    `protobuf:"varint,7,opt,name=ready,proto3" json:"ready,omitempty"`
+	Unhealthy            int32    `protobuf:"varint,8,opt,name=unhealthy,proto3" json:"unhealthy,omitempty"`
+	Pending              int32    `protobuf:"varint,9,opt,name=pending,proto3" json:"pending,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Job) Reset()         { *m = Job{} }
+func (m *Job) String() string { return proto.CompactTextString(m) }
+func (*Job) ProtoMessage()    {}
+func (*Job) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{4}
+}
+
+func (m *Job) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Job.Unmarshal(m, b)
+}
+func (m *Job) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Job.Marshal(b, m, deterministic)
+}
+func (m *Job) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Job.Merge(m, src)
+}
+func (m *Job) XXX_Size() int {
+	return xxx_messageInfo_Job.Size(m)
+}
+func (m *Job) XXX_DiscardUnknown() {
+	xxx_messageInfo_Job.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Job proto.InternalMessageInfo
+
+func (m *Job) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Job) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Job) GetPriority() int32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *Job) GetInstances() int32 {
+	if m != nil {
+		return m.Instances
+	}
+	return 0
+}
+
+func (m *Job) GetTasks() int32 {
+	if m != nil {
+		return m.Tasks
+	}
+	return 0
+}
+
+func (m *Job) GetRunning() int32 {
+	if m != nil {
+		return m.Running
+	}
+	return 0
+}
+
+func (m *Job) GetReady() int32 {
+	if m != nil {
+		return m.Ready
+	}
+	return 0
+}
+
+func (m *Job) GetUnhealthy() int32 {
+	if m != nil {
+		return m.Unhealthy
+	}
+	return 0
+}
+
+func (m *Job) GetPending() int32 {
+	if m != nil {
+		return m.Pending
+	}
+	return 0
+}
+
+type JobList struct {
+	Jobs                 []*Job   `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobList) Reset()         { *m = JobList{} }
+func (m *JobList) String() string { return proto.CompactTextString(m) }
+func (*JobList) ProtoMessage()    {}
+func (*JobList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{5}
+}
+
+func (m *JobList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobList.Unmarshal(m, b)
+}
+func (m *JobList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobList.Marshal(b, m, deterministic)
+}
+func (m *JobList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobList.Merge(m, src)
+}
+func (m *JobList) XXX_Size() int {
+	return xxx_messageInfo_JobList.Size(m)
+}
+func (m *JobList) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobList proto.InternalMessageInfo
+
+func (m *JobList) GetJobs() []*Job {
+	if m != nil {
+		return m.Jobs
+	}
+	return nil
+}
+
+type Task struct {
+	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	JobId                string               `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Hostname             string               `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	AgentId              string               `protobuf:"bytes,4,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	State                string               `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	Launched             *timestamp.Timestamp `protobuf:"bytes,6,opt,name=launched,proto3" json:"launched,omitempty"`
+	Updated              *timestamp.Timestamp `protobuf:"bytes,7,opt,name=updated,proto3" json:"updated,omitempty"`
+	StatusMessage        string               `protobuf:"bytes,8,opt,name=status_message,json=statusMessage,proto3" json:"status_message,omitempty"`
+	Ready                bool                 `protobuf:"varint,9,opt,name=ready,proto3" json:"ready,omitempty"`
+	Cpus                 float64              `protobuf:"fixed64,10,opt,name=cpus,proto3" json:"cpus,omitempty"`
+	Mem                  float64              `protobuf:"fixed64,11,opt,name=mem,proto3" json:"mem,omitempty"`
+	Disk                 float64              `protobuf:"fixed64,12,opt,name=disk,proto3" json:"disk,omitempty"`
+	Gpus                 float64              `protobuf:"fixed64,13,opt,name=gpus,proto3" json:"gpus,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Task) Reset()         { *m = Task{} }
+func (m *Task) String() string { return proto.CompactTextString(m) }
+func (*Task) ProtoMessage()    {}
+func (*Task) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{6}
+}
+
+func (m *Task) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Task.Unmarshal(m, b)
+}
+func (m *Task) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Task.Marshal(b, m, deterministic)
+}
+func (m *Task) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Task.Merge(m, src)
+}
+func (m *Task) XXX_Size() int {
+	return xxx_messageInfo_Task.Size(m)
+}
+func (m *Task) XXX_DiscardUnknown() {
+	xxx_messageInfo_Task.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Task proto.InternalMessageInfo
+
+func (m *Task) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Task) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *Task) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *Task) GetAgentId() string {
+	if m != nil {
+		return m.AgentId
+	}
+	return ""
+}
+
+func (m *Task) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Task) GetLaunched() *timestamp.Timestamp {
+	if m != nil {
+		return m.Launched
+	}
+	return nil
+}
+
+func (m *Task) GetUpdated() *timestamp.Timestamp {
+	if m != nil {
+		return m.Updated
+	}
+	return nil
+}
+
+func (m *Task) GetStatusMessage() string {
+	if m != nil {
+		return m.StatusMessage
+	}
+	return ""
+}
+
+func (m *Task) GetReady() bool {
+	if m != nil {
+		return m.Ready
+	}
+	return false
+}
+
+func (m *Task) GetCpus() float64 {
+	if m != nil {
+		return m.Cpus
+	}
+	return 0
+}
+
+func (m *Task) GetMem() float64 {
+	if m != nil {
+		return m.Mem
+	}
+	return 0
+}
+
+func (m *Task) GetDisk() float64 {
+	if m != nil {
+		return m.Disk
+	}
+	return 0
+}
+
+func (m *Task) GetGpus() float64 {
+	if m != nil {
+		return m.Gpus
+	}
+	return 0
+}
+
+type TaskList struct {
+	Tasks                []*Task  `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskList) Reset()         { *m = TaskList{} }
+func (m *TaskList) String() string { return proto.CompactTextString(m) }
+func (*TaskList) ProtoMessage()    {}
+func (*TaskList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{7}
+}
+
+func (m *TaskList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskList.Unmarshal(m, b)
+}
+func (m *TaskList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskList.Marshal(b, m, deterministic)
+}
+func (m *TaskList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskList.Merge(m, src)
+}
+func (m *TaskList) XXX_Size() int {
+	return xxx_messageInfo_TaskList.Size(m)
+}
+func (m *TaskList) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskList proto.InternalMessageInfo
+
+func (m *TaskList) GetTasks() []*Task {
+	if m != nil {
+		return m.Tasks
+	}
+	return nil
+}
+
+type KillTaskRequest struct {
+	TaskId string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// scale lowers the instances of the job instead of replacing the task
+	Scale                bool     `protobuf:"varint,2,opt,name=scale,proto3" json:"scale,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KillTaskRequest) Reset()         { *m = KillTaskRequest{} }
+func (m *KillTaskRequest) String() string { return proto.CompactTextString(m) }
+func (*KillTaskRequest) ProtoMessage()    {}
+func (*KillTaskRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{8}
+}
+
+func (m *KillTaskRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KillTaskRequest.Unmarshal(m, b)
+}
+func (m *KillTaskRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KillTaskRequest.Marshal(b, m, deterministic)
+}
+func (m *KillTaskRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KillTaskRequest.Merge(m, src)
+}
+func (m *KillTaskRequest) XXX_Size() int {
+	return xxx_messageInfo_KillTaskRequest.Size(m)
+}
+func (m *KillTaskRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KillTaskRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KillTaskRequest proto.InternalMessageInfo
+
+func (m *KillTaskRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *KillTaskRequest) GetScale() bool {
+	if m != nil {
+		return m.Scale
+	}
+	return false
+}
+
+type KillRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KillRequest) Reset()         { *m = KillRequest{} }
+func (m *KillRequest) String() string { return proto.CompactTextString(m) }
+func (*KillRequest) ProtoMessage()    {}
+func (*KillRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{9}
+}
+
+func (m *KillRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KillRequest.Unmarshal(m, b)
+}
+func (m *KillRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KillRequest.Marshal(b, m, deterministic)
+}
+func (m *KillRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KillRequest.Merge(m, src)
+}
+func (m *KillRequest) XXX_Size() int {
+	return xxx_messageInfo_KillRequest.Size(m)
+}
+func (m *KillRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KillRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KillRequest proto.InternalMessageInfo
+
+func (m *KillRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type KillResponse struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KillResponse) Reset()         { *m = KillResponse{} }
+func (m *KillResponse) String() string { return proto.CompactTextString(m) }
+func (*KillResponse) ProtoMessage()    {}
+func (*KillResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{10}
+}
+
+func (m *KillResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KillResponse.Unmarshal(m, b)
+}
+func (m *KillResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KillResponse.Marshal(b, m, deterministic)
+}
+func (m *KillResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KillResponse.Merge(m, src)
+}
+func (m *KillResponse) XXX_Size() int {
+	return xxx_messageInfo_KillResponse.Size(m)
+}
+func (m *KillResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_KillResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KillResponse proto.InternalMessageInfo
+
+func (m *KillResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type Kill struct {
+	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	JobId                string               `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	TaskId               string               `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Requested            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=requested,proto3" json:"requested,omitempty"`
+	Tasks                []string             `protobuf:"bytes,5,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	Remaining            []string             `protobuf:"bytes,6,rep,name=remaining,proto3" json:"remaining,omitempty"`
+	Done                 bool                 `protobuf:"varint,7,opt,name=done,proto3" json:"done,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Kill) Reset()         { *m = Kill{} }
+func (m *Kill) String() string { return proto.CompactTextString(m) }
+func (*Kill) ProtoMessage()    {}
+func (*Kill) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{11}
+}
+
+func (m *Kill) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Kill.Unmarshal(m, b)
+}
+func (m *Kill) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Kill.Marshal(b, m, deterministic)
+}
+func (m *Kill) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Kill.Merge(m, src)
+}
+func (m *Kill) XXX_Size() int {
+	return xxx_messageInfo_Kill.Size(m)
+}
+func (m *Kill) XXX_DiscardUnknown() {
+	xxx_messageInfo_Kill.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Kill proto.InternalMessageInfo
+
+func (m *Kill) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Kill) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *Kill) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *Kill) GetRequested() *timestamp.Timestamp {
+	if m != nil {
+		return m.Requested
+	}
+	return nil
+}
+
+func (m *Kill) GetTasks() []string {
+	if m != nil {
+		return m.Tasks
+	}
+	return nil
+}
+
+func (m *Kill) GetRemaining() []string {
+	if m != nil {
+		return m.Remaining
+	}
+	return nil
+}
+
+func (m *Kill) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
+type ScaleRequest struct {
+	JobId                string   `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Instances            int32    `protobuf:"varint,2,opt,name=instances,proto3" json:"instances,omitempty"`
+	KillSelection        string   `protobuf:"bytes,3,opt,name=kill_selection,json=killSelection,proto3" json:"kill_selection,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScaleRequest) Reset()         { *m = ScaleRequest{} }
+func (m *ScaleRequest) String() string { return proto.CompactTextString(m) }
+func (*ScaleRequest) ProtoMessage()    {}
+func (*ScaleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{12}
+}
+
+func (m *ScaleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScaleRequest.Unmarshal(m, b)
+}
+func (m *ScaleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScaleRequest.Marshal(b, m, deterministic)
+}
+func (m *ScaleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScaleRequest.Merge(m, src)
+}
+func (m *ScaleRequest) XXX_Size() int {
+	return xxx_messageInfo_ScaleRequest.Size(m)
+}
+func (m *ScaleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScaleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScaleRequest proto.InternalMessageInfo
+
+func (m *ScaleRequest) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *ScaleRequest) GetInstances() int32 {
+	if m != nil {
+		return m.Instances
+	}
+	return 0
+}
+
+func (m *ScaleRequest) GetKillSelection() string {
+	if m != nil {
+		return m.KillSelection
+	}
+	return ""
+}
+
+type ScaleResponse struct {
+	Instances            int32    `protobuf:"varint,1,opt,name=instances,proto3" json:"instances,omitempty"`
+	DeploymentId         string   `protobuf:"bytes,2,opt,name=deployment_id,json=deploymentId,proto3" json:"deployment_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScaleResponse) Reset()         { *m = ScaleResponse{} }
+func (m *ScaleResponse) String() string { return proto.CompactTextString(m) }
+func (*ScaleResponse) ProtoMessage()    {}
+func (*ScaleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{13}
+}
+
+func (m *ScaleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScaleResponse.Unmarshal(m, b)
+}
+func (m *ScaleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScaleResponse.Marshal(b, m, deterministic)
+}
+func (m *ScaleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScaleResponse.Merge(m, src)
+}
+func (m *ScaleResponse) XXX_Size() int {
+	return xxx_messageInfo_ScaleResponse.Size(m)
+}
+func (m *ScaleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScaleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScaleResponse proto.InternalMessageInfo
+
+func (m *ScaleResponse) GetInstances() int32 {
+	if m != nil {
+		return m.Instances
+	}
+	return 0
+}
+
+func (m *ScaleResponse) GetDeploymentId() string {
+	if m != nil {
+		return m.DeploymentId
+	}
+	return ""
+}
+
+type Deployment struct {
+	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	JobId                string               `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Version              int32                `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Strategy             string               `protobuf:"bytes,4,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	State                string               `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	Started              *timestamp.Timestamp `protobuf:"bytes,6,opt,name=started,proto3" json:"started,omitempty"`
+	Updated              *timestamp.Timestamp `protobuf:"bytes,7,opt,name=updated,proto3" json:"updated,omitempty"`
+	OldTasks             int32                `protobuf:"varint,8,opt,name=old_tasks,json=oldTasks,proto3" json:"old_tasks,omitempty"`
+	NewTasks             int32                `protobuf:"varint,9,opt,name=new_tasks,json=newTasks,proto3" json:"new_tasks,omitempty"`
+	ReadyTasks           int32                `protobuf:"varint,10,opt,name=ready_tasks,json=readyTasks,proto3" json:"ready_tasks,omitempty"`
+	Failures             int32                `protobuf:"varint,11,opt,name=failures,proto3" json:"failures,omitempty"`
+	Rollback             bool                 `protobuf:"varint,12,opt,name=rollback,proto3" json:"rollback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Deployment) Reset()         { *m = Deployment{} }
+func (m *Deployment) String() string { return proto.CompactTextString(m) }
+func (*Deployment) ProtoMessage()    {}
+func (*Deployment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{14}
+}
+
+func (m *Deployment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Deployment.Unmarshal(m, b)
+}
+func (m *Deployment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Deployment.Marshal(b, m, deterministic)
+}
+func (m *Deployment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Deployment.Merge(m, src)
+}
+func (m *Deployment) XXX_Size() int {
+	return xxx_messageInfo_Deployment.Size(m)
+}
+func (m *Deployment) XXX_DiscardUnknown() {
+	xxx_messageInfo_Deployment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Deployment proto.InternalMessageInfo
+
+func (m *Deployment) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Deployment) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *Deployment) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Deployment) GetStrategy() string {
+	if m != nil {
+		return m.Strategy
+	}
+	return ""
+}
+
+func (m *Deployment) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Deployment) GetStarted() *timestamp.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *Deployment) GetUpdated() *timestamp.Timestamp {
+	if m != nil {
+		return m.Updated
+	}
+	return nil
+}
+
+func (m *Deployment) GetOldTasks() int32 {
+	if m != nil {
+		return m.OldTasks
+	}
+	return 0
+}
+
+func (m *Deployment) GetNewTasks() int32 {
+	if m != nil {
+		return m.NewTasks
+	}
+	return 0
+}
+
+func (m *Deployment) GetReadyTasks() int32 {
+	if m != nil {
+		return m.ReadyTasks
+	}
+	return 0
+}
+
+func (m *Deployment) GetFailures() int32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *Deployment) GetRollback() bool {
+	if m != nil {
+		return m.Rollback
+	}
+	return false
+}
+
+type DeploymentList struct {
+	Deployments          []*Deployment `protobuf:"bytes,1,rep,name=deployments,proto3" json:"deployments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *DeploymentList) Reset()         { *m = DeploymentList{} }
+func (m *DeploymentList) String() string { return proto.CompactTextString(m) }
+func (*DeploymentList) ProtoMessage()    {}
+func (*DeploymentList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{15}
+}
+
+func (m *DeploymentList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeploymentList.Unmarshal(m, b)
+}
+func (m *DeploymentList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeploymentList.Marshal(b, m, deterministic)
+}
+func (m *DeploymentList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeploymentList.Merge(m, src)
+}
+func (m *DeploymentList) XXX_Size() int {
+	return xxx_messageInfo_DeploymentList.Size(m)
+}
+func (m *DeploymentList) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeploymentList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeploymentList proto.InternalMessageInfo
+
+func (m *DeploymentList) GetDeployments() []*Deployment {
+	if m != nil {
+		return m.Deployments
+	}
+	return nil
+}
+
+type EventFilter struct {
+	Types  []string `protobuf:"bytes,1,rep,name=types,proto3" json:"types,omitempty"`
+	JobIds []string `protobuf:"bytes,2,rep,name=job_ids,json=jobIds,proto3" json:"job_ids,omitempty"`
+	// since replays the events after this sequence number, 0 for none
+	Since                int64    `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EventFilter) Reset()         { *m = EventFilter{} }
+func (m *EventFilter) String() string { return proto.CompactTextString(m) }
+func (*EventFilter) ProtoMessage()    {}
+func (*EventFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{16}
+}
+
+func (m *EventFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventFilter.Unmarshal(m, b)
+}
+func (m *EventFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventFilter.Marshal(b, m, deterministic)
+}
+func (m *EventFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventFilter.Merge(m, src)
+}
+func (m *EventFilter) XXX_Size() int {
+	return xxx_messageInfo_EventFilter.Size(m)
+}
+func (m *EventFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventFilter proto.InternalMessageInfo
+
+func (m *EventFilter) GetTypes() []string {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+func (m *EventFilter) GetJobIds() []string {
+	if m != nil {
+		return m.JobIds
+	}
+	return nil
+}
+
+func (m *EventFilter) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+type Event struct {
+	Seq                  int64                `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	Time                 *timestamp.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Type                 string               `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	JobId                string               `protobuf:"bytes,4,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	TaskId               string               `protobuf:"bytes,5,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	AgentId              string               `protobuf:"bytes,6,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Hostname             string               `protobuf:"bytes,7,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	State                string               `protobuf:"bytes,8,opt,name=state,proto3" json:"state,omitempty"`
+	From                 string               `protobuf:"bytes,9,opt,name=from,proto3" json:"from,omitempty"`
+	Message              string               `protobuf:"bytes,10,opt,name=message,proto3" json:"message,omitempty"`
+	DeploymentId         string               `protobuf:"bytes,11,opt,name=deployment_id,json=deploymentId,proto3" json:"deployment_id,omitempty"`
+	Version              int32                `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2b3fc28395a6d9c5, []int{17}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetSeq() int64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *Event) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *Event) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Event) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *Event) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *Event) GetAgentId() string {
+	if m != nil {
+		return m.AgentId
+	}
+	return ""
+}
+
+func (m *Event) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *Event) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *Event) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *Event) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Event) GetDeploymentId() string {
+	if m != nil {
+		return m.DeploymentId
+	}
+	return ""
+}
+
+func (m *Event) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Empty)(nil), "pb.Empty")
+	proto.RegisterType((*ListRequest)(nil), "pb.ListRequest")
+	proto.RegisterType((*JobRequest)(nil), "pb.JobRequest")
+	proto.RegisterType((*JobSpec)(nil), "pb.JobSpec")
+	proto.RegisterType((*Job)(nil), "pb.Job")
+	proto.RegisterType((*JobList)(nil), "pb.JobList")
+	proto.RegisterType((*Task)(nil), "pb.Task")
+	proto.RegisterType((*TaskList)(nil), "pb.TaskList")
+	proto.RegisterType((*KillTaskRequest)(nil), "pb.KillTaskRequest")
+	proto.RegisterType((*KillRequest)(nil), "pb.KillRequest")
+	proto.RegisterType((*KillResponse)(nil), "pb.KillResponse")
+	proto.RegisterType((*Kill)(nil), "pb.Kill")
+	proto.RegisterType((*ScaleRequest)(nil), "pb.ScaleRequest")
+	proto.RegisterType((*ScaleResponse)(nil), "pb.ScaleResponse")
+	proto.RegisterType((*Deployment)(nil), "pb.Deployment")
+	proto.RegisterType((*DeploymentList)(nil), "pb.DeploymentList")
+	proto.RegisterType((*EventFilter)(nil), "pb.EventFilter")
+	proto.RegisterType((*Event)(nil), "pb.Event")
+}
+
+func init() {
+	proto.RegisterFile("scheduler.proto", fileDescriptor_2b3fc28395a6d9c5)
+}
+
+var fileDescriptor_2b3fc28395a6d9c5 = []byte{
+	// 1111 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x5f, 0x6f, 0xdc, 0x44,
+	0x10, 0xd7, 0xfd, 0xb5, 0x3d, 0xbe, 0xbb, 0xb4, 0x0b, 0x08, 0x73, 0xa1, 0x6d, 0xe4, 0x12, 0x14,
+	0x15, 0xb8, 0x94, 0x50, 0x55, 0x3c, 0x02, 0x22, 0xa0, 0x04, 0x78, 0xd9, 0x04, 0xf1, 0x18, 0xd9,
+	0xe7, 0xcd, 0xc5, 0x89, 0xed, 0x75, 0xbd, 0xeb, 0x54, 0xf7, 0x99, 0xf8, 0x20, 0xf0, 0x35, 0xf8,
+	0x0c, 0xbc, 0x23, 0x34, 0xb3, 0xfe, 0x77, 0x49, 0x9b, 0x46, 0x7d, 0xba, 0x9d, 0xdf, 0xcc, 0xce,
+	0xee, 0xcd, 0xfc, 0x7e, 0x3b, 0x86, 0x2d, 0xb5, 0xbc, 0x10, 0x51, 0x99, 0x88, 0x62, 0x91, 0x17,
+	0x52, 0x4b, 0xd6, 0xcf, 0xc3, 0xf9, 0x93, 0x95, 0x94, 0xab, 0x44, 0xec, 0x13, 0x12, 0x96, 0xe7,
+	0xfb, 0x3a, 0x4e, 0x85, 0xd2, 0x41, 0x9a, 0x9b, 0x20, 0xdf, 0x82, 0xd1, 0x61, 0x9a, 0xeb, 0xb5,
+	0x3f, 0x05, 0xf7, 0xd7, 0x58, 0x69, 0x2e, 0x5e, 0x95, 0x42, 0x69, 0xff, 0x29, 0xc0, 0xb1, 0x0c,
+	0x2b, 0x8b, 0x7d, 0x04, 0xe3, 0x4b, 0x19, 0x9e, 0xc5, 0x91, 0xd7, 0xdb, 0xe9, 0xed, 0x39, 0x7c,
+	0x74, 0x29, 0xc3, 0xa3, 0xc8, 0x7f, 0x04, 0xd6, 0xb1, 0x0c, 0x4f, 0x72, 0xb1, 0x64, 0x0c, 0x86,
+	0x97, 0x4a, 0x66, 0xe4, 0x9f, 0x70, 0x5a, 0xfb, 0xff, 0xf4, 0x60, 0x70, 0x2c, 0x43, 0x36, 0x83,
+	0x7e, 0xb3, 0xb3, 0x1f, 0x47, 0x18, 0xab, 0xd7, 0xb9, 0xf0, 0xfa, 0x84, 0xd0, 0x9a, 0xcd, 0xc1,
+	0xce, 0x8b, 0x58, 0x16, 0xb1, 0x5e, 0x7b, 0x83, 0x9d, 0xde, 0xde, 0x88, 0x37, 0x36, 0xfb, 0x14,
+	0x9c, 0x38, 0x53, 0x3a, 0xc8, 0x96, 0x42, 0x79, 0x43, 0x72, 0xb6, 0x00, 0xfb, 0x10, 0x46, 0x3a,
+	0x50, 0x57, 0xca, 0x1b, 0x91, 0xc7, 0x18, 0xcc, 0x03, 0xab, 0x28, 0xb3, 0x2c, 0xce, 0x56, 0xde,
+	0x98, 0xf0, 0xda, 0xc4, 0xf8, 0x42, 0x04, 0xd1, 0xda, 0xb3, 0x4c, 0x3c, 0x19, 0x78, 0x46, 0x99,
+	0x5d, 0x88, 0x20, 0xd1, 0x17, 0x6b, 0xcf, 0x36, 0x67, 0x34, 0x00, 0x66, 0xcb, 0x45, 0x16, 0x61,
+	0x36, 0xc7, 0x64, 0xab, 0x4c, 0xff, 0x73, 0x2a, 0x01, 0x56, 0x8e, 0x6d, 0xc3, 0xf0, 0x52, 0x86,
+	0xca, 0xeb, 0xed, 0x0c, 0xf6, 0xdc, 0x03, 0x6b, 0x91, 0x87, 0x0b, 0x2c, 0x21, 0x81, 0xfe, 0xbf,
+	0x7d, 0x18, 0x9e, 0x06, 0xea, 0xea, 0x56, 0x31, 0xda, 0xd2, 0xf6, 0x3b, 0xa5, 0xc5, 0x7a, 0x5c,
+	0x48, 0xa5, 0xb3, 0x20, 0x15, 0x54, 0x0f, 0x87, 0x37, 0x36, 0xfb, 0x04, 0xec, 0x60, 0x25, 0x32,
+	0x8d, 0x9b, 0x86, 0xe4, 0xb3, 0xc8, 0x3e, 0x8a, 0xf0, 0xcf, 0x29, 0x1d, 0x68, 0x41, 0xc5, 0x70,
+	0xb8, 0x31, 0xd8, 0x4b, 0xb0, 0x93, 0xa0, 0xcc, 0x90, 0x1f, 0x54, 0x0d, 0xf7, 0x60, 0xbe, 0x30,
+	0xc4, 0x58, 0xd4, 0xc4, 0x58, 0x9c, 0xd6, 0xc4, 0xe0, 0x4d, 0x2c, 0x7b, 0x01, 0x56, 0x99, 0x47,
+	0x81, 0x16, 0x91, 0x67, 0xbd, 0x73, 0x5b, 0x1d, 0xca, 0x76, 0x61, 0x86, 0xc7, 0x96, 0xea, 0x2c,
+	0x15, 0x4a, 0x05, 0x2b, 0x41, 0xf5, 0x74, 0xf8, 0xd4, 0xa0, 0xbf, 0x19, 0xb0, 0xed, 0x03, 0x56,
+	0xd4, 0xae, 0xfb, 0xc0, 0x60, 0xb8, 0xcc, 0x4b, 0xe5, 0xc1, 0x4e, 0x6f, 0xaf, 0xc7, 0x69, 0xcd,
+	0x1e, 0xc0, 0x20, 0x15, 0xa9, 0xe7, 0x12, 0x84, 0x4b, 0x8c, 0x8a, 0x62, 0x75, 0xe5, 0x4d, 0x4c,
+	0x14, 0xae, 0x11, 0x5b, 0xe1, 0xce, 0xa9, 0xc1, 0x70, 0xed, 0x3f, 0x03, 0x1b, 0x8b, 0x4e, 0xed,
+	0x79, 0x5c, 0xf3, 0xc4, 0xf4, 0xc7, 0xc6, 0xfe, 0xa0, 0xb3, 0x62, 0x8c, 0xff, 0x1d, 0x6c, 0xfd,
+	0x12, 0x27, 0x09, 0x41, 0x15, 0xed, 0x3f, 0x06, 0x0b, 0x7d, 0x2d, 0xef, 0xc7, 0x68, 0x56, 0x65,
+	0x5e, 0x06, 0x89, 0xa1, 0xb0, 0xcd, 0x8d, 0xe1, 0x3f, 0x02, 0x17, 0x33, 0xd4, 0xbb, 0x6f, 0x74,
+	0xda, 0x7f, 0x0c, 0x13, 0xe3, 0x56, 0xb9, 0xcc, 0x94, 0xb8, 0xe5, 0xff, 0xbb, 0x07, 0x43, 0x0c,
+	0xb8, 0x2f, 0x45, 0x3a, 0xb7, 0x1b, 0x6c, 0xdc, 0xee, 0x5b, 0x70, 0x0a, 0x73, 0x07, 0x61, 0x08,
+	0x72, 0x77, 0xe3, 0xda, 0xe0, 0xae, 0x96, 0x06, 0x78, 0x10, 0x19, 0xa8, 0x8d, 0x42, 0xa4, 0x41,
+	0x5c, 0xa9, 0x09, 0x3d, 0x2d, 0x40, 0xbd, 0x90, 0x99, 0x20, 0x86, 0xd8, 0x9c, 0xd6, 0xfe, 0x25,
+	0x4c, 0x4e, 0xb0, 0x24, 0x77, 0xbf, 0x1f, 0x9b, 0xc2, 0xee, 0xdf, 0x14, 0xf6, 0x2e, 0xcc, 0xae,
+	0xe2, 0x24, 0x39, 0x53, 0x22, 0x11, 0x4b, 0x1d, 0xcb, 0xac, 0xfa, 0x9b, 0x53, 0x44, 0x4f, 0x6a,
+	0xd0, 0xe7, 0x30, 0xad, 0xce, 0xaa, 0xea, 0xba, 0x91, 0xb5, 0x77, 0x33, 0xeb, 0x53, 0x98, 0x46,
+	0x22, 0x4f, 0xe4, 0x3a, 0xad, 0x14, 0x64, 0x6a, 0x3a, 0x69, 0xc1, 0xa3, 0xc8, 0xff, 0xaf, 0x0f,
+	0xf0, 0x63, 0x03, 0xdc, 0xb7, 0x21, 0x1e, 0x58, 0xd7, 0xa2, 0x50, 0xf5, 0x4d, 0x47, 0xbc, 0x36,
+	0x51, 0xcd, 0x4a, 0x17, 0x81, 0x16, 0xab, 0x75, 0xa5, 0xd8, 0xc6, 0x7e, 0x8b, 0x64, 0x5f, 0x80,
+	0xa5, 0x74, 0x50, 0xe8, 0x7b, 0x29, 0xb6, 0x0e, 0x7d, 0x4f, 0xc1, 0x6e, 0x83, 0x23, 0x93, 0xe8,
+	0xcc, 0x74, 0xde, 0xbc, 0x7d, 0xb6, 0x4c, 0xa2, 0x53, 0x6a, 0xfe, 0x36, 0x38, 0x99, 0x78, 0x5d,
+	0x39, 0xcd, 0xe3, 0x67, 0x67, 0xe2, 0xb5, 0x71, 0x3e, 0x01, 0x97, 0x64, 0x5b, 0xb9, 0x81, 0xdc,
+	0x40, 0x90, 0x09, 0x98, 0x83, 0x7d, 0x1e, 0xc4, 0x49, 0x59, 0x08, 0x45, 0xfa, 0x1d, 0xf1, 0xc6,
+	0x46, 0x5f, 0x21, 0x93, 0x24, 0x0c, 0x96, 0x46, 0xc8, 0x36, 0x6f, 0x6c, 0xff, 0x07, 0x98, 0xb5,
+	0xf5, 0x27, 0xf9, 0x3e, 0x07, 0xb7, 0x6d, 0x51, 0x2d, 0xe2, 0x19, 0x8a, 0xb8, 0x0d, 0xe4, 0xdd,
+	0x10, 0x9f, 0x83, 0x7b, 0x78, 0x2d, 0x32, 0xfd, 0x53, 0x9c, 0x68, 0x51, 0x10, 0xb7, 0xd7, 0xb9,
+	0x30, 0x5b, 0x1d, 0x6e, 0x0c, 0x14, 0x91, 0x69, 0x25, 0x12, 0x10, 0xf1, 0x31, 0xf5, 0x92, 0xc6,
+	0x8a, 0x8a, 0xb3, 0xa5, 0x79, 0x7d, 0x07, 0xdc, 0x18, 0xfe, 0x5f, 0x7d, 0x18, 0x51, 0x52, 0x7c,
+	0x94, 0x94, 0x78, 0x45, 0xa4, 0x18, 0x70, 0x5c, 0xb2, 0x05, 0x0c, 0x71, 0xba, 0x7a, 0xfd, 0x77,
+	0x56, 0x9e, 0xe2, 0x9a, 0x31, 0x38, 0xe8, 0x8c, 0xc1, 0x96, 0x59, 0xc3, 0xb7, 0x48, 0x7d, 0xb4,
+	0x21, 0xf5, 0xee, 0x28, 0x18, 0x6f, 0x8e, 0x82, 0xee, 0x04, 0xb1, 0x6e, 0x4c, 0x90, 0x86, 0x73,
+	0x76, 0x97, 0x73, 0x0c, 0x86, 0xe7, 0x85, 0x4c, 0xa9, 0xcb, 0x0e, 0xa7, 0x35, 0x72, 0xba, 0x7e,
+	0xc5, 0xc1, 0xe4, 0xaf, 0xcc, 0xdb, 0x42, 0x72, 0x6f, 0x0b, 0xa9, 0x2b, 0x89, 0xc9, 0x86, 0x24,
+	0x0e, 0xfe, 0x1c, 0x82, 0x73, 0x52, 0x7f, 0xb1, 0xb0, 0x5d, 0x70, 0x4e, 0xca, 0x30, 0x8d, 0x35,
+	0x7e, 0x2f, 0xb8, 0xd5, 0xe8, 0xc4, 0x0f, 0x8b, 0x79, 0xd7, 0xc0, 0xb0, 0xdf, 0x89, 0xb4, 0x77,
+	0x87, 0x7d, 0x05, 0x0e, 0x17, 0xa9, 0xbc, 0xa6, 0xb0, 0x59, 0xe5, 0xa9, 0xde, 0xa2, 0xf9, 0x03,
+	0xb4, 0x37, 0x1e, 0xe2, 0x3d, 0xb0, 0x91, 0x62, 0xc7, 0x32, 0x54, 0x6c, 0x0b, 0xbd, 0x9d, 0x0f,
+	0xa1, 0x26, 0x31, 0x62, 0xec, 0x19, 0x38, 0xf8, 0x6b, 0xb8, 0x7d, 0x2b, 0x74, 0x52, 0x8f, 0x14,
+	0x8a, 0xfd, 0x1a, 0xec, 0x7a, 0x9e, 0xb0, 0x0f, 0xea, 0x33, 0x3b, 0xd3, 0xe5, 0x0d, 0x17, 0xf9,
+	0x0c, 0xac, 0x9f, 0x85, 0x46, 0xc8, 0x24, 0xef, 0x4c, 0x93, 0xb9, 0x5d, 0x03, 0xec, 0x4b, 0x18,
+	0xd1, 0x83, 0xc7, 0x28, 0x41, 0xf7, 0x9d, 0x9d, 0x3f, 0xec, 0x20, 0x55, 0xce, 0x97, 0xb0, 0x85,
+	0xd7, 0x69, 0x45, 0xf2, 0x86, 0x8b, 0xb3, 0x4d, 0x19, 0xd1, 0xf5, 0x17, 0xf0, 0xf0, 0xfb, 0x3c,
+	0x2f, 0xe4, 0xb5, 0xe8, 0x3e, 0x84, 0x37, 0x6a, 0xe9, 0xa0, 0x4d, 0xdf, 0x8f, 0x6c, 0x1f, 0x18,
+	0xaf, 0xd4, 0x7b, 0xbf, 0x0d, 0x5f, 0x80, 0xfb, 0x47, 0xa0, 0x97, 0x17, 0x87, 0xd7, 0xed, 0xa5,
+	0x3a, 0x7a, 0x9d, 0x3b, 0x0d, 0xf0, 0xbc, 0x17, 0x8e, 0x49, 0x45, 0xdf, 0xfc, 0x3f, 0x00, 0x1a,
+	0x8c, 0x67, 0x57, 0xe5, 0x0a, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SchedulerClient is the client API for Scheduler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SchedulerClient interface {
+	SubmitJob(ctx context.Context, in *JobSpec, opts ...grpc.CallOption) (*JobSpec, error)
+	UpdateJob(ctx context.Context, in *JobSpec, opts ...grpc.CallOption) (*JobSpec, error)
+	RemoveJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*KillResponse, error)
+	ListJobs(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*JobList, error)
+	ListTasks(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*TaskList, error)
+	KillTask(ctx context.Context, in *KillTaskRequest, opts ...grpc.CallOption) (*KillResponse, error)
+	GetKill(ctx context.Context, in *KillRequest, opts ...grpc.CallOption) (*Kill, error)
+	Scale(ctx context.Context, in *ScaleRequest, opts ...grpc.CallOption) (*ScaleResponse, error)
+	ListDeployments(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*DeploymentList, error)
+	ApproveDeployment(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*Empty, error)
+	RollbackDeployment(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*Empty, error)
+	// WatchEvents streams the events as GET /v1/events does
+	WatchEvents(ctx context.Context, in *EventFilter, opts ...grpc.CallOption) (Scheduler_WatchEventsClient, error)
+}
+
+type schedulerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSchedulerClient(cc grpc.ClientConnInterface) SchedulerClient {
+	return &schedulerClient{cc}
+}
+
+func (c *schedulerClient) SubmitJob(ctx context.Context, in *JobSpec, opts ...grpc.CallOption) (*JobSpec, error) {
+	out := new(JobSpec)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/SubmitJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) UpdateJob(ctx context.Context, in *JobSpec, opts ...grpc.CallOption) (*JobSpec, error) {
+	out := new(JobSpec)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/UpdateJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) RemoveJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*KillResponse, error) {
+	out := new(KillResponse)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/RemoveJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) ListJobs(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*JobList, error) {
+	out := new(JobList)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/ListJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) ListTasks(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*TaskList, error) {
+	out := new(TaskList)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/ListTasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) KillTask(ctx context.Context, in *KillTaskRequest, opts ...grpc.CallOption) (*KillResponse, error) {
+	out := new(KillResponse)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/KillTask", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) GetKill(ctx context.Context, in *KillRequest, opts ...grpc.CallOption) (*Kill, error) {
+	out := new(Kill)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/GetKill", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) Scale(ctx context.Context, in *ScaleRequest, opts ...grpc.CallOption) (*ScaleResponse, error) {
+	out := new(ScaleResponse)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/Scale", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) ListDeployments(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*DeploymentList, error) {
+	out := new(DeploymentList)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/ListDeployments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) ApproveDeployment(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/ApproveDeployment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) RollbackDeployment(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Scheduler/RollbackDeployment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *schedulerClient) WatchEvents(ctx context.Context, in *EventFilter, opts ...grpc.CallOption) (Scheduler_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Scheduler_serviceDesc.Streams[0], "/pb.Scheduler/WatchEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &schedulerWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Scheduler_WatchEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type schedulerWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *schedulerWatchEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SchedulerServer is the server API for Scheduler service.
+type SchedulerServer interface {
+	SubmitJob(context.Context, *JobSpec) (*JobSpec, error)
+	UpdateJob(context.Context, *JobSpec) (*JobSpec, error)
+	RemoveJob(context.Context, *JobRequest) (*KillResponse, error)
+	ListJobs(context.Context, *ListRequest) (*JobList, error)
+	ListTasks(context.Context, *ListRequest) (*TaskList, error)
+	KillTask(context.Context, *KillTaskRequest) (*KillResponse, error)
+	GetKill(context.Context, *KillRequest) (*Kill, error)
+	Scale(context.Context, *ScaleRequest) (*ScaleResponse, error)
+	ListDeployments(context.Context, *ListRequest) (*DeploymentList, error)
+	ApproveDeployment(context.Context, *JobRequest) (*Empty, error)
+	RollbackDeployment(context.Context, *JobRequest) (*Empty, error)
+	// WatchEvents streams the events as GET /v1/events does
+	WatchEvents(*EventFilter, Scheduler_WatchEventsServer) error
+}
+
+// UnimplementedSchedulerServer can be embedded to have forward compatible implementations.
+type UnimplementedSchedulerServer struct {
+}
+
+func (*UnimplementedSchedulerServer) SubmitJob(ctx context.Context, req *JobSpec) (*JobSpec, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitJob not implemented")
+}
+func (*UnimplementedSchedulerServer) UpdateJob(ctx context.Context, req *JobSpec) (*JobSpec, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateJob not implemented")
+}
+func (*UnimplementedSchedulerServer) RemoveJob(ctx context.Context, req *JobRequest) (*KillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveJob not implemented")
+}
+func (*UnimplementedSchedulerServer) ListJobs(ctx context.Context, req *ListRequest) (*JobList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (*UnimplementedSchedulerServer) ListTasks(ctx context.Context, req *ListRequest) (*TaskList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (*UnimplementedSchedulerServer) KillTask(ctx context.Context, req *KillTaskRequest) (*KillResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KillTask not implemented")
+}
+func (*UnimplementedSchedulerServer) GetKill(ctx context.Context, req *KillRequest) (*Kill, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKill not implemented")
+}
+func (*UnimplementedSchedulerServer) Scale(ctx context.Context, req *ScaleRequest) (*ScaleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scale not implemented")
+}
+func (*UnimplementedSchedulerServer) ListDeployments(ctx context.Context, req *ListRequest) (*DeploymentList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeployments not implemented")
+}
+func (*UnimplementedSchedulerServer) ApproveDeployment(ctx context.Context, req *JobRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveDeployment not implemented")
+}
+func (*UnimplementedSchedulerServer) RollbackDeployment(ctx context.Context, req *JobRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackDeployment not implemented")
+}
+func (*UnimplementedSchedulerServer) WatchEvents(req *EventFilter, srv Scheduler_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+
+func RegisterSchedulerServer(s *grpc.Server, srv SchedulerServer) {
+	s.RegisterService(&_Scheduler_serviceDesc, srv)
+}
+
+func _Scheduler_SubmitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).SubmitJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/SubmitJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).SubmitJob(ctx, req.(*JobSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_UpdateJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).UpdateJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/UpdateJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).UpdateJob(ctx, req.(*JobSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_RemoveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).RemoveJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/RemoveJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).RemoveJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/ListJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).ListJobs(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/ListTasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).ListTasks(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_KillTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KillTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).KillTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/KillTask",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).KillTask(ctx, req.(*KillTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_GetKill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).GetKill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/GetKill",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).GetKill(ctx, req.(*KillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_Scale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScaleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).Scale(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/Scale",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).Scale(ctx, req.(*ScaleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_ListDeployments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).ListDeployments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/ListDeployments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).ListDeployments(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_ApproveDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).ApproveDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/ApproveDeployment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).ApproveDeployment(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_RollbackDeployment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SchedulerServer).RollbackDeployment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Scheduler/RollbackDeployment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SchedulerServer).RollbackDeployment(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Scheduler_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EventFilter)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SchedulerServer).WatchEvents(m, &schedulerWatchEventsServer{stream})
+}
+
+type Scheduler_WatchEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type schedulerWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *schedulerWatchEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Scheduler_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Scheduler",
+	HandlerType: (*SchedulerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitJob",
+			Handler:    _Scheduler_SubmitJob_Handler,
+		},
+		{
+			MethodName: "UpdateJob",
+			Handler:    _Scheduler_UpdateJob_Handler,
+		},
+		{
+			MethodName: "RemoveJob",
+			Handler:    _Scheduler_RemoveJob_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _Scheduler_ListJobs_Handler,
+		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _Scheduler_ListTasks_Handler,
+		},
+		{
+			MethodName: "KillTask",
+			Handler:    _Scheduler_KillTask_Handler,
+		},
+		{
+			MethodName: "GetKill",
+			Handler:    _Scheduler_GetKill_Handler,
+		},
+		{
+			MethodName: "Scale",
+			Handler:    _Scheduler_Scale_Handler,
+		},
+		{
+			MethodName: "ListDeployments",
+			Handler:    _Scheduler_ListDeployments_Handler,
+		},
+		{
+			MethodName: "ApproveDeployment",
+			Handler:    _Scheduler_ApproveDeployment_Handler,
+		},
+		{
+			MethodName: "RollbackDeployment",
+			Handler:    _Scheduler_RollbackDeployment_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _Scheduler_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "scheduler.proto",
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+)
+
+//Status is the body of the probes that pass
+type Status struct {
+	Status string `json:"status"`
+}
+
+//probe handles GET /healthz and GET /readyz for the supervisors of the
+//scheduler process: 200 when the check passes, 503 with why otherwise
+func (s *Server) probe(check func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" && r.Method != "HEAD" {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		if err := check(); err != nil {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		writeJSON(w, http.StatusOK, &Status{Status: "ok"})
+	}
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//ErrUsage can be returned by a Command to print its usage and exit with an
+//error
+var ErrUsage = errors.New("invalid usage")
+
+//Command is a subcommand of an App, like "run" or "status"
+type Command struct {
+	//Name used to invoke the command
+	Name string
+
+	//Arguments expected after the flags, shown in the usage
+	Args string
+
+	//One line description shown in the list of commands
+	Short string
+
+	//Flags of the command. Created by App.Run if nil
+	Flags *flag.FlagSet
+
+	//Run executes the command with the arguments left after parsing the
+	//flags
+	Run func(cmd *Command, args []string) error
+}
+
+//Usage prints the usage of the command and its flags
+func (c *Command) Usage(app string) {
+	fmt.Fprintf(os.Stderr, "Usage: %s %s [flags] %s\n\n%s\n\nFlags:\n", app, c.Name, c.Args, c.Short)
+	c.Flags.PrintDefaults()
+}
+
+//App is a command line application made of several commands
+type App struct {
+	Name     string
+	Commands []*Command
+
+	//Default is the command run when no command name is given, so the
+	//application can be invoked only with flags
+	Default string
+}
+
+//Run parses args (without the program name), finds the command and runs it
+func (a *App) Run(args []string) error {
+	name := a.Default
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "" || name == "help" {
+		a.Usage()
+		return nil
+	}
+
+	cmd := a.command(name)
+	if cmd == nil {
+		a.Usage()
+		return fmt.Errorf("unknown command %q", name)
+	}
+
+	if cmd.Flags == nil {
+		cmd.Flags = flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	}
+	cmd.Flags.Usage = func() { cmd.Usage(a.Name) }
+
+	if err := cmd.Flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return err
+	}
+
+	err := cmd.Run(cmd, cmd.Flags.Args())
+	if err == ErrUsage {
+		cmd.Usage(a.Name)
+	}
+
+	return err
+}
+
+//Usage prints the list of commands of the application
+func (a *App) Usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags] [args]\n\nCommands:\n", a.Name)
+	for _, cmd := range a.Commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.Name, cmd.Short)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the flags of a command\n", a.Name)
+}
+
+func (a *App) command(name string) *Command {
+	for _, cmd := range a.Commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,376 @@
+//Package client calls the management API of a running scheduler, so the
+//scripts and tools driving it don't have to write the HTTP calls. The API
+//itself is described by the OpenAPI document it serves
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"minimal-mesos-go-framework/api"
+	"minimal-mesos-go-framework/example_scheduler"
+)
+
+//Client calls the API of the scheduler at URL
+type Client struct {
+	URL string
+
+	//Token is the bearer token sent with every call, empty for none
+	Token string
+
+	//HTTPClient sends the calls, http.DefaultClient when nil. Set its
+	//transport for the APIs served over TLS with a private authority or
+	//verifying the client certificates
+	HTTPClient *http.Client
+}
+
+//Error is returned for the calls the API refused, with the status code of
+//the answer
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+//New returns the client of the API at the given URL, like
+//http://127.0.0.1:8000
+func New(apiURL, token string) *Client {
+	return &Client{URL: strings.TrimSuffix(apiURL, "/"), Token: token}
+}
+
+//SubmitJob submits a new job and returns it as accepted, with its
+//defaults
+func (c *Client) SubmitJob(job *example_scheduler.JobSpec) (*example_scheduler.JobSpec, error) {
+	var submitted example_scheduler.JobSpec
+	if err := c.call("POST", "/v1/jobs", job, &submitted); err != nil {
+		return nil, err
+	}
+
+	return &submitted, nil
+}
+
+//SubmitJobOnce submits a new job with an idempotency key, so calling it
+//again with the same key and spec, after a timeout for example, doesn't
+//submit it twice. It returns the job and whether it was already submitted
+func (c *Client) SubmitJobOnce(job *example_scheduler.JobSpec, key string) (*example_scheduler.JobSpec, bool, error) {
+	header := http.Header{}
+	header.Set("Idempotency-Key", key)
+
+	var submitted example_scheduler.JobSpec
+	resp, err := c.callWith("POST", "/v1/jobs", header, job, &submitted)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &submitted, resp.Header.Get("Idempotent-Replayed") == "true", nil
+}
+
+//SubmitJobs submits several jobs at once, all of them or none. It returns
+//them as accepted. The error of a job tells its position
+func (c *Client) SubmitJobs(jobs []*example_scheduler.JobSpec) ([]*example_scheduler.JobSpec, error) {
+	var resp api.BatchResponse
+	if err := c.call("POST", "/v1/jobs", jobs, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Jobs, nil
+}
+
+//UpdateJob deploys the new spec of a job
+func (c *Client) UpdateJob(job *example_scheduler.JobSpec) (*example_scheduler.JobSpec, error) {
+	var updated example_scheduler.JobSpec
+	if err := c.call("PUT", "/v1/jobs/"+url.PathEscape(job.ID), job, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+//RemoveJob kills the tasks of a job and removes it. It returns the ID of
+//the kill, followed with Kill
+func (c *Client) RemoveJob(jobId string) (string, error) {
+	var kill api.KillResponse
+	if err := c.call("DELETE", "/v1/jobs/"+url.PathEscape(jobId), nil, &kill); err != nil {
+		return "", err
+	}
+
+	return kill.ID, nil
+}
+
+//Jobs returns the jobs with the state of their instances
+func (c *Client) Jobs() ([]example_scheduler.JobSummary, error) {
+	var jobs []example_scheduler.JobSummary
+	return jobs, c.call("GET", "/v1/jobs", nil, &jobs)
+}
+
+//Tasks returns the tasks of the job in the given states, the ones of every
+//job with an empty job and in any state without states
+func (c *Client) Tasks(jobId string, states ...string) ([]example_scheduler.TaskSummary, error) {
+	query := url.Values{}
+	if jobId != "" {
+		query.Set("job", jobId)
+	}
+	if len(states) > 0 {
+		query.Set("state", strings.Join(states, ","))
+	}
+
+	var tasks []example_scheduler.TaskSummary
+	return tasks, c.call("GET", "/v1/tasks?"+query.Encode(), nil, &tasks)
+}
+
+//KillTask kills a task, its job launches a replacement unless scale is set.
+//It returns the ID of the kill, followed with Kill
+func (c *Client) KillTask(taskId string, scale bool) (string, error) {
+	path := "/v1/tasks/" + url.PathEscape(taskId)
+	if scale {
+		path += "?scale=true"
+	}
+
+	var kill api.KillResponse
+	if err := c.call("DELETE", path, nil, &kill); err != nil {
+		return "", err
+	}
+
+	return kill.ID, nil
+}
+
+//Kill returns the state of a kill
+func (c *Client) Kill(id string) (*example_scheduler.KillSummary, error) {
+	var kill example_scheduler.KillSummary
+	if err := c.call("GET", "/v1/kills/"+url.PathEscape(id), nil, &kill); err != nil {
+		return nil, err
+	}
+
+	return &kill, nil
+}
+
+//WaitKill polls a kill every interval until its tasks ended
+func (c *Client) WaitKill(id string, interval time.Duration) (*example_scheduler.KillSummary, error) {
+	for {
+		kill, err := c.Kill(id)
+		if err != nil || kill.Done {
+			return kill, err
+		}
+		time.Sleep(interval)
+	}
+}
+
+//Scale changes the number of instances of a job, killing the tasks chosen
+//by selection when scaling down, the default with an empty one. It returns
+//the ID of the deployment scaling the job
+func (c *Client) Scale(jobId string, instances int, selection string) (string, error) {
+	req := api.ScaleRequest{Instances: instances, KillSelection: selection}
+	var resp api.ScaleResponse
+	if err := c.call("PUT", "/v1/jobs/"+url.PathEscape(jobId)+"/scale", &req, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.DeploymentID, nil
+}
+
+//Deployments returns the last deployment of every job
+func (c *Client) Deployments() ([]example_scheduler.DeploymentSummary, error) {
+	var deployments []example_scheduler.DeploymentSummary
+	return deployments, c.call("GET", "/v1/deployments", nil, &deployments)
+}
+
+//Deployment returns a deployment by its ID, the job and its version like
+//web.3
+func (c *Client) Deployment(id string) (*example_scheduler.DeploymentSummary, error) {
+	var deployment example_scheduler.DeploymentSummary
+	if err := c.call("GET", "/v1/deployments/"+url.PathEscape(id), nil, &deployment); err != nil {
+		return nil, err
+	}
+
+	return &deployment, nil
+}
+
+//ApproveDeployment approves the cutover of the deployment of a job
+func (c *Client) ApproveDeployment(jobId string) error {
+	return c.call("POST", "/v1/deployments/"+url.PathEscape(jobId)+"/approve", nil, nil)
+}
+
+//RollbackDeployment rolls a job back to its previous spec
+func (c *Client) RollbackDeployment(jobId string) error {
+	return c.call("POST", "/v1/deployments/"+url.PathEscape(jobId)+"/rollback", nil, nil)
+}
+
+//Queue describes the launch queue
+func (c *Client) Queue() (*example_scheduler.QueueSummary, error) {
+	var queue example_scheduler.QueueSummary
+	if err := c.call("GET", "/v1/queue", nil, &queue); err != nil {
+		return nil, err
+	}
+
+	return &queue, nil
+}
+
+//Runs returns the runs of a cron job
+func (c *Client) Runs(jobId string) ([]example_scheduler.CronRunSummary, error) {
+	var runs []example_scheduler.CronRunSummary
+	return runs, c.call("GET", "/v1/jobs/"+url.PathEscape(jobId)+"/runs", nil, &runs)
+}
+
+//Indexes returns the state of each index of an indexed job
+func (c *Client) Indexes(jobId string) ([]example_scheduler.IndexSummary, error) {
+	var indexes []example_scheduler.IndexSummary
+	return indexes, c.call("GET", "/v1/jobs/"+url.PathEscape(jobId)+"/indexes", nil, &indexes)
+}
+
+//RetryIndexes launches again the failed indexes of an indexed job and
+//returns them
+func (c *Client) RetryIndexes(jobId string) ([]int, error) {
+	var retried []int
+	return retried, c.call("POST", "/v1/jobs/"+url.PathEscape(jobId)+"/retry", nil, &retried)
+}
+
+//Pipeline returns the jobs with dependencies and their state
+func (c *Client) Pipeline() ([]example_scheduler.PipelineSummary, error) {
+	var pipeline []example_scheduler.PipelineSummary
+	return pipeline, c.call("GET", "/v1/pipeline", nil, &pipeline)
+}
+
+//HostFilter returns the host filter
+func (c *Client) HostFilter() (*example_scheduler.HostFilter, error) {
+	var filter example_scheduler.HostFilter
+	if err := c.call("GET", "/v1/hosts", nil, &filter); err != nil {
+		return nil, err
+	}
+
+	return &filter, nil
+}
+
+//SetHostFilter replaces the host filter
+func (c *Client) SetHostFilter(filter *example_scheduler.HostFilter) error {
+	return c.call("PUT", "/v1/hosts", filter, nil)
+}
+
+//Maintenance tells if the scheduling is paused
+func (c *Client) Maintenance() (*example_scheduler.Maintenance, error) {
+	var m example_scheduler.Maintenance
+	if err := c.call("GET", "/v1/maintenance", nil, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+//Pause stops the scheduling until Resume, for the reason given
+func (c *Client) Pause(reason string) (*example_scheduler.Maintenance, error) {
+	var m example_scheduler.Maintenance
+	if err := c.call("POST", "/v1/maintenance/pause", &api.PauseRequest{Reason: reason}, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+//Resume starts the scheduling again
+func (c *Client) Resume() (*example_scheduler.Maintenance, error) {
+	var m example_scheduler.Maintenance
+	if err := c.call("POST", "/v1/maintenance/resume", nil, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+//Offers returns the statistics of the offers
+func (c *Client) Offers() (*example_scheduler.OfferStats, error) {
+	var stats example_scheduler.OfferStats
+	if err := c.call("GET", "/v1/offers", nil, &stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+//Audit returns the audit entries matching the filter
+func (c *Client) Audit(filter example_scheduler.AuditFilter) ([]example_scheduler.AuditEntry, error) {
+	query := url.Values{}
+	for name, value := range map[string]string{
+		"action": filter.Action,
+		"job":    filter.JobID,
+		"task":   filter.TaskID,
+		"agent":  filter.AgentID,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if !filter.Since.IsZero() {
+		query.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", fmt.Sprint(filter.Limit))
+	}
+
+	var entries []example_scheduler.AuditEntry
+	return entries, c.call("GET", "/v1/audit?"+query.Encode(), nil, &entries)
+}
+
+//call sends body as JSON and decodes the answer in result, if not nil
+func (c *Client) call(method, path string, body, result interface{}) error {
+	_, err := c.callWith(method, path, nil, body, result)
+	return err
+}
+
+//callWith is call with more headers. It returns the answer, its body
+//already closed
+func (c *Client) callWith(method, path string, header http.Header, body, result interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.URL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		apiErr := &Error{Method: method, Path: path, StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		var body api.Error
+		if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Error != "" {
+			apiErr.Message = body.Error
+		}
+		return resp, apiErr
+	}
+
+	if result == nil {
+		return resp, nil
+	}
+	return resp, json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,858 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+//Config holds every setting needed to build the DriverConfig, the
+//FrameworkInfo and the ExampleScheduler. It is loaded from a JSON file and
+//can be overridden value by value using Set.
+type Config struct {
+	//Master address <ip:port>
+	Master string `json:"master"`
+
+	//Address where the management API listens
+	APIAddress string `json:"api_address"`
+
+	//APITokensFile holds the bearer tokens the API requires, a principal
+	//and its token per line. Empty leaves the API open
+	APITokensFile string `json:"api_tokens_file"`
+
+	//APIOpenReads lets the GET calls of the API go without a token
+	APIOpenReads bool `json:"api_open_reads"`
+
+	//The certificate and key files the API is served with over HTTPS,
+	//both empty for plain HTTP, and the file of the authorities of the
+	//client certificates, empty not to require any
+	APITLSCert     string `json:"api_tls_cert"`
+	APITLSKey      string `json:"api_tls_key"`
+	APITLSClientCA string `json:"api_tls_client_ca"`
+
+	//GRPCAddress is where the gRPC API listens, with the tokens and the
+	//TLS settings of the API. Empty doesn't serve it
+	GRPCAddress string `json:"grpc_address"`
+
+	//LogLevel is one of debug, info, warn or error
+	LogLevel string `json:"log_level"`
+
+	//LogFormat is text or json
+	LogFormat string `json:"log_format"`
+
+	//DryRun logs the offers that would be accepted and the tasks that would
+	//be launched, but declines every offer
+	DryRun bool `json:"dry_run"`
+
+	//AllowCommandReadiness lets the jobs have command readiness checks,
+	//which run on the scheduler host
+	AllowCommandReadiness bool `json:"allow_command_readiness"`
+
+	//Placement chooses the agent of each task: first-fit, bin-packing,
+	//spread or any other registered one
+	Placement string `json:"placement"`
+
+	//Seconds an unreachable task is waited for before it is replaced
+	UnreachableGrace float64 `json:"unreachable_grace"`
+
+	//Seconds a task may take to reach TASK_RUNNING before it is killed and
+	//replaced on another agent
+	LaunchTimeout float64 `json:"launch_timeout"`
+
+	//Seconds no task is placed on an agent after it is lost
+	LostAgentCooldown float64 `json:"lost_agent_cooldown"`
+
+	//Seconds the instances of a job wait for resources before the tasks
+	//of lower priority jobs are preempted for them
+	PreemptionGrace float64 `json:"preemption_grace"`
+
+	//Most instances waiting in the launch queue, 0 for no limit
+	MaxQueued int `json:"max_queued"`
+
+	//AuditLog is the file where every scheduling decision is appended, as
+	//a line of JSON
+	AuditLog string `json:"audit_log"`
+
+	Framework     FrameworkConfig     `json:"framework"`
+	HA            HAConfig            `json:"ha"`
+	Reconcile     ReconcileConfig     `json:"reconcile"`
+	Decline       DeclineConfig       `json:"decline"`
+	AgentFailures AgentFailuresConfig `json:"agent_failures"`
+	TaskHistory   TaskHistoryConfig   `json:"task_history"`
+	Metrics       MetricsConfig       `json:"metrics"`
+	Webhooks      WebhooksConfig      `json:"webhooks"`
+	Slack         SlackConfig         `json:"slack"`
+	Shutdown      ShutdownConfig      `json:"shutdown"`
+	Executor      ExecutorConfig      `json:"executor"`
+	Task          TaskConfig          `json:"task"`
+	Hosts         HostsConfig         `json:"hosts"`
+	Credential    CredentialConfig    `json:"credential"`
+}
+
+//FrameworkConfig is the information used to fill the mesosproto.FrameworkInfo
+type FrameworkConfig struct {
+	User string `json:"user"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+
+	//Roles, when set, registers the framework with several roles instead
+	//of Role. The jobs choose the role of the offers they use
+	Roles []string `json:"roles"`
+
+	//Seconds the master waits for the scheduler to fail over before killing
+	//all its tasks
+	FailoverTimeout float64 `json:"failover_timeout"`
+
+	//Checkpoint makes the agents checkpoint the tasks, so they survive agent
+	//restarts
+	Checkpoint bool `json:"checkpoint"`
+
+	//IDFile is where the FrameworkID is saved to fail over to the same
+	//framework after a restart
+	IDFile string `json:"id_file"`
+
+	//StateFile is where the jobs, the tasks and, without IDFile, the
+	//FrameworkID are saved so a restarted scheduler knows them
+	StateFile string `json:"state_file"`
+
+	//StateStore is the format of the state file: json, the default, or
+	//bolt for a BoltDB database. With postgres the state is in the
+	//PostgreSQL database of StateDSN instead
+	StateStore string `json:"state_store"`
+
+	//StateDSN is the data source name of the database of the postgres
+	//state store
+	StateDSN string `json:"state_dsn"`
+
+	//Reserve reserves dynamically for the role the resources of the tasks, so
+	//they are kept for the framework when the tasks restart
+	Reserve bool `json:"reserve"`
+}
+
+//HAConfig enables running several instances of the scheduler, only the
+//elected leader registers with the master
+type HAConfig struct {
+	//ZooKeeper URL used for the election, zk://host1:port1,host2:port2/path
+	ZK string `json:"zk"`
+
+	//etcd URL used instead of ZooKeeper, etcd://host1:port1,host2:port2/prefix
+	Etcd string `json:"etcd"`
+}
+
+//ReconcileConfig sets how often the scheduler asks the master for the state
+//of all the tasks of the framework
+type ReconcileConfig struct {
+	//Seconds between reconciliations, 0 disables them
+	Interval float64 `json:"interval"`
+
+	//Fraction of the interval added at random to each wait, so several
+	//frameworks don't reconcile all at once
+	Jitter float64 `json:"jitter"`
+}
+
+//AgentFailuresConfig sets when the agents where the tasks keep failing are
+//blacklisted
+type AgentFailuresConfig struct {
+	//Failures of the tasks of an agent in Window seconds that blacklist it
+	MaxFailures int     `json:"max_failures"`
+	Window      float64 `json:"window"`
+
+	//Seconds the agent is blacklisted, doubled each time it is blacklisted
+	//again shortly after, up to MaxBlacklist
+	Blacklist    float64 `json:"blacklist"`
+	MaxBlacklist float64 `json:"max_blacklist"`
+}
+
+//TaskHistoryConfig sets how long the ended tasks are kept, in memory and in
+//the state store
+type TaskHistoryConfig struct {
+	//Seconds a task is kept after it ended, 0 regardless of its age
+	Retention float64 `json:"retention"`
+
+	//Most ended tasks kept, the oldest are pruned first. 0 for no limit
+	MaxTasks int `json:"max_tasks"`
+}
+
+//MetricsConfig sets where the metrics of the scheduler are pushed
+type MetricsConfig struct {
+	//StatsD is the host:port of the StatsD server, empty not to push them
+	StatsD string `json:"statsd"`
+
+	//Prefix goes in front of the name of every metric
+	Prefix string `json:"prefix"`
+
+	//Seconds between two pushes
+	FlushInterval float64 `json:"flush_interval"`
+}
+
+//WebhooksConfig sets the URLs notified of the tasks failing, the
+//deployments ending and the agents blacklisted
+type WebhooksConfig struct {
+	//URLs receive every notification, none to notify nothing
+	URLs []string `json:"urls"`
+
+	//Secret signs the payloads with HMAC-SHA256, empty not to sign them
+	Secret string `json:"secret"`
+
+	//Retries of a payload a URL didn't accept, and the seconds it has to
+	//answer
+	Retries int     `json:"retries"`
+	Timeout float64 `json:"timeout"`
+}
+
+//SlackConfig sets the Slack channel told about the tasks failing, the
+//restart loops, the deployments ending and the agents blacklisted
+type SlackConfig struct {
+	//WebhookURL is the incoming webhook of the channel, empty not to post
+	//to Slack
+	WebhookURL string `json:"webhook_url"`
+
+	//Seconds between two messages about the same thing, the ones in
+	//between are only counted
+	Throttle float64 `json:"throttle"`
+
+	//A job is in a restart loop when LoopFailures of its tasks fail
+	//within LoopWindow seconds
+	LoopFailures int     `json:"loop_failures"`
+	LoopWindow   float64 `json:"loop_window"`
+}
+
+//DeclineConfig sets for how many seconds the master doesn't offer again the
+//resources of the offers the scheduler gives back, depending on why
+type DeclineConfig struct {
+	//No job needs resources
+	Idle float64 `json:"idle"`
+
+	//The offers are too small for the pending tasks
+	Unfit float64 `json:"unfit"`
+
+	//The agent doesn't match the constraints of any pending job
+	Mismatch float64 `json:"mismatch"`
+
+	//The agent is excluded by the host filter
+	Excluded float64 `json:"excluded"`
+
+	//The resources left in the offers accepted to launch tasks
+	Accepted float64 `json:"accepted"`
+}
+
+//ShutdownConfig is what the scheduler does on SIGINT or SIGTERM
+type ShutdownConfig struct {
+	//KillTasks kills every running task before stopping
+	KillTasks bool `json:"kill_tasks"`
+
+	//Failover stops the driver keeping the framework registered for the
+	//failover timeout, so a new scheduler can take its tasks over
+	Failover bool `json:"failover"`
+
+	//Teardown kills every task, unregisters the framework and forgets its
+	//FrameworkID, whatever KillTasks and Failover say, so nothing is left
+	//behind in the cluster
+	Teardown bool `json:"teardown"`
+}
+
+//ExecutorConfig is the information used to fill the mesosproto.ExecutorInfo
+type ExecutorConfig struct {
+	//Command that launches the executor once its URIs are fetched
+	Command string `json:"command"`
+
+	//URIs where the cluster can download/fetch our executor and any other
+	//file it needs
+	URIs []URIConfig `json:"uris"`
+}
+
+//URIConfig is the information used to fill a mesosproto.CommandInfo_URI
+type URIConfig struct {
+	Value      string `json:"value"`
+	Executable bool   `json:"executable"`
+	Extract    bool   `json:"extract"`
+}
+
+//TaskConfig describes the job the scheduler launches at startup and the
+//resources its tasks need. More jobs can be submitted through the API
+type TaskConfig struct {
+	//ID of the job
+	ID string `json:"id"`
+
+	//Type of the job, service or batch
+	Type string `json:"type"`
+
+	//Docker image of the task. Leave it empty to run the task with the
+	//executor
+	DockerImage string            `json:"docker_image"`
+	Command     string            `json:"command"`
+	Args        []string          `json:"args"`
+	Env         map[string]string `json:"env"`
+	Cpus        float64           `json:"cpus"`
+	Mem         float64           `json:"mem"`
+	Disk        float64           `json:"disk"`
+	Gpus        float64           `json:"gpus"`
+
+	//Role whose offers the tasks use when the framework has several roles.
+	//Empty is the first one
+	Role string `json:"role"`
+
+	//Revocable lets the tasks use revocable resources
+	Revocable bool `json:"revocable"`
+
+	//Host ports of each task. Without any, each task takes a single port
+	Ports []PortConfig `json:"ports"`
+
+	//Number of copies of the task to keep running
+	Instances int `json:"instances"`
+
+	//Gang launches all the pending instances at once or none
+	Gang bool `json:"gang"`
+
+	//Indexed runs an array job, each task gets its index in TASK_INDEX
+	Indexed bool `json:"indexed"`
+
+	//Priority of the job, its tasks preempt the ones of lower priority
+	//jobs when they can't find resources
+	Priority int `json:"priority"`
+
+	Restart RestartConfig `json:"restart"`
+
+	//Cron runs the instances on a schedule, none if the schedule is empty
+	Cron CronConfig `json:"cron"`
+
+	Upgrade UpgradeConfig `json:"upgrade"`
+
+	//Seconds a task may run before it is killed, 0 for no limit
+	MaxRuntime float64 `json:"max_runtime"`
+
+	//Seconds a task has to shut down after SIGTERM before SIGKILL, 0 for
+	//the default of the executor
+	KillGracePeriod float64 `json:"kill_grace_period"`
+
+	//Constraints on the agents where the tasks run, each one as
+	//[field, operator, value]
+	Constraints [][]string `json:"constraints"`
+
+	//IDs of the jobs the tasks must run with, and of the ones they must
+	//not
+	Affinity     []string `json:"affinity"`
+	AntiAffinity []string `json:"anti_affinity"`
+
+	//Persistent volume of each task, none if its size is 0
+	Volume VolumeConfig `json:"volume"`
+
+	//Containers of each instance, launched together as a task group.
+	//Only in the config file
+	Containers []ContainerConfig `json:"containers"`
+
+	//Health check Mesos runs on each task, none if the protocol is empty
+	HealthCheck HealthCheckConfig `json:"health_check"`
+
+	//Readiness check the scheduler runs on each task, none if the
+	//protocol is empty
+	Readiness ReadinessConfig `json:"readiness"`
+}
+
+//ReadinessConfig is the readiness check of each task
+type ReadinessConfig struct {
+	//Protocol is http or command
+	Protocol string `json:"protocol"`
+	Path     string `json:"path"`
+	Command  string `json:"command"`
+
+	//Name of the port requested, empty for the first one
+	Port string `json:"port"`
+
+	//Seconds between the checks and to wait for each one, 0 for 5
+	Interval float64 `json:"interval"`
+	Timeout  float64 `json:"timeout"`
+}
+
+//HealthCheckConfig is the health check of each task
+type HealthCheckConfig struct {
+	//Protocol is command, http or tcp
+	Protocol string `json:"protocol"`
+	Command  string `json:"command"`
+	Path     string `json:"path"`
+
+	//Name of the port checked, empty for the first one
+	Port string `json:"port"`
+
+	//Seconds between the checks, to wait for each one and before the
+	//failures count, 0 for the defaults of Mesos
+	Interval    float64 `json:"interval"`
+	Timeout     float64 `json:"timeout"`
+	GracePeriod float64 `json:"grace_period"`
+
+	//Consecutive failures after which the task is killed, 0 for 3
+	MaxFailures int `json:"max_failures"`
+}
+
+//ContainerConfig is a task of the group of each instance
+type ContainerConfig struct {
+	Name        string            `json:"name"`
+	DockerImage string            `json:"docker_image"`
+	Command     string            `json:"command"`
+	Args        []string          `json:"args"`
+	Env         map[string]string `json:"env"`
+	Cpus        float64           `json:"cpus"`
+	Mem         float64           `json:"mem"`
+	Disk        float64           `json:"disk"`
+}
+
+//VolumeConfig is a persistent volume created for each task
+type VolumeConfig struct {
+	//Path inside the sandbox of the task, relative
+	ContainerPath string `json:"container_path"`
+
+	//Size in MB
+	Size float64 `json:"size"`
+}
+
+//PortConfig is a host port of each task
+type PortConfig struct {
+	Name string `json:"name"`
+
+	//Fixed port number, 0 to take any port offered
+	Port uint64 `json:"port"`
+}
+
+//RestartConfig is what the job does when a task ends
+type RestartConfig struct {
+	//Policy is always, on-failure or never
+	Policy string `json:"policy"`
+
+	//Consecutive failures after which the failed tasks aren't replaced, 0
+	//for no limit
+	MaxRetries int `json:"max_retries"`
+
+	//Seconds to wait before replacing a failed task, doubled on every
+	//consecutive failure up to MaxBackoff
+	Backoff    float64 `json:"backoff"`
+	MaxBackoff float64 `json:"max_backoff"`
+}
+
+//CronConfig is the schedule of the runs of the job
+type CronConfig struct {
+	//Schedule is a cron expression, like "*/15 * * * *" or "@daily"
+	Schedule string `json:"schedule"`
+
+	//Concurrency is allow, forbid or replace, what happens when a run is
+	//due while the previous one is in progress
+	Concurrency string `json:"concurrency"`
+
+	//Ended runs kept in the history, 0 for 10
+	HistoryLimit int `json:"history_limit"`
+}
+
+//UpgradeConfig is how the tasks are replaced when the job changes
+type UpgradeConfig struct {
+	//Strategy is rolling or blue-green
+	Strategy string `json:"strategy"`
+
+	//Canary replaces a single task first, and waits CanaryWindow seconds
+	//with it ready and healthy before replacing the rest, 0 for 300
+	Canary       bool    `json:"canary"`
+	CanaryWindow float64 `json:"canary_window"`
+
+	//Manual waits for the approval of the operator before the cutover of
+	//a blue-green deployment, or after the canary
+	Manual bool `json:"manual"`
+
+	//Tasks replaced at a time, 0 for 1
+	BatchSize int `json:"batch_size"`
+
+	//Failures of the new tasks that abort the deployment, 0 for 3
+	MaxFailures int `json:"max_failures"`
+
+	//Rollback goes back to the previous spec when a deployment is aborted
+	Rollback bool `json:"rollback"`
+}
+
+//HostsConfig pins the framework to some agents or excludes others, by
+//hostname
+type HostsConfig struct {
+	Whitelist []string `json:"whitelist"`
+	Blacklist []string `json:"blacklist"`
+}
+
+//CredentialConfig is the principal and secret used to authenticate against
+//the master. When File is set they are read from that Mesos credential file
+type CredentialConfig struct {
+	Principal string `json:"principal"`
+	Secret    string `json:"secret"`
+	File      string `json:"file,omitempty"`
+}
+
+//Default returns the configuration used when no config file is given
+func Default() *Config {
+	return &Config{
+		Master:            "10.0.137.51:5050",
+		APIAddress:        "127.0.0.1:8000",
+		LogLevel:          "info",
+		LogFormat:         "text",
+		Placement:         "first-fit",
+		UnreachableGrace:  300,
+		LaunchTimeout:     300,
+		LostAgentCooldown: 600,
+		PreemptionGrace:   60,
+		Framework: FrameworkConfig{
+			User: "root",
+			Name: "Mesos framework demo by Golang",
+			Role: "marathon",
+		},
+		Reconcile: ReconcileConfig{
+			Interval: 600,
+			Jitter:   0.1,
+		},
+		Decline: DeclineConfig{
+			Idle:     3600,
+			Unfit:    5,
+			Mismatch: 300,
+			Excluded: 600,
+			Accepted: 10,
+		},
+		AgentFailures: AgentFailuresConfig{
+			MaxFailures:  5,
+			Window:       600,
+			Blacklist:    300,
+			MaxBlacklist: 3600,
+		},
+		TaskHistory: TaskHistoryConfig{
+			Retention: 86400,
+			MaxTasks:  1000,
+		},
+		Metrics: MetricsConfig{
+			Prefix:        "mesos.framework",
+			FlushInterval: 10,
+		},
+		Webhooks: WebhooksConfig{
+			Retries: 5,
+			Timeout: 10,
+		},
+		Slack: SlackConfig{
+			Throttle:     300,
+			LoopFailures: 3,
+			LoopWindow:   600,
+		},
+		Shutdown: ShutdownConfig{
+			Failover: true,
+		},
+		Executor: ExecutorConfig{
+			Command: "./executor",
+			URIs: []URIConfig{
+				{Value: "http://s3-eu-west-1.amazonaws.com/enablers/executor", Executable: true},
+			},
+		},
+		Task: TaskConfig{
+			ID:          "default",
+			DockerImage: "index.alauda.cn/alauda/ubuntu",
+			Command:     "sleep 600",
+			Cpus:        0.5,
+			Mem:         128.0,
+			Instances:   1,
+			Restart: RestartConfig{
+				Backoff:    1,
+				MaxBackoff: 300,
+			},
+		},
+	}
+}
+
+//Load reads the JSON file in path on top of the default configuration, so
+//the file only needs to contain the values that change
+func Load(path string) (*Config, error) {
+	c := Default()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %v", path, err)
+	}
+
+	return c, nil
+}
+
+//setting binds a key (the same one used by the command line flag) and an
+//environment variable to the field of the Config it modifies
+type setting struct {
+	key string
+	env string
+	set func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{"master", "MESOS_MASTER", func(c *Config, v string) error { c.Master = v; return nil }},
+	{"api-addr", "API_ADDR", func(c *Config, v string) error { c.APIAddress = v; return nil }},
+	{"api-tokens-file", "API_TOKENS_FILE", func(c *Config, v string) error { c.APITokensFile = v; return nil }},
+	{"api-open-reads", "API_OPEN_READS", func(c *Config, v string) error { return setBool(&c.APIOpenReads, v) }},
+	{"api-tls-cert", "API_TLS_CERT", func(c *Config, v string) error { c.APITLSCert = v; return nil }},
+	{"api-tls-key", "API_TLS_KEY", func(c *Config, v string) error { c.APITLSKey = v; return nil }},
+	{"api-tls-client-ca", "API_TLS_CLIENT_CA", func(c *Config, v string) error { c.APITLSClientCA = v; return nil }},
+	{"grpc-addr", "GRPC_ADDR", func(c *Config, v string) error { c.GRPCAddress = v; return nil }},
+	{"log-level", "LOG_LEVEL", func(c *Config, v string) error { c.LogLevel = v; return nil }},
+	{"log-format", "LOG_FORMAT", func(c *Config, v string) error { c.LogFormat = v; return nil }},
+	{"dry-run", "DRY_RUN", func(c *Config, v string) error { return setBool(&c.DryRun, v) }},
+	{"allow-command-readiness", "ALLOW_COMMAND_READINESS", func(c *Config, v string) error { return setBool(&c.AllowCommandReadiness, v) }},
+	{"placement", "PLACEMENT", func(c *Config, v string) error { c.Placement = v; return nil }},
+	{"unreachable-grace", "UNREACHABLE_GRACE", func(c *Config, v string) error { return setFloat(&c.UnreachableGrace, v) }},
+	{"launch-timeout", "LAUNCH_TIMEOUT", func(c *Config, v string) error { return setFloat(&c.LaunchTimeout, v) }},
+	{"lost-agent-cooldown", "LOST_AGENT_COOLDOWN", func(c *Config, v string) error { return setFloat(&c.LostAgentCooldown, v) }},
+	{"preemption-grace", "PREEMPTION_GRACE", func(c *Config, v string) error { return setFloat(&c.PreemptionGrace, v) }},
+	{"max-queued", "MAX_QUEUED", func(c *Config, v string) error { return setInt(&c.MaxQueued, v) }},
+	{"audit-log", "AUDIT_LOG", func(c *Config, v string) error { c.AuditLog = v; return nil }},
+	{"user", "FRAMEWORK_USER", func(c *Config, v string) error { c.Framework.User = v; return nil }},
+	{"name", "FRAMEWORK_NAME", func(c *Config, v string) error { c.Framework.Name = v; return nil }},
+	{"role", "FRAMEWORK_ROLE", func(c *Config, v string) error { c.Framework.Role = v; return nil }},
+	{"roles", "FRAMEWORK_ROLES", func(c *Config, v string) error { c.Framework.Roles = parseList(v); return nil }},
+	{"failover-timeout", "FRAMEWORK_FAILOVER_TIMEOUT", func(c *Config, v string) error { return setFloat(&c.Framework.FailoverTimeout, v) }},
+	{"checkpoint", "FRAMEWORK_CHECKPOINT", func(c *Config, v string) error { return setBool(&c.Framework.Checkpoint, v) }},
+	{"reserve", "FRAMEWORK_RESERVE", func(c *Config, v string) error { return setBool(&c.Framework.Reserve, v) }},
+	{"framework-id-file", "FRAMEWORK_ID_FILE", func(c *Config, v string) error { c.Framework.IDFile = v; return nil }},
+	{"state-file", "FRAMEWORK_STATE_FILE", func(c *Config, v string) error { c.Framework.StateFile = v; return nil }},
+	{"state-store", "FRAMEWORK_STATE_STORE", func(c *Config, v string) error { c.Framework.StateStore = v; return nil }},
+	{"state-dsn", "FRAMEWORK_STATE_DSN", func(c *Config, v string) error { c.Framework.StateDSN = v; return nil }},
+	{"ha-zk", "HA_ZK", func(c *Config, v string) error { c.HA.ZK = v; return nil }},
+	{"ha-etcd", "HA_ETCD", func(c *Config, v string) error { c.HA.Etcd = v; return nil }},
+	{"reconcile-interval", "RECONCILE_INTERVAL", func(c *Config, v string) error { return setFloat(&c.Reconcile.Interval, v) }},
+	{"reconcile-jitter", "RECONCILE_JITTER", func(c *Config, v string) error { return setFloat(&c.Reconcile.Jitter, v) }},
+	{"decline-idle", "DECLINE_IDLE", func(c *Config, v string) error { return setFloat(&c.Decline.Idle, v) }},
+	{"decline-unfit", "DECLINE_UNFIT", func(c *Config, v string) error { return setFloat(&c.Decline.Unfit, v) }},
+	{"decline-mismatch", "DECLINE_MISMATCH", func(c *Config, v string) error { return setFloat(&c.Decline.Mismatch, v) }},
+	{"decline-excluded", "DECLINE_EXCLUDED", func(c *Config, v string) error { return setFloat(&c.Decline.Excluded, v) }},
+	{"decline-accepted", "DECLINE_ACCEPTED", func(c *Config, v string) error { return setFloat(&c.Decline.Accepted, v) }},
+	{"agent-max-failures", "AGENT_MAX_FAILURES", func(c *Config, v string) error { return setInt(&c.AgentFailures.MaxFailures, v) }},
+	{"agent-failure-window", "AGENT_FAILURE_WINDOW", func(c *Config, v string) error { return setFloat(&c.AgentFailures.Window, v) }},
+	{"agent-blacklist", "AGENT_BLACKLIST", func(c *Config, v string) error { return setFloat(&c.AgentFailures.Blacklist, v) }},
+	{"agent-max-blacklist", "AGENT_MAX_BLACKLIST", func(c *Config, v string) error { return setFloat(&c.AgentFailures.MaxBlacklist, v) }},
+	{"task-history-retention", "TASK_HISTORY_RETENTION", func(c *Config, v string) error { return setFloat(&c.TaskHistory.Retention, v) }},
+	{"task-history-max", "TASK_HISTORY_MAX", func(c *Config, v string) error { return setInt(&c.TaskHistory.MaxTasks, v) }},
+	{"statsd", "STATSD_ADDRESS", func(c *Config, v string) error { c.Metrics.StatsD = v; return nil }},
+	{"metrics-prefix", "METRICS_PREFIX", func(c *Config, v string) error { c.Metrics.Prefix = v; return nil }},
+	{"metrics-flush-interval", "METRICS_FLUSH_INTERVAL", func(c *Config, v string) error { return setFloat(&c.Metrics.FlushInterval, v) }},
+	{"webhooks", "WEBHOOK_URLS", func(c *Config, v string) error { c.Webhooks.URLs = parseList(v); return nil }},
+	{"webhook-secret", "WEBHOOK_SECRET", func(c *Config, v string) error { c.Webhooks.Secret = v; return nil }},
+	{"webhook-retries", "WEBHOOK_RETRIES", func(c *Config, v string) error { return setInt(&c.Webhooks.Retries, v) }},
+	{"webhook-timeout", "WEBHOOK_TIMEOUT", func(c *Config, v string) error { return setFloat(&c.Webhooks.Timeout, v) }},
+	{"slack-webhook", "SLACK_WEBHOOK_URL", func(c *Config, v string) error { c.Slack.WebhookURL = v; return nil }},
+	{"slack-throttle", "SLACK_THROTTLE", func(c *Config, v string) error { return setFloat(&c.Slack.Throttle, v) }},
+	{"slack-loop-failures", "SLACK_LOOP_FAILURES", func(c *Config, v string) error { return setInt(&c.Slack.LoopFailures, v) }},
+	{"slack-loop-window", "SLACK_LOOP_WINDOW", func(c *Config, v string) error { return setFloat(&c.Slack.LoopWindow, v) }},
+	{"kill-on-exit", "KILL_ON_EXIT", func(c *Config, v string) error { return setBool(&c.Shutdown.KillTasks, v) }},
+	{"failover-on-exit", "FAILOVER_ON_EXIT", func(c *Config, v string) error { return setBool(&c.Shutdown.Failover, v) }},
+	{"teardown-on-exit", "TEARDOWN_ON_EXIT", func(c *Config, v string) error { return setBool(&c.Shutdown.Teardown, v) }},
+	{"executor-uri", "EXECUTOR_URI", func(c *Config, v string) error { c.Executor.URIs = parseURIs(v); return nil }},
+	{"executor-command", "EXECUTOR_COMMAND", func(c *Config, v string) error { c.Executor.Command = v; return nil }},
+	{"job-id", "JOB_ID", func(c *Config, v string) error { c.Task.ID = v; return nil }},
+	{"job-type", "TASK_TYPE", func(c *Config, v string) error { c.Task.Type = v; return nil }},
+	{"docker-image", "DOCKER_IMAGE", func(c *Config, v string) error { c.Task.DockerImage = v; return nil }},
+	{"task-command", "TASK_COMMAND", func(c *Config, v string) error { c.Task.Command = v; return nil }},
+	{"cpus", "TASK_CPU", func(c *Config, v string) error { return setFloat(&c.Task.Cpus, v) }},
+	{"mem", "TASK_MEM", func(c *Config, v string) error { return setFloat(&c.Task.Mem, v) }},
+	{"disk", "TASK_DISK", func(c *Config, v string) error { return setFloat(&c.Task.Disk, v) }},
+	{"gpus", "TASK_GPUS", func(c *Config, v string) error { return setFloat(&c.Task.Gpus, v) }},
+	{"max-runtime", "TASK_MAX_RUNTIME", func(c *Config, v string) error { return setFloat(&c.Task.MaxRuntime, v) }},
+	{"kill-grace-period", "TASK_KILL_GRACE_PERIOD", func(c *Config, v string) error { return setFloat(&c.Task.KillGracePeriod, v) }},
+	{"ports", "TASK_PORTS", func(c *Config, v string) (err error) { c.Task.Ports, err = parsePorts(v); return err }},
+	{"job-role", "TASK_ROLE", func(c *Config, v string) error { c.Task.Role = v; return nil }},
+	{"revocable", "TASK_REVOCABLE", func(c *Config, v string) error { return setBool(&c.Task.Revocable, v) }},
+	{"gang", "TASK_GANG", func(c *Config, v string) error { return setBool(&c.Task.Gang, v) }},
+	{"indexed", "TASK_INDEXED", func(c *Config, v string) error { return setBool(&c.Task.Indexed, v) }},
+	{"priority", "TASK_PRIORITY", func(c *Config, v string) error { return setInt(&c.Task.Priority, v) }},
+	{"instances", "TASK_INSTANCES", func(c *Config, v string) error { return setInt(&c.Task.Instances, v) }},
+	{"restart-policy", "TASK_RESTART_POLICY", func(c *Config, v string) error { c.Task.Restart.Policy = v; return nil }},
+	{"max-retries", "TASK_MAX_RETRIES", func(c *Config, v string) error { return setInt(&c.Task.Restart.MaxRetries, v) }},
+	{"backoff", "TASK_BACKOFF", func(c *Config, v string) error { return setFloat(&c.Task.Restart.Backoff, v) }},
+	{"max-backoff", "TASK_MAX_BACKOFF", func(c *Config, v string) error { return setFloat(&c.Task.Restart.MaxBackoff, v) }},
+	{"cron-schedule", "TASK_CRON_SCHEDULE", func(c *Config, v string) error { c.Task.Cron.Schedule = v; return nil }},
+	{"cron-concurrency", "TASK_CRON_CONCURRENCY", func(c *Config, v string) error { c.Task.Cron.Concurrency = v; return nil }},
+	{"cron-history-limit", "TASK_CRON_HISTORY_LIMIT", func(c *Config, v string) error { return setInt(&c.Task.Cron.HistoryLimit, v) }},
+	{"upgrade-strategy", "TASK_UPGRADE_STRATEGY", func(c *Config, v string) error { c.Task.Upgrade.Strategy = v; return nil }},
+	{"upgrade-canary", "TASK_UPGRADE_CANARY", func(c *Config, v string) error { return setBool(&c.Task.Upgrade.Canary, v) }},
+	{"upgrade-canary-window", "TASK_UPGRADE_CANARY_WINDOW", func(c *Config, v string) error { return setFloat(&c.Task.Upgrade.CanaryWindow, v) }},
+	{"upgrade-manual", "TASK_UPGRADE_MANUAL", func(c *Config, v string) error { return setBool(&c.Task.Upgrade.Manual, v) }},
+	{"upgrade-batch-size", "TASK_UPGRADE_BATCH_SIZE", func(c *Config, v string) error { return setInt(&c.Task.Upgrade.BatchSize, v) }},
+	{"upgrade-max-failures", "TASK_UPGRADE_MAX_FAILURES", func(c *Config, v string) error { return setInt(&c.Task.Upgrade.MaxFailures, v) }},
+	{"upgrade-rollback", "TASK_UPGRADE_ROLLBACK", func(c *Config, v string) error { return setBool(&c.Task.Upgrade.Rollback, v) }},
+	{"constraint", "TASK_CONSTRAINTS", func(c *Config, v string) error { c.Task.Constraints = parseConstraints(v); return nil }},
+	{"host-whitelist", "HOST_WHITELIST", func(c *Config, v string) error { c.Hosts.Whitelist = parseList(v); return nil }},
+	{"host-blacklist", "HOST_BLACKLIST", func(c *Config, v string) error { c.Hosts.Blacklist = parseList(v); return nil }},
+	{"affinity", "TASK_AFFINITY", func(c *Config, v string) error { c.Task.Affinity = parseList(v); return nil }},
+	{"anti-affinity", "TASK_ANTI_AFFINITY", func(c *Config, v string) error { c.Task.AntiAffinity = parseList(v); return nil }},
+	{"volume-path", "TASK_VOLUME_PATH", func(c *Config, v string) error { c.Task.Volume.ContainerPath = v; return nil }},
+	{"volume-size", "TASK_VOLUME_SIZE", func(c *Config, v string) error { return setFloat(&c.Task.Volume.Size, v) }},
+	{"health-protocol", "TASK_HEALTH_PROTOCOL", func(c *Config, v string) error { c.Task.HealthCheck.Protocol = v; return nil }},
+	{"health-command", "TASK_HEALTH_COMMAND", func(c *Config, v string) error { c.Task.HealthCheck.Command = v; return nil }},
+	{"health-path", "TASK_HEALTH_PATH", func(c *Config, v string) error { c.Task.HealthCheck.Path = v; return nil }},
+	{"health-port", "TASK_HEALTH_PORT", func(c *Config, v string) error { c.Task.HealthCheck.Port = v; return nil }},
+	{"health-interval", "TASK_HEALTH_INTERVAL", func(c *Config, v string) error { return setFloat(&c.Task.HealthCheck.Interval, v) }},
+	{"health-timeout", "TASK_HEALTH_TIMEOUT", func(c *Config, v string) error { return setFloat(&c.Task.HealthCheck.Timeout, v) }},
+	{"health-grace", "TASK_HEALTH_GRACE", func(c *Config, v string) error { return setFloat(&c.Task.HealthCheck.GracePeriod, v) }},
+	{"health-max-failures", "TASK_HEALTH_MAX_FAILURES", func(c *Config, v string) error { return setInt(&c.Task.HealthCheck.MaxFailures, v) }},
+	{"readiness-protocol", "TASK_READINESS_PROTOCOL", func(c *Config, v string) error { c.Task.Readiness.Protocol = v; return nil }},
+	{"readiness-path", "TASK_READINESS_PATH", func(c *Config, v string) error { c.Task.Readiness.Path = v; return nil }},
+	{"readiness-command", "TASK_READINESS_COMMAND", func(c *Config, v string) error { c.Task.Readiness.Command = v; return nil }},
+	{"readiness-port", "TASK_READINESS_PORT", func(c *Config, v string) error { c.Task.Readiness.Port = v; return nil }},
+	{"readiness-interval", "TASK_READINESS_INTERVAL", func(c *Config, v string) error { return setFloat(&c.Task.Readiness.Interval, v) }},
+	{"readiness-timeout", "TASK_READINESS_TIMEOUT", func(c *Config, v string) error { return setFloat(&c.Task.Readiness.Timeout, v) }},
+	{"principal", "MESOS_PRINCIPAL", func(c *Config, v string) error { c.Credential.Principal = v; return nil }},
+	{"secret", "MESOS_SECRET", func(c *Config, v string) error { c.Credential.Secret = v; return nil }},
+	{"credential-file", "MESOS_CREDENTIAL_FILE", func(c *Config, v string) error { c.Credential.File = v; return nil }},
+}
+
+//Set overrides a single value of the configuration. Keys are the names of
+//the command line flags, so the flags explicitly set by the user can be
+//applied with flag.Visit
+func (c *Config) Set(key, value string) error {
+	for _, s := range settings {
+		if s.key == key {
+			if err := s.set(c, value); err != nil {
+				return fmt.Errorf("invalid value %q for %s: %v", value, key, err)
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown config key %s", key)
+}
+
+//ApplyEnv overrides the configuration with every environment variable bound
+//to a setting that is present in the environment. lookup is usually
+//os.LookupEnv
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	for _, s := range settings {
+		value, ok := lookup(s.env)
+		if !ok {
+			continue
+		}
+
+		if err := s.set(c, value); err != nil {
+			return fmt.Errorf("invalid value %q for %s: %v", value, s.env, err)
+		}
+	}
+
+	return nil
+}
+
+//EnvVar returns the environment variable bound to key, or an empty string
+//if there is none
+func EnvVar(key string) string {
+	for _, s := range settings {
+		if s.key == key {
+			return s.env
+		}
+	}
+
+	return ""
+}
+
+//parseList parses a comma separated list, ignoring the empty items
+func parseList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+//parseConstraints parses a list of constraints separated by semicolons,
+//each one as "field OPERATOR value"
+func parseConstraints(value string) [][]string {
+	var constraints [][]string
+	for _, c := range strings.Split(value, ";") {
+		parts := strings.Fields(c)
+		if len(parts) == 0 {
+			continue
+		}
+		if len(parts) > 3 {
+			parts = append(parts[:2], strings.Join(parts[2:], " "))
+		}
+
+		constraints = append(constraints, parts)
+	}
+
+	return constraints
+}
+
+//parsePorts parses a comma separated list of ports, each one as name,
+//name:number or :number
+func parsePorts(value string) ([]PortConfig, error) {
+	var ports []PortConfig
+	for _, item := range parseList(value) {
+		port := PortConfig{Name: item}
+		if i := strings.LastIndex(item, ":"); i >= 0 {
+			number, err := strconv.ParseUint(item[i+1:], 10, 16)
+			if err != nil {
+				return nil, err
+			}
+			port.Name, port.Port = item[:i], number
+		}
+
+		ports = append(ports, port)
+	}
+
+	return ports, nil
+}
+
+func setFloat(dst *float64, value string) error {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+
+	*dst = f
+	return nil
+}
+
+func setInt(dst *int, value string) error {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+
+	*dst = i
+	return nil
+}
+
+func setBool(dst *bool, value string) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+
+	*dst = b
+	return nil
+}
+
+//archiveExtensions are the files the Mesos fetcher knows how to extract
+var archiveExtensions = []string{".tar", ".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz", ".zip"}
+
+//parseURIs reads a comma separated list of URIs. As flags and environment
+//variables can't express the fetch options, archives are extracted and any
+//other file is fetched as an executable
+func parseURIs(value string) []URIConfig {
+	var uris []URIConfig
+
+	for _, uri := range strings.Split(value, ",") {
+		uri = strings.TrimSpace(uri)
+		if uri == "" {
+			continue
+		}
+
+		archive := false
+		for _, ext := range archiveExtensions {
+			if strings.HasSuffix(uri, ext) {
+				archive = true
+				break
+			}
+		}
+
+		uris = append(uris, URIConfig{Value: uri, Executable: !archive, Extract: archive})
+	}
+
+	return uris
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+//LoadCredential reads a Mesos credential file. Both formats accepted by the
+//master are supported: a text file with "principal secret" lines or a JSON
+//file with a "credentials" array. A plain {"principal", "secret"} JSON
+//object is also accepted. The first credential found is returned
+func LoadCredential(path string) (*CredentialConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		return parseJSONCredential(path, data)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("credential file %s: expected \"principal secret\" lines", path)
+		}
+
+		return &CredentialConfig{Principal: fields[0], Secret: fields[1]}, nil
+	}
+
+	return nil, fmt.Errorf("credential file %s has no credentials", path)
+}
+
+func parseJSONCredential(path string, data []byte) (*CredentialConfig, error) {
+	var file struct {
+		Credentials []CredentialConfig `json:"credentials"`
+		CredentialConfig
+	}
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing credential file %s: %v", path, err)
+	}
+
+	if len(file.Credentials) > 0 {
+		return &file.Credentials[0], nil
+	}
+
+	if file.Principal == "" {
+		return nil, fmt.Errorf("credential file %s has no credentials", path)
+	}
+
+	return &file.CredentialConfig, nil
+}
@@ -0,0 +1,473 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"minimal-mesos-go-framework/cron"
+)
+
+//checkTimeout bounds every network check done by CheckReachable
+const checkTimeout = 5 * time.Second
+
+//dockerImageRegexp matches a Docker image reference:
+//[registry[:port]/]name[/name...][:tag][@digest]
+var dockerImageRegexp = regexp.MustCompile(`^` +
+	`(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?/)?` +
+	`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+	`(?::[\w][\w.-]{0,127})?` +
+	`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?` +
+	`$`)
+
+//ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+//Validate checks the configuration before the driver is created: the
+//resources of the task, the Docker image reference, the address of the
+//master and the executor URIs when the task uses the executor. It doesn't
+//connect anywhere, CheckReachable does. All the problems found are reported
+//in a single ValidationError
+func (c *Config) Validate() error {
+	var problems []string
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		addf("unknown log level %q, use debug, info, warn or error (--log-level)", c.LogLevel)
+	}
+	if c.LogFormat != "text" && c.LogFormat != "json" {
+		addf("unknown log format %q, use text or json (--log-format)", c.LogFormat)
+	}
+
+	if c.Framework.FailoverTimeout < 0 {
+		addf("failover timeout can't be negative, got %v (--failover-timeout)", c.Framework.FailoverTimeout)
+	}
+
+	if len(c.Framework.Roles) == 0 {
+		if c.Framework.Reserve && (c.Framework.Role == "" || c.Framework.Role == "*") {
+			addf("resources can only be reserved for a role, set one with --role (--reserve)")
+		}
+		if c.Task.Role != "" {
+			addf("the job can only choose a role when the framework has several (--job-role, --roles)")
+		}
+	} else {
+		for _, role := range c.Framework.Roles {
+			if c.Framework.Reserve && role == "*" {
+				addf("resources can't be reserved for the * role (--reserve, --roles)")
+			}
+		}
+		if c.Task.Role != "" && !contains(c.Framework.Roles, c.Task.Role) {
+			addf("role %s of the job is not one of the roles of the framework (--job-role)", c.Task.Role)
+		}
+	}
+
+	if c.HA.ZK != "" && !strings.HasPrefix(c.HA.ZK, "zk://") {
+		addf("%q is not a zk:// URL (--ha-zk)", c.HA.ZK)
+	}
+	if c.HA.Etcd != "" && !strings.HasPrefix(c.HA.Etcd, "etcd://") {
+		addf("%q is not an etcd:// URL (--ha-etcd)", c.HA.Etcd)
+	}
+	if c.HA.ZK != "" && c.HA.Etcd != "" {
+		addf("the leader is elected with ZooKeeper or with etcd, not both (--ha-zk, --ha-etcd)")
+	}
+	if (c.HA.ZK != "" || c.HA.Etcd != "") && (c.Framework.StateFile != "" || c.Framework.StateStore == "postgres") {
+		addf("the state is kept next to the election with HA, it can't be in a file or a database too (--state-file, --state-store)")
+	}
+	switch c.Framework.StateStore {
+	case "", "json", "bolt":
+		if c.Framework.StateDSN != "" {
+			addf("the data source name is only for the postgres state store (--state-dsn)")
+		}
+	case "postgres":
+		if c.Framework.StateDSN == "" {
+			addf("the postgres state store needs the data source name of the database (--state-dsn)")
+		}
+		if c.Framework.StateFile != "" {
+			addf("the postgres state store keeps the state in the database, not in a file (--state-file)")
+		}
+	default:
+		addf("unknown state store %q, use json, bolt or postgres (--state-store)", c.Framework.StateStore)
+	}
+
+	if c.UnreachableGrace < 0 {
+		addf("unreachable grace can't be negative, got %v (--unreachable-grace)", c.UnreachableGrace)
+	}
+
+	if c.LaunchTimeout < 0 {
+		addf("launch timeout can't be negative, got %v (--launch-timeout)", c.LaunchTimeout)
+	}
+
+	if c.LostAgentCooldown < 0 {
+		addf("lost agent cool-down can't be negative, got %v (--lost-agent-cooldown)", c.LostAgentCooldown)
+	}
+
+	if c.PreemptionGrace < 0 {
+		addf("preemption grace can't be negative, got %v (--preemption-grace)", c.PreemptionGrace)
+	}
+
+	if (c.APITLSCert == "") != (c.APITLSKey == "") {
+		addf("the API needs both a certificate and a key for TLS (--api-tls-cert, --api-tls-key)")
+	}
+	if c.APITLSClientCA != "" && c.APITLSCert == "" {
+		addf("client certificates can only be verified over TLS (--api-tls-client-ca, --api-tls-cert)")
+	}
+
+	if c.GRPCAddress != "" && c.GRPCAddress == c.APIAddress {
+		addf("the gRPC API needs its own address, %s is the one of the API (--grpc-addr, --api-addr)", c.GRPCAddress)
+	}
+
+	if c.APIOpenReads && c.APITokensFile == "" {
+		addf("api open reads only applies with an api tokens file (--api-open-reads, --api-tokens-file)")
+	}
+
+	if c.MaxQueued < 0 {
+		addf("max queued can't be negative, got %v (--max-queued)", c.MaxQueued)
+	}
+
+	if c.Reconcile.Interval < 0 {
+		addf("reconcile interval can't be negative, got %v (--reconcile-interval)", c.Reconcile.Interval)
+	}
+	if c.Reconcile.Jitter < 0 || c.Reconcile.Jitter > 1 {
+		addf("reconcile jitter must be between 0 and 1, got %v (--reconcile-jitter)", c.Reconcile.Jitter)
+	}
+
+	if c.Decline.Idle < 0 || c.Decline.Unfit < 0 || c.Decline.Mismatch < 0 || c.Decline.Excluded < 0 || c.Decline.Accepted < 0 {
+		addf("decline filters can't be negative (--decline-idle, --decline-unfit, --decline-mismatch, --decline-excluded, --decline-accepted)")
+	}
+
+	if c.AgentFailures.MaxFailures < 0 || c.AgentFailures.Window < 0 || c.AgentFailures.Blacklist < 0 || c.AgentFailures.MaxBlacklist < 0 {
+		addf("agent failure settings can't be negative (--agent-max-failures, --agent-failure-window, --agent-blacklist, --agent-max-blacklist)")
+	}
+
+	if c.TaskHistory.Retention < 0 || c.TaskHistory.MaxTasks < 0 {
+		addf("task history settings can't be negative (--task-history-retention, --task-history-max)")
+	}
+
+	if c.Metrics.StatsD != "" {
+		if _, _, err := net.SplitHostPort(c.Metrics.StatsD); err != nil {
+			addf("statsd must be host:port, got %q (--statsd)", c.Metrics.StatsD)
+		}
+		if c.Metrics.FlushInterval <= 0 {
+			addf("metrics flush interval must be greater than 0, got %v (--metrics-flush-interval)", c.Metrics.FlushInterval)
+		}
+	}
+	if strings.ContainsAny(c.Metrics.Prefix, ":|@ \n") {
+		addf("metrics prefix can't contain ':', '|', '@' or spaces, got %q (--metrics-prefix)", c.Metrics.Prefix)
+	}
+
+	for _, u := range c.Webhooks.URLs {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			addf("webhook URLs must be http or https URLs, got %q (--webhooks)", u)
+		}
+	}
+	if c.Webhooks.Retries < 0 {
+		addf("webhook retries can't be negative, got %d (--webhook-retries)", c.Webhooks.Retries)
+	}
+	if len(c.Webhooks.URLs) > 0 && c.Webhooks.Timeout <= 0 {
+		addf("webhook timeout must be greater than 0, got %v (--webhook-timeout)", c.Webhooks.Timeout)
+	}
+
+	if c.Slack.WebhookURL != "" {
+		if parsed, err := url.Parse(c.Slack.WebhookURL); err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			addf("the Slack webhook must be an https URL, got %q (--slack-webhook)", c.Slack.WebhookURL)
+		}
+		if c.Slack.Throttle < 0 || c.Slack.LoopWindow < 0 {
+			addf("Slack throttle and loop window can't be negative (--slack-throttle, --slack-loop-window)")
+		}
+		if c.Slack.LoopFailures < 1 {
+			addf("Slack loop failures must be at least 1, got %d (--slack-loop-failures)", c.Slack.LoopFailures)
+		}
+	}
+
+	if c.Task.ID == "" {
+		addf("the job id can't be empty (--job-id)")
+	}
+	if c.Task.Cpus <= 0 {
+		addf("cpus must be greater than 0, got %v (--cpus)", c.Task.Cpus)
+	}
+	if c.Task.Mem <= 0 {
+		addf("mem must be greater than 0, got %v (--mem)", c.Task.Mem)
+	}
+	if c.Task.Disk < 0 {
+		addf("disk can't be negative, got %v (--disk)", c.Task.Disk)
+	}
+	if c.Task.Gpus < 0 {
+		addf("gpus can't be negative, got %v (--gpus)", c.Task.Gpus)
+	}
+	if c.Task.Gpus > 0 && c.Task.DockerImage != "" {
+		addf("gpus are only supported by tasks without a Docker image (--gpus)")
+	}
+	if c.Task.Instances < 0 {
+		addf("instances can't be negative, got %d (--instances)", c.Task.Instances)
+	}
+	if c.Task.MaxRuntime < 0 {
+		addf("max runtime can't be negative, got %v (--max-runtime)", c.Task.MaxRuntime)
+	}
+	if c.Task.KillGracePeriod < 0 {
+		addf("kill grace period can't be negative, got %v (--kill-grace-period)", c.Task.KillGracePeriod)
+	}
+
+	switch c.Task.Type {
+	case "", "service", "batch":
+	default:
+		addf("unknown job type %q, use service or batch (--job-type)", c.Task.Type)
+	}
+
+	switch c.Task.Restart.Policy {
+	case "", "always", "on-failure", "never":
+	default:
+		addf("unknown restart policy %q, use always, on-failure or never (--restart-policy)", c.Task.Restart.Policy)
+	}
+	if c.Task.Cron.Schedule != "" {
+		if _, err := cron.Parse(c.Task.Cron.Schedule); err != nil {
+			addf("%v (--cron-schedule)", err)
+		}
+		switch c.Task.Cron.Concurrency {
+		case "", "allow", "forbid", "replace":
+		default:
+			addf("unknown concurrency policy %q, use allow, forbid or replace (--cron-concurrency)", c.Task.Cron.Concurrency)
+		}
+		if c.Task.Cron.HistoryLimit < 0 {
+			addf("cron history limit can't be negative, got %d (--cron-history-limit)", c.Task.Cron.HistoryLimit)
+		}
+		if c.Task.Type != "batch" {
+			addf("cron jobs must be batch jobs (--job-type)")
+		}
+		if c.Task.Restart.Policy == "always" {
+			addf("cron jobs need the on-failure or never restart policy (--restart-policy)")
+		}
+	}
+	if c.Task.Indexed {
+		if c.Task.Type != "batch" {
+			addf("indexed jobs must be batch jobs (--job-type)")
+		}
+		if c.Task.Cron.Schedule != "" {
+			addf("indexed jobs can't have a cron schedule (--cron-schedule)")
+		}
+		if c.Task.DockerImage == "" && len(c.Task.Containers) == 0 {
+			addf("indexed jobs need a Docker image or containers, the executor doesn't get the index (--indexed)")
+		}
+	}
+	if c.Task.Restart.MaxRetries < 0 {
+		addf("max retries can't be negative, got %d (--max-retries)", c.Task.Restart.MaxRetries)
+	}
+	if c.Task.Restart.Backoff < 0 || c.Task.Restart.MaxBackoff < 0 {
+		addf("backoff can't be negative (--backoff, --max-backoff)")
+	}
+	switch c.Task.Upgrade.Strategy {
+	case "", "rolling", "blue-green":
+	default:
+		addf("unknown upgrade strategy %q, use rolling or blue-green (--upgrade-strategy)", c.Task.Upgrade.Strategy)
+	}
+	if c.Task.Upgrade.BatchSize < 0 || c.Task.Upgrade.MaxFailures < 0 || c.Task.Upgrade.CanaryWindow < 0 {
+		addf("upgrade settings can't be negative (--upgrade-batch-size, --upgrade-max-failures, --upgrade-canary-window)")
+	}
+	if c.Task.Upgrade.Canary && c.Task.Upgrade.Strategy == "blue-green" {
+		addf("canaries are only supported by rolling deployments (--upgrade-canary)")
+	}
+
+	for _, constraint := range c.Task.Constraints {
+		if len(constraint) < 2 || len(constraint) > 3 {
+			addf("invalid constraint %q, use field OPERATOR [value] (--constraint)", strings.Join(constraint, " "))
+		}
+	}
+
+	if c.Task.Volume.Size < 0 {
+		addf("volume size can't be negative, got %v (--volume-size)", c.Task.Volume.Size)
+	}
+	if c.Task.Volume.Size > 0 {
+		if c.Task.Volume.ContainerPath == "" || strings.HasPrefix(c.Task.Volume.ContainerPath, "/") {
+			addf("the volume needs a relative container path (--volume-path)")
+		}
+		if !c.Framework.Reserve {
+			addf("persistent volumes need reserved resources (--reserve)")
+		}
+	}
+
+	if c.Task.HealthCheck.Protocol != "" {
+		h := c.Task.HealthCheck
+		switch {
+		case h.Protocol != "command" && h.Protocol != "http" && h.Protocol != "tcp":
+			addf("unknown health check protocol %q, use command, http or tcp (--health-protocol)", h.Protocol)
+		case h.Protocol == "command" && h.Command == "":
+			addf("a command health check needs a command (--health-command)")
+		}
+		if h.Interval < 0 || h.Timeout < 0 || h.GracePeriod < 0 || h.MaxFailures < 0 {
+			addf("health check settings can't be negative (--health-interval, --health-timeout, --health-grace, --health-max-failures)")
+		}
+		if c.Task.DockerImage == "" && len(c.Task.Containers) == 0 {
+			addf("health checks need a Docker image or containers, the executor doesn't run them (--health-protocol)")
+		}
+	}
+
+	if c.Task.Readiness.Protocol != "" {
+		r := c.Task.Readiness
+		switch {
+		case r.Protocol != "http" && r.Protocol != "command":
+			addf("unknown readiness check protocol %q, use http or command (--readiness-protocol)", r.Protocol)
+		case r.Protocol == "command" && r.Command == "":
+			addf("a command readiness check needs a command (--readiness-command)")
+		case r.Protocol == "command" && !c.AllowCommandReadiness:
+			addf("command readiness checks run on the scheduler host and need --allow-command-readiness")
+		}
+		if r.Interval < 0 || r.Timeout < 0 {
+			addf("readiness check settings can't be negative (--readiness-interval, --readiness-timeout)")
+		}
+	}
+
+	if c.Task.DockerImage != "" && !dockerImageRegexp.MatchString(c.Task.DockerImage) {
+		addf("%q is not a valid Docker image reference (--docker-image)", c.Task.DockerImage)
+	}
+
+	if _, err := masterAddrs(c.Master); err != nil {
+		addf("master %s: %v (--master)", c.Master, err)
+	}
+
+	//The executor is only used by the tasks without a Docker image, pods
+	//use the default executor of Mesos
+	if c.Task.DockerImage == "" && len(c.Task.Containers) == 0 {
+		if c.Executor.Command == "" {
+			addf("the executor command can't be empty when no Docker image is set (--executor-command)")
+		}
+		if len(c.Executor.URIs) == 0 {
+			addf("at least one executor URI is needed when no Docker image is set (--executor-uri)")
+		}
+		for _, uri := range c.Executor.URIs {
+			if _, err := url.Parse(uri.Value); err != nil {
+				addf("executor URI %s: %v (--executor-uri)", uri.Value, err)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+//CheckReachable checks, once the configuration is valid, that the master is
+//reachable and that the executor URIs can be fetched when the task uses the
+//executor. Only the startup of the scheduler needs them, the commands
+//working on the state store don't. The problems are reported in a single
+//ValidationError
+func (c *Config) CheckReachable() error {
+	var problems []string
+	if err := checkMaster(c.Master); err != nil {
+		problems = append(problems, fmt.Sprintf("master %s: %v (--master)", c.Master, err))
+	}
+
+	if c.Task.DockerImage == "" && len(c.Task.Containers) == 0 {
+		for _, uri := range c.Executor.URIs {
+			if err := checkURI(uri.Value); err != nil {
+				problems = append(problems, fmt.Sprintf("executor URI %s: %v (--executor-uri)", uri.Value, err))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+//masterAddrs returns the address of the master, or the ones of the
+//ZooKeeper servers when the master is a zk:// URL
+func masterAddrs(master string) ([]string, error) {
+	if master == "" {
+		return nil, fmt.Errorf("the address can't be empty")
+	}
+
+	var addrs []string
+	if strings.HasPrefix(master, "zk://") {
+		hosts := strings.TrimPrefix(master, "zk://")
+		if i := strings.Index(hosts, "/"); i >= 0 {
+			hosts = hosts[:i]
+		}
+		if i := strings.LastIndex(hosts, "@"); i >= 0 {
+			hosts = hosts[i+1:]
+		}
+		addrs = strings.Split(hosts, ",")
+	} else {
+		addrs = []string{strings.TrimPrefix(master, "master@")}
+	}
+
+	for _, addr := range addrs {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return nil, err
+		}
+	}
+
+	return addrs, nil
+}
+
+//checkMaster verifies that the master, or at least one of the ZooKeeper
+//servers when the master is a zk:// URL, accepts connections
+func checkMaster(master string) error {
+	addrs, err := masterAddrs(master)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		var conn net.Conn
+		if conn, err = net.DialTimeout("tcp", addr, checkTimeout); err == nil {
+			conn.Close()
+			return nil
+		}
+	}
+
+	return fmt.Errorf("not reachable: %v", err)
+}
+
+//checkURI verifies that an executor URI can be fetched, for HTTP URIs
+func checkURI(rawurl string) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+
+	//Local paths, hdfs://, s3://... are fetched by the agents with their
+	//own tools, we can't check them from here
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil
+	}
+
+	client := &http.Client{Timeout: checkTimeout}
+	resp, err := client.Head(rawurl)
+	if err != nil {
+		return fmt.Errorf("not resolvable: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("not resolvable: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 
@@ -20,13 +21,7 @@ type exampleExecutor struct {}
 func (e *exampleExecutor) LaunchTask(driver executor.ExecutorDriver, taskInfo *mesosproto.TaskInfo) {
 	fmt.Printf("Launching task %v with data [%#x]\n", taskInfo.GetName(), taskInfo.Data)
 
-	var port string
-
-	for _, resource := range taskInfo.Resources {
-		if resource.GetName() == "ports" {
-			port = strconv.FormatUint(resource.GetRanges().GetRange()[0].GetBegin(), 10)
-		}
-	}
+	port := serverPort(taskInfo)
 
 	//Send a status update to the scheduler
 	runStatus := &mesosproto.TaskStatus{
@@ -37,6 +32,7 @@ func (e *exampleExecutor) LaunchTask(driver executor.ExecutorDriver, taskInfo *m
 	if err != nil {
 		fmt.Println("Got error", err)
 	}
+	sendMessage(driver, taskInfo, "serving", "port "+port)
 
 	launchMyServer(taskInfo.Data, port)
 
@@ -55,6 +51,49 @@ func (e *exampleExecutor) LaunchTask(driver executor.ExecutorDriver, taskInfo *m
 	fmt.Println("Task finished", taskInfo.GetName())
 }
 
+//serverPort returns the port the server listens on: the one named http in
+//the DiscoveryInfo of the task or, without it, the first port of the task
+func serverPort(taskInfo *mesosproto.TaskInfo) string {
+	for _, p := range taskInfo.GetDiscovery().GetPorts().GetPorts() {
+		if p.GetName() == "http" {
+			return strconv.FormatUint(uint64(p.GetNumber()), 10)
+		}
+	}
+
+	for _, resource := range taskInfo.Resources {
+		if resource.GetName() == "ports" {
+			return strconv.FormatUint(resource.GetRanges().GetRange()[0].GetBegin(), 10)
+		}
+	}
+
+	return ""
+}
+
+//taskMessage is the envelope of the framework messages sent to the
+//scheduler about a task
+type taskMessage struct {
+	TaskID string `json:"task_id"`
+	Type   string `json:"type"`
+	Data   string `json:"data,omitempty"`
+}
+
+//sendMessage sends a framework message about the task to the scheduler
+func sendMessage(driver executor.ExecutorDriver, taskInfo *mesosproto.TaskInfo, kind, data string) {
+	msg, err := json.Marshal(taskMessage{
+		TaskID: taskInfo.GetTaskId().GetValue(),
+		Type:   kind,
+		Data:   data,
+	})
+	if err != nil {
+		fmt.Println("Got error", err)
+		return
+	}
+
+	if _, err := driver.SendFrameworkMessage(string(msg)); err != nil {
+		fmt.Println("Got error", err)
+	}
+}
+
 func init() {
 	flag.Parse()
 }
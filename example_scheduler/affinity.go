@@ -0,0 +1,78 @@
+package example_scheduler
+
+import (
+	"fmt"
+
+	"github.com/mesos/mesos-go/mesosproto"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//validateAffinity checks that the job doesn't refer to itself
+func (j *JobSpec) validateAffinity() error {
+	for _, id := range append(append([]string{}, j.Affinity...), j.AntiAffinity...) {
+		if id == j.ID {
+			return fmt.Errorf("job %s can't have affinity or anti-affinity with itself", j.ID)
+		}
+	}
+
+	return nil
+}
+
+//acceptsAffinity reports if a task of the job can run on the agent of the
+//offer: the agent must run a task of every job in its Affinity and none of
+//the jobs in its AntiAffinity. Anti-affinity goes both ways, a job can't
+//land on an agent running a job that refuses it either. The caller must
+//hold the mutex
+func (s *ExampleScheduler) acceptsAffinity(job *JobSpec, offer *mesosproto.Offer) bool {
+	if len(job.Affinity) == 0 && len(job.AntiAffinity) == 0 && !s.hasAntiAffinity(job.ID) {
+		return true
+	}
+
+	//The jobs with tasks staging or running on the agent
+	onAgent := make(map[string]bool)
+	for _, t := range s.tasks {
+		if t.agentId == offer.SlaveId.GetValue() && !tasks.IsTerminal(t.State) {
+			onAgent[t.jobId] = true
+		}
+	}
+
+	for _, id := range job.Affinity {
+		if !onAgent[id] {
+			return false
+		}
+	}
+	for _, id := range job.AntiAffinity {
+		if onAgent[id] {
+			return false
+		}
+	}
+	for id := range onAgent {
+		if other := s.job(id); other != nil && contains(other.AntiAffinity, job.ID) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//hasAntiAffinity reports if any job refuses to run with the given one. The
+//caller must hold the mutex
+func (s *ExampleScheduler) hasAntiAffinity(jobId string) bool {
+	for _, job := range s.jobs {
+		if contains(job.AntiAffinity, jobId) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
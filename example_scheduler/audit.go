@@ -0,0 +1,232 @@
+package example_scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//auditKept is the number of audit entries kept in memory for the API
+const auditKept = 10000
+
+//The actions recorded in the audit log
+const (
+	AuditAccept   = "accept"
+	AuditDecline  = "decline"
+	AuditLaunch   = "launch"
+	AuditKill     = "kill"
+	AuditUnplaced = "unplaced"
+
+	//AuditRequest is a call of the API changing the scheduler
+	AuditRequest = "request"
+)
+
+//AuditEntry is a scheduling decision and why it was taken
+type AuditEntry struct {
+	Seq    int64     `json:"seq"`
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+
+	JobID    string   `json:"job_id,omitempty"`
+	TaskID   string   `json:"task_id,omitempty"`
+	AgentID  string   `json:"agent_id,omitempty"`
+	Hostname string   `json:"hostname,omitempty"`
+	OfferIDs []string `json:"offer_ids,omitempty"`
+
+	//Principal is who called the API, for the requests
+	Principal string `json:"principal,omitempty"`
+
+	Reason string `json:"reason,omitempty"`
+}
+
+//AuditFilter selects audit entries. The fields left empty match every
+//entry
+type AuditFilter struct {
+	Action  string
+	JobID   string
+	TaskID  string
+	AgentID string
+	Since   time.Time
+
+	//Limit keeps the last entries matching, 0 for all of them
+	Limit int
+}
+
+//matches reports if the entry passes the filter
+func (f *AuditFilter) matches(e *AuditEntry) bool {
+	return (f.Action == "" || e.Action == f.Action) &&
+		(f.JobID == "" || e.JobID == f.JobID) &&
+		(f.TaskID == "" || e.TaskID == f.TaskID) &&
+		(f.AgentID == "" || e.AgentID == f.AgentID) &&
+		!e.Time.Before(f.Since)
+}
+
+//audit appends the entry to the audit log, in memory and, if set, to the
+//AuditLog writer. The caller must hold the mutex
+func (s *ExampleScheduler) audit(e AuditEntry) {
+	s.auditSeq++
+	e.Seq, e.Time = s.auditSeq, time.Now()
+
+	s.auditLog = append(s.auditLog, e)
+	if len(s.auditLog) > auditKept {
+		s.auditLog = append([]AuditEntry{}, s.auditLog[len(s.auditLog)-auditKept:]...)
+	}
+
+	switch e.Action {
+	case AuditAccept:
+		s.offerStats.accepted += int64(len(e.OfferIDs))
+	case AuditDecline:
+		s.offerStats.declined += int64(len(e.OfferIDs))
+	}
+
+	//The offers accepted and declined are events too
+	if e.Action == AuditAccept || e.Action == AuditDecline {
+		s.publish(Event{
+			Type:     e.Action,
+			JobID:    e.JobID,
+			AgentID:  e.AgentID,
+			Hostname: e.Hostname,
+			OfferIDs: e.OfferIDs,
+			Reason:   e.Reason,
+		})
+	}
+
+	if s.AuditLog == nil {
+		return
+	}
+	line, err := json.Marshal(&e)
+	if err == nil {
+		_, err = s.AuditLog.Write(append(line, '\n'))
+	}
+	if err != nil {
+		log.WithError(err).Errorln("Unable to write the audit log")
+	}
+}
+
+//auditOffers records a decision about the offers of an agent. The caller
+//must hold the mutex
+func (s *ExampleScheduler) auditOffers(action string, offers []*mesosproto.Offer, reason string) {
+	if len(offers) == 0 {
+		return
+	}
+
+	s.audit(AuditEntry{
+		Action:   action,
+		AgentID:  offers[0].SlaveId.GetValue(),
+		Hostname: offers[0].GetHostname(),
+		OfferIDs: auditOfferIDs(offers),
+		Reason:   reason,
+	})
+}
+
+//auditOfferIDs returns the IDs of the offers
+func auditOfferIDs(offers []*mesosproto.Offer) []string {
+	ids := make([]string, len(offers))
+	for i, offer := range offers {
+		ids[i] = offer.Id.GetValue()
+	}
+
+	return ids
+}
+
+//auditAllOffers records the decline of every offer in the pool. The caller
+//must hold the mutex
+func (s *ExampleScheduler) auditAllOffers(reason string) {
+	for _, held := range s.offers {
+		s.auditOffers(AuditDecline, heldOffers(held), reason)
+	}
+}
+
+//auditUnplaced records why an instance of the job wasn't placed, unless it
+//is the same reason as the last time. The caller must hold the mutex
+func (s *ExampleScheduler) auditUnplaced(job *JobSpec, reason string) {
+	if s.unplaced[job.ID] == reason {
+		return
+	}
+
+	s.unplaced[job.ID] = reason
+	s.audit(AuditEntry{Action: AuditUnplaced, JobID: job.ID, Reason: reason})
+}
+
+//unfitReason explains why no agent takes a task of the job, one reason by
+//agent. The caller must hold the mutex
+func (s *ExampleScheduler) unfitReason(agents []*agentOffers, job *JobSpec) string {
+	if len(agents) == 0 {
+		return "no offers"
+	}
+
+	reasons := make([]string, len(agents))
+	for i, agent := range agents {
+		reasons[i] = agent.offers[0].GetHostname() + ": " + s.agentUnfitReason(agent, job)
+	}
+
+	return "no agent fits: " + strings.Join(reasons, "; ")
+}
+
+//agentUnfitReason explains why the agent doesn't take a task of the job,
+//the first check that fails in the order of selectAgent. The caller must
+//hold the mutex
+func (s *ExampleScheduler) agentUnfitReason(agent *agentOffers, job *JobSpec) string {
+	res := s.resOf(agent, job)
+	for _, name := range []string{"cpus", "mem", "disk", "gpus"} {
+		if available, needed := res.available(name, job), job.needs(name); available < needed {
+			return fmt.Sprintf("not enough %s, %v offered for %v", name, available, needed)
+		}
+	}
+	if !res.fitsPorts(job) {
+		return "the ports of the job aren't offered"
+	}
+	if !s.fitsVolume(agent, job) {
+		return "no room for the persistent volume"
+	}
+
+	fields := offerFields(agent.offers[0])
+	active := s.activeTasks(job.ID)
+	for _, c := range job.Constraints {
+		if !c.accepts(fields, active) {
+			return fmt.Sprintf("constraint %q not met", c)
+		}
+	}
+	if !s.acceptsAffinity(job, agent.offers[0]) {
+		return "affinity not met"
+	}
+
+	return "unknown"
+}
+
+//Audit returns the entries of the audit log kept in memory that match the
+//filter, oldest first
+func (s *ExampleScheduler) Audit(filter AuditFilter) []AuditEntry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entries := []AuditEntry{}
+	for i := range s.auditLog {
+		if filter.matches(&s.auditLog[i]) {
+			entries = append(entries, s.auditLog[i])
+		}
+	}
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+
+	return entries
+}
+
+//AuditRequest records a call of the API changing the scheduler, with the
+//principal that made it, empty if the API has no authentication, and the
+//status code of the answer
+func (s *ExampleScheduler) AuditRequest(principal, method, path string, status int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.audit(AuditEntry{
+		Action:    AuditRequest,
+		Principal: principal,
+		Reason:    fmt.Sprintf("%s %s: %d", method, path, status),
+	})
+}
@@ -0,0 +1,133 @@
+package example_scheduler
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//defaultCanaryWindow is how long the canary must stay ready and healthy
+//when CanaryWindow isn't set
+const defaultCanaryWindow = 5 * time.Minute
+
+//CanarySummary is the canary of a deployment exposed to the operators
+type CanarySummary struct {
+	TaskID string `json:"task_id"`
+	State  string `json:"state"`
+
+	//When the canary became ready and healthy, zero if it isn't
+	ReadySince time.Time `json:"ready_since"`
+
+	//Promoted is set once the rest of the tasks are being replaced
+	Promoted bool `json:"promoted"`
+}
+
+func (u *UpgradeStrategy) canaryWindow() time.Duration {
+	if u.CanaryWindow > 0 {
+		return time.Duration(u.CanaryWindow * float64(time.Second))
+	}
+
+	return defaultCanaryWindow
+}
+
+//canaryTask returns the canary of the deployment among its new tasks: the
+//current one if it is still alive, otherwise the first launched
+func canaryTask(d *deployment, fresh []*taskRecord) *taskRecord {
+	var canary *taskRecord
+	for _, t := range fresh {
+		if t.id == d.canary {
+			return t
+		}
+		if canary == nil || t.launched.Before(canary.launched) {
+			canary = t
+		}
+	}
+
+	return canary
+}
+
+//holdCanary reports if the deployment must wait on its canary, the first
+//new task, before replacing the rest: until it has been ready and healthy
+//for the canary window or, with a manual strategy, until the operator
+//approves it. The caller must hold the mutex
+func (s *ExampleScheduler) holdCanary(d *deployment, job *JobSpec, fresh []*taskRecord) bool {
+	canary := canaryTask(d, fresh)
+	if canary == nil {
+		return false
+	}
+
+	dlog := log.WithFields(log.Fields{
+		"job_id":  d.jobId,
+		"version": d.version,
+		"task_id": canary.id,
+	})
+	if canary.id != d.canary {
+		d.canary = canary.id
+		d.canaryReady = time.Time{}
+		dlog.Infoln("Canary launched")
+	}
+
+	if !s.deployReady(canary, job) {
+		if !d.canaryReady.IsZero() || d.state == DeploymentWaiting {
+			dlog.Warnln("Canary not ready anymore")
+		}
+		d.canaryReady = time.Time{}
+		s.setDeploymentState(d, DeploymentRunning)
+		return true
+	}
+
+	now := time.Now()
+	if d.canaryReady.IsZero() {
+		d.canaryReady = now
+		d.updated = now
+		dlog.Infoln("Canary ready")
+	}
+
+	switch {
+	case d.state == DeploymentWaiting:
+		return true
+	case job.Upgrade.Manual:
+		s.setDeploymentState(d, DeploymentWaiting)
+		d.updated = now
+		dlog.Infoln("Canary ready, waiting for the approval of the deployment")
+		return true
+	case now.Sub(d.canaryReady) < job.Upgrade.canaryWindow():
+		return true
+	}
+
+	s.promoteCanary(d)
+	return false
+}
+
+//promoteCanary lets the deployment replace the rest of the tasks. The
+//caller must hold the mutex
+func (s *ExampleScheduler) promoteCanary(d *deployment) {
+	d.promoted = true
+	s.setDeploymentState(d, DeploymentRunning)
+	d.updated = time.Now()
+
+	log.WithFields(log.Fields{
+		"job_id":  d.jobId,
+		"version": d.version,
+		"task_id": d.canary,
+	}).Infoln("Canary promoted, replacing the rest of the tasks")
+}
+
+//canarySummary describes the canary of the deployment, nil if it has
+//none. The caller must hold the mutex
+func (s *ExampleScheduler) canarySummary(d *deployment) *CanarySummary {
+	if d.canary == "" {
+		return nil
+	}
+
+	summary := &CanarySummary{
+		TaskID:     d.canary,
+		ReadySince: d.canaryReady,
+		Promoted:   d.promoted,
+	}
+	if t, ok := s.tasks[d.canary]; ok {
+		summary.State = t.State.String()
+	}
+
+	return summary
+}
@@ -0,0 +1,223 @@
+package example_scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//The operators of the constraints
+const (
+	//OperatorLike accepts the agents whose field matches the regular
+	//expression in the value
+	OperatorLike = "LIKE"
+
+	//OperatorUnlike accepts the agents whose field doesn't match the
+	//regular expression in the value
+	OperatorUnlike = "UNLIKE"
+
+	//OperatorUnique accepts the agents whose field has a value no other
+	//active task of the job has. With hostname it runs one task per agent
+	OperatorUnique = "UNIQUE"
+
+	//OperatorGroupBy spreads the tasks of the job evenly across the values
+	//of the field. The optional value is the number of distinct values to
+	//expect, so the first tasks wait for an offer of each of them
+	OperatorGroupBy = "GROUP_BY"
+)
+
+//hostnameField is the constraint field matched against the hostname of the
+//agent instead of one of its attributes
+const hostnameField = "hostname"
+
+//Constraint restricts the agents where the tasks of a job run, evaluated
+//against the attributes of the offers like the Marathon constraints. In
+//JSON it is an array: ["rack", "LIKE", "rack-1"]
+type Constraint struct {
+	//Field is the name of an agent attribute or hostname
+	Field    string
+	Operator string
+	Value    string
+}
+
+//ParseConstraint parses a constraint written as "field OPERATOR value"
+func ParseConstraint(s string) (Constraint, error) {
+	parts := strings.Fields(s)
+	if len(parts) < 2 {
+		return Constraint{}, fmt.Errorf("invalid constraint %q, use: field OPERATOR [value]", s)
+	}
+
+	c := Constraint{Field: parts[0], Operator: parts[1]}
+	if len(parts) > 2 {
+		c.Value = strings.Join(parts[2:], " ")
+	}
+
+	return c, c.Validate()
+}
+
+//String returns the constraint as "field OPERATOR value"
+func (c Constraint) String() string {
+	return strings.TrimSpace(c.Field + " " + c.Operator + " " + c.Value)
+}
+
+//MarshalJSON writes the constraint as an array
+func (c Constraint) MarshalJSON() ([]byte, error) {
+	parts := []string{c.Field, c.Operator}
+	if c.Value != "" {
+		parts = append(parts, c.Value)
+	}
+
+	return json.Marshal(parts)
+}
+
+//UnmarshalJSON reads the constraint from an array
+func (c *Constraint) UnmarshalJSON(data []byte) error {
+	var parts []string
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return err
+	}
+	if len(parts) < 2 || len(parts) > 3 {
+		return fmt.Errorf("invalid constraint %s, use [field, operator, value]", data)
+	}
+
+	c.Field, c.Operator, c.Value = parts[0], parts[1], ""
+	if len(parts) == 3 {
+		c.Value = parts[2]
+	}
+
+	return nil
+}
+
+//Validate checks the operator and its value
+func (c Constraint) Validate() error {
+	if c.Field == "" {
+		return fmt.Errorf("constraint %q has no field", c)
+	}
+
+	switch c.Operator {
+	case OperatorLike, OperatorUnlike:
+		if _, err := regexp.Compile("^(?:" + c.Value + ")$"); err != nil {
+			return fmt.Errorf("constraint %q: %v", c, err)
+		}
+	case OperatorUnique:
+		if c.Value != "" {
+			return fmt.Errorf("constraint %q: %s takes no value", c, c.Operator)
+		}
+	case OperatorGroupBy:
+		if c.Value != "" {
+			if n, err := strconv.Atoi(c.Value); err != nil || n < 1 {
+				return fmt.Errorf("constraint %q: %s takes the number of groups", c, c.Operator)
+			}
+		}
+	default:
+		return fmt.Errorf("constraint %q has an unknown operator %s", c, c.Operator)
+	}
+
+	return nil
+}
+
+//accepts reports if an agent with the given fields satisfies the
+//constraint. active are the tasks of the job that are staging or running.
+//An agent without the attribute never matches
+func (c Constraint) accepts(fields map[string]string, active []*taskRecord) bool {
+	value, ok := fields[c.Field]
+
+	switch c.Operator {
+	case OperatorLike:
+		return ok && matches(c.Value, value)
+	case OperatorUnlike:
+		return !ok || !matches(c.Value, value)
+	case OperatorUnique:
+		if !ok {
+			return false
+		}
+		for _, t := range active {
+			if used, ok := t.fields[c.Field]; ok && used == value {
+				return false
+			}
+		}
+		return true
+	case OperatorGroupBy:
+		return ok && c.acceptsGroup(value, active)
+	}
+
+	return false
+}
+
+//acceptsGroup reports if a task in the group of value keeps the tasks of
+//the job evenly spread. The distribution is computed from the active
+//tasks, so it changes as tasks die or are launched
+func (c Constraint) acceptsGroup(value string, active []*taskRecord) bool {
+	counts := make(map[string]int)
+	for _, t := range active {
+		if group, ok := t.fields[c.Field]; ok {
+			counts[group]++
+		}
+	}
+
+	//A group without tasks is always the least used one
+	if counts[value] == 0 {
+		return true
+	}
+
+	//Until every expected group has a task, wait for offers of the others
+	if groups, _ := strconv.Atoi(c.Value); len(counts) < groups {
+		return false
+	}
+
+	for _, n := range counts {
+		if n < counts[value] {
+			return false
+		}
+	}
+
+	return true
+}
+
+//acceptsOffer reports if the agent of the offer satisfies every constraint
+//of the job. The caller must hold the mutex
+func (s *ExampleScheduler) acceptsOffer(job *JobSpec, offer *mesosproto.Offer) bool {
+	if len(job.Constraints) == 0 {
+		return true
+	}
+
+	fields := offerFields(offer)
+	active := s.activeTasks(job.ID)
+	for _, c := range job.Constraints {
+		if !c.accepts(fields, active) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//offerFields returns the hostname and the attributes of the agent of the
+//offer, as text
+func offerFields(offer *mesosproto.Offer) map[string]string {
+	fields := map[string]string{hostnameField: offer.GetHostname()}
+
+	for _, attr := range offer.GetAttributes() {
+		switch {
+		case attr.Text != nil:
+			fields[attr.GetName()] = attr.Text.GetValue()
+		case attr.Scalar != nil:
+			fields[attr.GetName()] = strconv.FormatFloat(attr.Scalar.GetValue(), 'f', -1, 64)
+		case attr.Set != nil:
+			fields[attr.GetName()] = strings.Join(attr.Set.GetItem(), ",")
+		}
+	}
+
+	return fields
+}
+
+//matches reports if value matches the whole regular expression. The
+//expression was checked by Validate
+func matches(expr, value string) bool {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	return err == nil && re.MatchString(value)
+}
@@ -0,0 +1,74 @@
+package example_scheduler
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//convergeInterval is how often the controller compares the desired and the
+//actual state of the jobs
+const convergeInterval = 5 * time.Second
+
+//RunController drives the tasks of every job towards its number of
+//instances. The missing instances, including the ones of crashed tasks, are
+//launched on the next offers; the tasks above the number of instances, for
+//example the ones found by a reconciliation, are killed here. It never
+//returns, run it in its own goroutine
+func (s *ExampleScheduler) RunController() {
+	for range time.Tick(convergeInterval) {
+		s.mutex.Lock()
+		s.converge()
+		s.mutex.Unlock()
+	}
+}
+
+//converge replaces the tasks unreachable for too long, gives the offers
+//held in the pool another chance, declining the ones held for too long,
+//checks the readiness of the running tasks, retries the kills that didn't
+//complete, moves the deployments forward, kills the tasks that didn't
+//start in time and the ones running for too long, preempts lower priority
+//tasks for the jobs waiting for resources and kills the excess tasks of
+//every job. The deployments, the preemptions and the excess tasks wait
+//while the scheduling is paused. The caller must hold the mutex
+func (s *ExampleScheduler) converge() {
+	if s.driver == nil || s.disconnected {
+		return
+	}
+	s.replaceUnreachable()
+	s.runCrons()
+	s.reviveIfNeeded(false)
+	s.placeTasks(s.driver)
+	s.checkReadiness()
+
+	//Until the reconciliation ends we don't know which tasks are running
+	if s.stopping || s.isReconciling() {
+		return
+	}
+	s.retryKills()
+	s.killStuckLaunches()
+	s.killExpired()
+
+	//While paused no task replaces the ones killed, so the deployments,
+	//the preemptions and the scaling down wait for the resume
+	if !s.paused {
+		s.advanceDeployments()
+		s.saveDeployments()
+		s.preemptTasks()
+	}
+
+	for _, job := range s.jobs {
+		if !s.paused {
+			if err := s.killExcess(job); err != nil {
+				log.WithField("job_id", job.ID).WithError(err).Errorln("Unable to kill the excess tasks")
+			}
+		}
+
+		if pending := s.pendingInstances(job); pending > 0 {
+			log.WithField("job_id", job.ID).Debugf("%d instances pending to launch", pending)
+		}
+		if ready := s.readyInstances(job); ready < job.Instances {
+			log.WithField("job_id", job.ID).Debugf("%d of %d instances ready", ready, job.Instances)
+		}
+	}
+}
@@ -0,0 +1,378 @@
+package example_scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/cron"
+)
+
+//The concurrency policies of a cron job, what happens when a run is due
+//while the previous one is still in progress
+const (
+	//ConcurrencyAllow starts the new run alongside the previous one
+	ConcurrencyAllow = "allow"
+
+	//ConcurrencyForbid skips the new run
+	ConcurrencyForbid = "forbid"
+
+	//ConcurrencyReplace kills the tasks of the previous run and starts
+	//the new one
+	ConcurrencyReplace = "replace"
+)
+
+//The states of a run of a cron job
+const (
+	RunRunning   = "running"
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+	RunReplaced  = "replaced"
+	RunSkipped   = "skipped"
+)
+
+//defaultRunHistory is the number of ended runs kept of each cron job when
+//HistoryLimit isn't set
+const defaultRunHistory = 10
+
+//ErrNotCron is returned when asking for the runs of a job without a cron
+//schedule
+var ErrNotCron = errors.New("the job has no cron schedule")
+
+//CronSpec makes a job run on a schedule: at each activation a run launches
+//the instances of the job, which run to completion
+type CronSpec struct {
+	//Schedule is a cron expression, like "*/15 * * * *" or "@daily", in
+	//the local time of the scheduler
+	Schedule string `json:"schedule"`
+
+	//Concurrency is allow, forbid or replace. Empty is allow
+	Concurrency string `json:"concurrency,omitempty"`
+
+	//HistoryLimit is the number of ended runs kept. 0 is 10
+	HistoryLimit int `json:"history_limit,omitempty"`
+}
+
+//validate checks the schedule and the policies of the cron job. Its tasks
+//must end for good for the runs to end, so it must be a batch job that
+//doesn't restart them always
+func (c *CronSpec) validate(job *JobSpec) error {
+	if _, err := cron.Parse(c.Schedule); err != nil {
+		return err
+	}
+
+	switch c.Concurrency {
+	case "", ConcurrencyAllow, ConcurrencyForbid, ConcurrencyReplace:
+	default:
+		return fmt.Errorf("unknown concurrency policy %q, use %s, %s or %s", c.Concurrency, ConcurrencyAllow, ConcurrencyForbid, ConcurrencyReplace)
+	}
+
+	switch {
+	case c.HistoryLimit < 0:
+		return errors.New("history limit can't be negative")
+	case !job.isBatch():
+		return fmt.Errorf("cron jobs must be %s jobs", JobBatch)
+	case job.restartPolicy() != RestartOnFailure && job.restartPolicy() != RestartNever:
+		return fmt.Errorf("cron jobs need the %s or %s restart policy", RestartOnFailure, RestartNever)
+	}
+
+	return nil
+}
+
+//historyLimit returns the number of ended runs kept
+func (c *CronSpec) historyLimit() int {
+	if c.HistoryLimit > 0 {
+		return c.HistoryLimit
+	}
+
+	return defaultRunHistory
+}
+
+//cronRun is a run of a cron job
+type cronRun struct {
+	number    int
+	scheduled time.Time
+	ended     time.Time
+	state     string
+
+	//The tasks launched by the run, and how many of its instances ended
+	//for good and how many of them finished successfully
+	tasks     []string
+	done      int
+	succeeded int
+}
+
+//cronState is what the scheduler tracks of a cron job
+type cronState struct {
+	expr     string
+	schedule *cron.Schedule
+	next     time.Time
+
+	//The runs of the job, in the order they were scheduled, and the
+	//number of the last one
+	runs []*cronRun
+	last int
+}
+
+//CronRunSummary describes a run of a cron job
+type CronRunSummary struct {
+	Run       int       `json:"run"`
+	Scheduled time.Time `json:"scheduled"`
+	Ended     time.Time `json:"ended"`
+	State     string    `json:"state"`
+	Tasks     []string  `json:"tasks"`
+	Succeeded int       `json:"succeeded"`
+}
+
+//cronState returns the state of the cron job, parsing its schedule again if
+//it changed. The caller must hold the mutex
+func (s *ExampleScheduler) cronState(job *JobSpec) *cronState {
+	c, ok := s.crons[job.ID]
+	if !ok {
+		c = &cronState{}
+		s.crons[job.ID] = c
+	}
+
+	if c.expr != job.Cron.Schedule {
+		schedule, err := cron.Parse(job.Cron.Schedule)
+		if err != nil {
+			//The spec was validated, it can't happen
+			log.WithField("job_id", job.ID).WithError(err).Errorln("Invalid cron schedule")
+			return c
+		}
+		c.expr, c.schedule = job.Cron.Schedule, schedule
+		c.next = schedule.Next(time.Now())
+		log.WithFields(log.Fields{
+			"job_id":   job.ID,
+			"schedule": c.expr,
+		}).Infof("Next run of the cron job at %v", c.next)
+	}
+
+	return c
+}
+
+//runCrons starts the runs of the cron jobs that are due and ends the runs
+//whose instances are all done. The caller must hold the mutex
+func (s *ExampleScheduler) runCrons() {
+	for _, job := range s.jobs {
+		if job.Cron == nil {
+			continue
+		}
+
+		c := s.cronState(job)
+		for _, run := range c.runs {
+			if run.state == RunRunning && run.done >= job.Instances && len(s.runTasks(job, run)) == 0 {
+				s.endRun(job, run)
+			}
+		}
+
+		if c.schedule != nil && !c.next.IsZero() && !time.Now().Before(c.next) {
+			s.startRun(job, c)
+			c.next = c.schedule.Next(time.Now())
+		}
+		s.trimRuns(job, c)
+	}
+}
+
+//startRun starts a run of the cron job, applying its concurrency policy to
+//the runs still in progress. The caller must hold the mutex
+func (s *ExampleScheduler) startRun(job *JobSpec, c *cronState) {
+	c.last++
+	run := &cronRun{
+		number:    c.last,
+		scheduled: c.next,
+		state:     RunRunning,
+	}
+	rlog := log.WithFields(log.Fields{
+		"job_id": job.ID,
+		"run":    run.number,
+	})
+
+	var running []*cronRun
+	for _, r := range c.runs {
+		if r.state == RunRunning {
+			running = append(running, r)
+		}
+	}
+
+	switch {
+	case len(running) == 0 || job.Cron.Concurrency == "" || job.Cron.Concurrency == ConcurrencyAllow:
+	case job.Cron.Concurrency == ConcurrencyForbid:
+		run.state, run.ended = RunSkipped, time.Now()
+		c.runs = append(c.runs, run)
+		rlog.Warnln("Run skipped, the previous one is still in progress")
+		return
+	case job.Cron.Concurrency == ConcurrencyReplace:
+		for _, r := range running {
+			r.state, r.ended = RunReplaced, time.Now()
+			for _, t := range s.runTasks(job, r) {
+				if err := s.kill(t, "replaced by a new run of the cron job"); err != nil {
+					taskLog(t).WithError(err).Errorln("Unable to kill the task")
+				}
+			}
+			rlog.WithField("replaced_run", r.number).Warnln("Replacing the run still in progress")
+		}
+	}
+
+	//Each run gets all its retries and no backoff of the previous ones
+	delete(s.restarts, job.ID)
+	c.runs = append(c.runs, run)
+	s.reviveIfNeeded(true)
+	rlog.Infof("Run started with %d instances", job.Instances)
+}
+
+//endRun records the end of a run whose instances are all done. The caller
+//must hold the mutex
+func (s *ExampleScheduler) endRun(job *JobSpec, run *cronRun) {
+	run.state, run.ended = RunSucceeded, time.Now()
+	if run.succeeded < job.Instances {
+		run.state = RunFailed
+	}
+
+	rlog := log.WithFields(log.Fields{
+		"job_id":    job.ID,
+		"run":       run.number,
+		"succeeded": run.succeeded,
+	})
+	if run.state == RunFailed {
+		rlog.Errorln("Run failed")
+	} else {
+		rlog.Infoln("Run succeeded")
+	}
+}
+
+//trimRuns forgets the oldest ended runs beyond the history limit of the
+//job. The caller must hold the mutex
+func (s *ExampleScheduler) trimRuns(job *JobSpec, c *cronState) {
+	ended := 0
+	for _, r := range c.runs {
+		if r.state != RunRunning {
+			ended++
+		}
+	}
+
+	kept := c.runs[:0]
+	for _, r := range c.runs {
+		if r.state != RunRunning && ended > job.Cron.historyLimit() {
+			ended--
+			continue
+		}
+		kept = append(kept, r)
+	}
+	c.runs = kept
+}
+
+//runTasks returns the active tasks of a run. The caller must hold the
+//mutex
+func (s *ExampleScheduler) runTasks(job *JobSpec, run *cronRun) []*taskRecord {
+	var tasks []*taskRecord
+	for _, t := range s.activeTasks(job.ID) {
+		if t.run == run.number {
+			tasks = append(tasks, t)
+		}
+	}
+
+	return tasks
+}
+
+//runPending returns the instances of the run to launch. The caller must
+//hold the mutex
+func (s *ExampleScheduler) runPending(job *JobSpec, run *cronRun) int {
+	pending := job.Instances - run.done - len(s.runTasks(job, run))
+	if pending < 0 {
+		return 0
+	}
+
+	return pending
+}
+
+//cronPending returns the instances to launch of the runs of the cron job in
+//progress. The caller must hold the mutex
+func (s *ExampleScheduler) cronPending(job *JobSpec) int {
+	c, ok := s.crons[job.ID]
+	if !ok {
+		return 0
+	}
+
+	var pending int
+	for _, run := range c.runs {
+		if run.state == RunRunning {
+			pending += s.runPending(job, run)
+		}
+	}
+
+	return pending
+}
+
+//assignRun adds a task just placed to the oldest run of its cron job that
+//needs it. The caller must hold the mutex
+func (s *ExampleScheduler) assignRun(job *JobSpec, t *taskRecord) {
+	c, ok := s.crons[job.ID]
+	if !ok {
+		return
+	}
+
+	for _, run := range c.runs {
+		if run.state == RunRunning && s.runPending(job, run) > 0 {
+			t.run = run.number
+			run.tasks = append(run.tasks, t.id)
+			return
+		}
+	}
+}
+
+//instanceDone records that the instance of the task won't be replaced, and
+//if it succeeded. The caller must hold the mutex
+func (s *ExampleScheduler) instanceDone(t *taskRecord, succeeded bool) {
+	r := s.restartState(t.jobId)
+	r.done++
+	if succeeded {
+		r.succeeded++
+	}
+	if job := s.job(t.jobId); job != nil && job.Indexed {
+		r.indexes[t.index] = succeeded
+	}
+
+	c, ok := s.crons[t.jobId]
+	if !ok || t.run == 0 {
+		return
+	}
+	for _, run := range c.runs {
+		if run.number == t.run && run.state == RunRunning {
+			run.done++
+			if succeeded {
+				run.succeeded++
+			}
+		}
+	}
+}
+
+//Runs returns the runs of a cron job, the runs in progress and the last
+//ones ended, in the order they were scheduled
+func (s *ExampleScheduler) Runs(jobId string) ([]CronRunSummary, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job := s.job(jobId)
+	if job == nil {
+		return nil, ErrUnknownJob
+	}
+	if job.Cron == nil {
+		return nil, ErrNotCron
+	}
+
+	summaries := []CronRunSummary{}
+	for _, run := range s.cronState(job).runs {
+		summaries = append(summaries, CronRunSummary{
+			Run:       run.number,
+			Scheduled: run.scheduled,
+			Ended:     run.ended,
+			State:     run.state,
+			Tasks:     append([]string{}, run.tasks...),
+			Succeeded: run.succeeded,
+		})
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,88 @@
+package example_scheduler
+
+//DeclinePolicy chooses for how many seconds the master doesn't offer again
+//the resources of the offers we give back, depending on why they weren't
+//used. The fields left at 0 use the defaults
+type DeclinePolicy struct {
+	//No job needs resources. The offers are revived as soon as a job
+	//needs them again
+	Idle float64
+
+	//The offers are too small for the pending tasks, more resources may
+	//be freed soon
+	Unfit float64
+
+	//The agent doesn't match the constraints of any pending job. The
+	//offers are revived when a job changes or a task ends
+	Mismatch float64
+
+	//The agent is excluded by the host filter
+	Excluded float64
+
+	//The resources left in the offers accepted to launch tasks
+	Accepted float64
+}
+
+//DefaultDeclinePolicy are the filters used for the fields of a
+//DeclinePolicy left at 0
+var DefaultDeclinePolicy = DeclinePolicy{
+	Idle:     3600,
+	Unfit:    5,
+	Mismatch: 300,
+	Excluded: 600,
+	Accepted: 10,
+}
+
+//declineReason is why the offers are given back
+type declineReason int
+
+const (
+	//Short waits, like the end of a reconciliation or a dry run
+	declineWaiting declineReason = iota
+	declineIdle
+	declineUnfit
+	declineMismatch
+	declineExcluded
+	declineAccepted
+)
+
+//refuseSeconds returns the filter of the reason
+func (p *DeclinePolicy) refuseSeconds(reason declineReason) float64 {
+	var value, defaultValue float64
+	switch reason {
+	case declineIdle:
+		value, defaultValue = p.Idle, DefaultDeclinePolicy.Idle
+	case declineUnfit:
+		value, defaultValue = p.Unfit, DefaultDeclinePolicy.Unfit
+	case declineMismatch:
+		value, defaultValue = p.Mismatch, DefaultDeclinePolicy.Mismatch
+	case declineExcluded:
+		value, defaultValue = p.Excluded, DefaultDeclinePolicy.Excluded
+	case declineAccepted:
+		value, defaultValue = p.Accepted, DefaultDeclinePolicy.Accepted
+	default:
+		return 1
+	}
+
+	if value > 0 {
+		return value
+	}
+
+	return defaultValue
+}
+
+//matchesPendingJob reports if the agent matches the constraints of any job
+//with instances to launch, so its offers may be used once they have more
+//resources. The caller must hold the mutex
+func (s *ExampleScheduler) matchesPendingJob(agent *agentOffers, planned map[string]int) bool {
+	for _, job := range s.jobs {
+		if s.pendingInstances(job) <= planned[job.ID] {
+			continue
+		}
+		if s.acceptsOffer(job, agent.offers[0]) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,527 @@
+package example_scheduler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/store"
+)
+
+//The states of a deployment
+const (
+	DeploymentRunning  = "running"
+	DeploymentWaiting  = "waiting"
+	DeploymentFinished = "finished"
+	DeploymentAborted  = "aborted"
+
+	//DeploymentRolledBack is a deployment undone by a rollback
+	DeploymentRolledBack = "rolled-back"
+)
+
+//The strategies of a deployment
+const (
+	//UpgradeRolling replaces the tasks a batch at a time
+	UpgradeRolling = "rolling"
+
+	//UpgradeBlueGreen launches all the new tasks alongside the old ones
+	//and kills the old ones at once when the new ones are ready
+	UpgradeBlueGreen = "blue-green"
+
+	//UpgradeScale is the strategy of the deployments started by scaling a
+	//job, which replace no task: they finish once the job runs its new
+	//number of instances. It can't be chosen for a job
+	UpgradeScale = "scale"
+)
+
+//ErrNotWaiting is returned when approving a deployment that isn't waiting
+//for it
+var ErrNotWaiting = errors.New("no deployment of the job is waiting for approval")
+
+//Defaults of the UpgradeStrategy fields left empty
+const (
+	defaultBatchSize          = 1
+	defaultDeploymentFailures = 3
+)
+
+//UpgradeStrategy decides how the running tasks of a job are replaced when
+//its spec changes
+type UpgradeStrategy struct {
+	//Strategy is rolling or blue-green. Empty is rolling
+	Strategy string `json:"strategy,omitempty"`
+
+	//Canary makes a rolling deployment replace a single task first, the
+	//canary, and wait for it to be ready and healthy for CanaryWindow
+	//seconds before replacing the rest. 0 is 5 minutes
+	Canary       bool    `json:"canary,omitempty"`
+	CanaryWindow float64 `json:"canary_window,omitempty"`
+
+	//Manual makes a deployment wait for the approval of the operator
+	//instead: before killing the old tasks of a blue-green deployment, or
+	//before replacing the rest of the tasks after the canary
+	Manual bool `json:"manual,omitempty"`
+
+	//BatchSize is the number of tasks replaced at a time. The next batch
+	//waits for the new tasks to be running, ready and healthy. 0 is 1
+	BatchSize int `json:"batch_size,omitempty"`
+
+	//MaxFailures is the number of failures of the new tasks that abort
+	//the deployment, leaving the old tasks left running. 0 is 3
+	MaxFailures int `json:"max_failures,omitempty"`
+
+	//Rollback makes an aborted deployment roll back to the previous spec
+	//of the job
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+//Validate checks the values of the strategy
+func (u *UpgradeStrategy) Validate() error {
+	switch u.Strategy {
+	case "", UpgradeRolling, UpgradeBlueGreen:
+	default:
+		return fmt.Errorf("unknown upgrade strategy %q, use %s or %s", u.Strategy, UpgradeRolling, UpgradeBlueGreen)
+	}
+
+	if u.BatchSize < 0 || u.MaxFailures < 0 || u.CanaryWindow < 0 {
+		return errors.New("upgrade batch size, max failures and canary window can't be negative")
+	}
+	if u.Canary && u.Strategy == UpgradeBlueGreen {
+		return errors.New("canaries are only supported by rolling deployments")
+	}
+
+	return nil
+}
+
+func (u *UpgradeStrategy) batchSize() int {
+	if u.BatchSize > 0 {
+		return u.BatchSize
+	}
+
+	return defaultBatchSize
+}
+
+func (u *UpgradeStrategy) maxFailures() int {
+	if u.MaxFailures > 0 {
+		return u.MaxFailures
+	}
+
+	return defaultDeploymentFailures
+}
+
+//deployment is the replacement of the tasks of a job after a change of
+//its spec. The tasks launched with the new spec have its version, the
+//older ones are killed a batch at a time or, in a blue-green deployment,
+//all at once when the cutover comes
+type deployment struct {
+	jobId    string
+	version  int
+	strategy string
+	state    string
+	started  time.Time
+	updated  time.Time
+
+	//cutover is set when the old tasks of a blue-green deployment are
+	//killed
+	cutover bool
+
+	//The canary task, since when it is ready and whether it was promoted
+	canary      string
+	canaryReady time.Time
+	promoted    bool
+
+	//The spec the job had before the deployment, and whether the
+	//deployment rolls back to it
+	previous *JobSpec
+	rollback bool
+
+	//Failures of the tasks launched with the new spec
+	failures int
+
+	//What was last saved of the deployment in the store
+	saved *store.Deployment
+}
+
+//DeploymentSummary is the progress of a deployment exposed to the operators
+type DeploymentSummary struct {
+	//ID is the job and the version of the deployment, like web.3
+	ID string `json:"id"`
+
+	JobID    string    `json:"job_id"`
+	Version  int       `json:"version"`
+	Strategy string    `json:"strategy"`
+	State    string    `json:"state"`
+	Started  time.Time `json:"started"`
+	Updated  time.Time `json:"updated"`
+
+	//The tasks with the old specs still alive, the ones with the new spec
+	//and, among them, the ones ready and healthy
+	OldTasks   int `json:"old_tasks"`
+	NewTasks   int `json:"new_tasks"`
+	ReadyTasks int `json:"ready_tasks"`
+
+	Failures int `json:"failures"`
+
+	//Rollback is set for the deployments rolling back to the previous
+	//spec
+	Rollback bool `json:"rollback,omitempty"`
+
+	//The canary of the deployment, if any
+	Canary *CanarySummary `json:"canary,omitempty"`
+}
+
+//deploymentID returns the ID of the deployment of the version of the job
+func deploymentID(jobId string, version int) string {
+	return fmt.Sprintf("%s%s%d", jobId, taskJobSeparator, version)
+}
+
+//specChanged reports if the tasks of the job must be replaced to run the
+//new spec. The number of instances, the priority and the upgrade strategy
+//don't change the tasks
+func specChanged(previous, current *JobSpec) bool {
+	a, b := *previous, *current
+	a.Instances, b.Instances = 0, 0
+	a.Priority, b.Priority = 0, 0
+	a.Upgrade, b.Upgrade = UpgradeStrategy{}, UpgradeStrategy{}
+
+	return !reflect.DeepEqual(a, b)
+}
+
+//startDeployment starts replacing the tasks of the job, whose spec was
+//previous, with its new spec. A deployment still in progress is
+//superseded. The caller must hold the mutex
+func (s *ExampleScheduler) startDeployment(job *JobSpec, previous *JobSpec) *deployment {
+	jlog := log.WithField("job_id", job.ID)
+	if d, ok := s.deployments[job.ID]; ok && (d.state == DeploymentRunning || d.state == DeploymentWaiting) {
+		jlog.WithField("version", d.version).Warnln("Deployment superseded by a new one")
+	}
+
+	s.versions[job.ID]++
+	now := time.Now()
+	d := &deployment{
+		jobId:    job.ID,
+		version:  s.versions[job.ID],
+		strategy: job.Upgrade.Strategy,
+		state:    DeploymentRunning,
+		started:  now,
+		updated:  now,
+		previous: previous,
+	}
+	if d.strategy == "" {
+		d.strategy = UpgradeRolling
+	}
+	s.deployments[job.ID] = d
+	s.publishDeployment(d, "")
+
+	jlog.WithFields(log.Fields{
+		"version":  d.version,
+		"strategy": d.strategy,
+	}).Infoln("Deployment started")
+
+	return d
+}
+
+//blueGreen returns the blue-green deployment of the job in progress, nil
+//if there is none. The caller must hold the mutex
+func (s *ExampleScheduler) blueGreen(jobId string) *deployment {
+	d, ok := s.deployments[jobId]
+	if !ok || d.strategy != UpgradeBlueGreen || d.cutover ||
+		(d.state != DeploymentRunning && d.state != DeploymentWaiting) {
+		return nil
+	}
+
+	return d
+}
+
+//instanceTasks returns the active tasks that count as the instances of
+//the job. Until the cutover of a blue-green deployment the old tasks
+//don't: the new ones are launched alongside them. The caller must hold the
+//mutex
+func (s *ExampleScheduler) instanceTasks(job *JobSpec) []*taskRecord {
+	active := s.activeTasks(job.ID)
+
+	d := s.blueGreen(job.ID)
+	if d == nil {
+		return active
+	}
+
+	var fresh []*taskRecord
+	for _, t := range active {
+		if t.version >= d.version {
+			fresh = append(fresh, t)
+		}
+	}
+
+	return fresh
+}
+
+//deployReady reports if the task is running, ready and, if its job has a
+//health check, healthy. The caller must hold the mutex
+func (s *ExampleScheduler) deployReady(t *taskRecord, job *JobSpec) bool {
+	return s.isReady(t) && (job.HealthCheck == nil || (t.healthy != nil && *t.healthy))
+}
+
+//deploymentTasks splits the active tasks of the job between the ones with
+//an older spec than the deployment, excluding the ones being killed, and
+//the ones with its spec. It also returns how many old tasks are being
+//killed. Scaling doesn't change the spec, every task has it. The caller
+//must hold the mutex
+func (s *ExampleScheduler) deploymentTasks(d *deployment) (old, fresh []*taskRecord, killing int) {
+	for _, t := range s.activeTasks(d.jobId) {
+		switch {
+		case t.version >= d.version || d.strategy == UpgradeScale:
+			fresh = append(fresh, t)
+		case t.killed:
+			killing++
+		default:
+			old = append(old, t)
+		}
+	}
+
+	return old, fresh, killing
+}
+
+//advanceDeployments kills the next batch of old tasks of the running
+//deployments once the new tasks of the previous batch are ready, and
+//finishes the deployments with no old task left. The caller must hold the
+//mutex
+func (s *ExampleScheduler) advanceDeployments() {
+	for _, d := range s.deployments {
+		job := s.job(d.jobId)
+		if (d.state != DeploymentRunning && d.state != DeploymentWaiting) || job == nil {
+			continue
+		}
+
+		old, fresh, killing := s.deploymentTasks(d)
+		var inFlight int
+		for _, t := range fresh {
+			if !s.deployReady(t, job) {
+				inFlight++
+			}
+		}
+
+		dlog := log.WithFields(log.Fields{
+			"job_id":  d.jobId,
+			"version": d.version,
+		})
+		if d.strategy == UpgradeBlueGreen {
+			s.advanceBlueGreen(d, job, old, killing, len(fresh)-inFlight)
+			continue
+		}
+		if d.strategy == UpgradeScale {
+			s.advanceScale(d, job, fresh, inFlight)
+			continue
+		}
+
+		//The first batch is the canary alone
+		batch := job.Upgrade.batchSize()
+		if job.Upgrade.Canary && !d.promoted && !d.rollback {
+			if s.holdCanary(d, job, fresh) {
+				continue
+			}
+			if len(fresh) == 0 {
+				batch = 1
+			}
+		}
+
+		switch {
+		case len(old) == 0 && killing == 0 && inFlight == 0 && s.pendingInstances(job) == 0:
+			s.setDeploymentState(d, DeploymentFinished)
+			d.updated = time.Now()
+			dlog.Infof("Deployment finished in %v", d.updated.Sub(d.started))
+			continue
+		case len(old) == 0 || killing > 0 || inFlight > 0 || s.pendingInstances(job) > 0:
+			//The current batch isn't replaced yet
+			continue
+		}
+
+		if batch > len(old) {
+			batch = len(old)
+		}
+		s.sortVictims(old, KillLeastHealthyFirst)
+
+		dlog.Infof("Replacing %d of the %d old tasks", batch, len(old))
+		for _, t := range old[:batch] {
+			if err := s.kill(t, fmt.Sprintf("replaced by version %d of the job", d.version)); err != nil {
+				taskLog(t).WithError(err).Errorln("Unable to kill the task")
+			}
+		}
+		d.updated = time.Now()
+	}
+}
+
+//advanceBlueGreen waits for all the new tasks of a blue-green deployment
+//to be ready and then, with the approval of the operator if the strategy
+//is manual, kills all the old tasks at once. The caller must hold the
+//mutex
+func (s *ExampleScheduler) advanceBlueGreen(d *deployment, job *JobSpec, old []*taskRecord, killing int, ready int) {
+	dlog := log.WithFields(log.Fields{
+		"job_id":  d.jobId,
+		"version": d.version,
+	})
+
+	switch {
+	case d.state == DeploymentWaiting:
+		return
+	case !d.cutover && ready >= job.Instances && job.Upgrade.Manual:
+		s.setDeploymentState(d, DeploymentWaiting)
+		d.updated = time.Now()
+		dlog.Infof("The %d new tasks are ready, waiting for the approval of the cutover", ready)
+		return
+	case !d.cutover && ready >= job.Instances:
+		s.cutover(d)
+		return
+	case d.cutover && len(old) == 0 && killing == 0:
+		s.setDeploymentState(d, DeploymentFinished)
+		d.updated = time.Now()
+		dlog.Infof("Deployment finished in %v", d.updated.Sub(d.started))
+	}
+}
+
+//cutover kills all the old tasks of a blue-green deployment, the new ones
+//take over. The caller must hold the mutex
+func (s *ExampleScheduler) cutover(d *deployment) {
+	d.cutover = true
+	s.setDeploymentState(d, DeploymentRunning)
+	d.updated = time.Now()
+
+	old, _, _ := s.deploymentTasks(d)
+	log.WithFields(log.Fields{
+		"job_id":  d.jobId,
+		"version": d.version,
+	}).Infof("Cutover to the new tasks, killing the %d old ones", len(old))
+	for _, t := range old {
+		if err := s.kill(t, fmt.Sprintf("cutover to version %d of the job", d.version)); err != nil {
+			taskLog(t).WithError(err).Errorln("Unable to kill the task")
+		}
+	}
+}
+
+//ApproveDeployment lets the deployment of the job waiting for the approval
+//of the operator go on
+func (s *ExampleScheduler) ApproveDeployment(jobId string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.job(jobId) == nil {
+		return ErrUnknownJob
+	}
+	d, ok := s.deployments[jobId]
+	if !ok || d.state != DeploymentWaiting {
+		return ErrNotWaiting
+	}
+
+	log.WithFields(log.Fields{
+		"job_id":  d.jobId,
+		"version": d.version,
+	}).Infoln("Deployment approved")
+	if d.strategy == UpgradeBlueGreen {
+		s.cutover(d)
+	} else {
+		s.promoteCanary(d)
+	}
+
+	return nil
+}
+
+//restoreSpec gives the job back the spec it had before the deployment,
+//keeping its current number of instances. Its old tasks run the current
+//spec again, so they get a new version. The caller must hold the mutex
+func (s *ExampleScheduler) restoreSpec(d *deployment) {
+	job := s.job(d.jobId)
+	if job == nil {
+		return
+	}
+
+	instances := job.Instances
+	*job = *d.previous
+	job.Instances = instances
+	s.saveJob(job)
+	s.versions[job.ID]++
+	for _, t := range s.activeTasks(job.ID) {
+		if t.version < d.version {
+			t.version = s.versions[job.ID]
+			s.saveTask(t)
+		}
+	}
+}
+
+//deploymentFailed counts the failure of a task in the deployment running
+//its spec, aborting it after too many. The caller must hold the mutex
+func (s *ExampleScheduler) deploymentFailed(t *taskRecord) {
+	d, ok := s.deployments[t.jobId]
+	job := s.job(t.jobId)
+	if !ok || job == nil || (d.state != DeploymentRunning && d.state != DeploymentWaiting) || t.version != d.version {
+		return
+	}
+
+	d.failures++
+	d.updated = time.Now()
+	if d.failures < job.Upgrade.maxFailures() {
+		return
+	}
+
+	s.setDeploymentState(d, DeploymentAborted)
+	dlog := log.WithFields(log.Fields{
+		"job_id":   d.jobId,
+		"version":  d.version,
+		"failures": d.failures,
+	})
+
+	//The old tasks of a blue-green deployment are all still there, so the
+	//job always goes back to them. A rollback is never rolled back, the
+	//previous spec failed too
+	switch {
+	case d.strategy == UpgradeScale:
+		dlog.Errorln("Scaling aborted, the new tasks fail too often")
+	case d.strategy == UpgradeBlueGreen && !d.cutover:
+		dlog.Errorln("Deployment aborted, the new tasks fail too often. Going back to the old ones")
+		s.rollback(d)
+	case job.Upgrade.Rollback && !d.rollback:
+		dlog.Errorln("Deployment aborted, the new tasks fail too often. Rolling back to the previous spec")
+		s.rollback(d)
+	default:
+		dlog.Errorln("Deployment aborted, the new tasks fail too often. The old tasks left keep running")
+	}
+}
+
+//Deployments returns the last deployment of every job
+func (s *ExampleScheduler) Deployments() []DeploymentSummary {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	summaries := make([]DeploymentSummary, 0, len(s.deployments))
+	for _, d := range s.deployments {
+		summary := DeploymentSummary{
+			ID:       deploymentID(d.jobId, d.version),
+			JobID:    d.jobId,
+			Version:  d.version,
+			Strategy: d.strategy,
+			State:    d.state,
+			Started:  d.started,
+			Updated:  d.updated,
+			Failures: d.failures,
+			Rollback: d.rollback,
+			Canary:   s.canarySummary(d),
+		}
+
+		old, fresh, killing := s.deploymentTasks(d)
+		summary.OldTasks = len(old) + killing
+		summary.NewTasks = len(fresh)
+		if job := s.job(d.jobId); job != nil {
+			for _, t := range fresh {
+				if s.deployReady(t, job) {
+					summary.ReadyTasks++
+				}
+			}
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].JobID < summaries[j].JobID })
+
+	return summaries
+}
@@ -0,0 +1,50 @@
+package example_scheduler
+
+import (
+	log "github.com/Sirupsen/logrus"
+	"github.com/mesos/mesos-go/scheduler"
+)
+
+//disconnect pauses the launches until the scheduler registers again. The
+//offers of the old master aren't valid anymore. The caller must hold the
+//mutex
+func (s *ExampleScheduler) disconnect() {
+	s.disconnected = true
+	s.offers = make(map[string][]*heldOffer)
+}
+
+//reconnect resumes the launches once registered with a master: the kills
+//requested while disconnected are sent, the tasks are reconciled, since
+//updates may have been lost, and the offers are revived unless the
+//scheduling is paused. The caller must hold the mutex
+func (s *ExampleScheduler) reconnect(driver scheduler.SchedulerDriver) {
+	s.driver = driver
+	s.disconnected = false
+
+	for taskId := range s.pendingKills {
+		t, ok := s.tasks[taskId]
+		if !ok {
+			delete(s.pendingKills, taskId)
+			continue
+		}
+
+		taskLog(t).Infoln("Sending the kill requested while disconnected")
+		if err := s.sendKill(t); err != nil {
+			taskLog(t).WithError(err).Errorln("Unable to kill the task")
+			continue
+		}
+		delete(s.pendingKills, taskId)
+	}
+
+	s.reconcile(driver)
+	if s.paused {
+		return
+	}
+
+	if _, err := driver.ReviveOffers(); err != nil {
+		log.WithError(err).Errorln("Unable to revive the offers")
+		return
+	}
+	s.suppressed = false
+	s.filtered = false
+}
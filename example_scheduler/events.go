@@ -0,0 +1,216 @@
+package example_scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//eventBuffer is how many events a subscriber may fall behind before it is
+//dropped, closing its channel
+const eventBuffer = 256
+
+//eventsKept is how many of the last events are kept to be replayed to the
+//subscribers reconnecting
+const eventsKept = 10000
+
+//ErrEventsGone is returned when subscribing from a sequence number whose
+//next events aren't kept anymore, or never were by this scheduler
+var ErrEventsGone = errors.New("the events since the sequence number are not kept anymore")
+
+//The types of the events
+const (
+	//EventStatus is a status update of a task
+	EventStatus = "status"
+
+	//EventAccept and EventDecline are offers accepted or declined
+	EventAccept  = "accept"
+	EventDecline = "decline"
+
+	//EventDeployment is a deployment started or changing state
+	EventDeployment = "deployment"
+
+	//EventAgent is an agent blacklisted for the failures of its tasks
+	EventAgent = "agent"
+)
+
+//AgentBlacklisted is the state of the agents blacklisted in their events
+const AgentBlacklisted = "blacklisted"
+
+//Event is something that happened in the scheduler, sent to the
+//subscribers as it happens. The fields set depend on the type
+type Event struct {
+	Seq  int64     `json:"seq"`
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+
+	JobID    string `json:"job_id,omitempty"`
+	TaskID   string `json:"task_id,omitempty"`
+	AgentID  string `json:"agent_id,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+
+	//The state of the task, of the deployment or of the agent, and the
+	//state the deployment left
+	State string `json:"state,omitempty"`
+	From  string `json:"from,omitempty"`
+
+	//The status update of the task
+	Message string `json:"message,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Healthy *bool  `json:"healthy,omitempty"`
+
+	//The offers accepted or declined
+	OfferIDs []string `json:"offer_ids,omitempty"`
+
+	//Why the offers were declined, the task got its state or the agent
+	//was blacklisted
+	Reason string `json:"reason,omitempty"`
+
+	//The deployment
+	DeploymentID string `json:"deployment_id,omitempty"`
+	Version      int    `json:"version,omitempty"`
+	Strategy     string `json:"strategy,omitempty"`
+}
+
+//EventFilter selects the events of a subscriber. Empty fields match every
+//event
+type EventFilter struct {
+	Types  []string
+	JobIDs []string
+}
+
+//ValidEventType checks the type of the events of a filter
+func ValidEventType(t string) error {
+	switch t {
+	case EventStatus, EventAccept, EventDecline, EventDeployment, EventAgent:
+		return nil
+	}
+
+	return fmt.Errorf("unknown event type %q, use %s, %s, %s, %s or %s", t, EventStatus, EventAccept, EventDecline, EventDeployment, EventAgent)
+}
+
+func (f *EventFilter) matches(e *Event) bool {
+	return matchesAny(f.Types, e.Type) && matchesAny(f.JobIDs, e.JobID)
+}
+
+//matchesAny reports if the value is one of the values, or if there is none
+func matchesAny(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return len(values) == 0
+}
+
+//Subscribe returns the events matching the filter after the sequence
+//number since, if not zero, and a channel receiving the next ones, along
+//with the function to call once done with it. A subscriber falling too far
+//behind is dropped: its channel is closed, and it can subscribe again from
+//the last event it got
+func (s *ExampleScheduler) Subscribe(filter EventFilter, since int64) ([]Event, <-chan Event, func(), error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var replay []Event
+	if since > 0 {
+		if since > s.eventSeq || (len(s.events) > 0 && s.events[0].Seq > since+1) {
+			return nil, nil, nil, ErrEventsGone
+		}
+		for i := range s.events {
+			if e := &s.events[i]; e.Seq > since && filter.matches(e) {
+				replay = append(replay, *e)
+			}
+		}
+	}
+
+	events := make(chan Event, eventBuffer)
+	s.subscribers[events] = filter
+
+	return replay, events, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		if _, ok := s.subscribers[events]; ok {
+			delete(s.subscribers, events)
+			close(events)
+		}
+	}, nil
+}
+
+//publish numbers the event, keeps it for the replays and sends it to the
+//subscribers. The caller must hold the mutex
+func (s *ExampleScheduler) publish(e Event) {
+	s.eventSeq++
+	e.Seq, e.Time = s.eventSeq, time.Now()
+
+	s.events = append(s.events, e)
+	if len(s.events) > eventsKept {
+		s.events = s.events[len(s.events)-eventsKept:]
+	}
+
+	for events, filter := range s.subscribers {
+		if !filter.matches(&e) {
+			continue
+		}
+
+		select {
+		case events <- e:
+		default:
+			log.WithField("seq", e.Seq).Warnln("Event subscriber too far behind, dropping it")
+			delete(s.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+//publishStatus publishes the status update of the task. The caller must
+//hold the mutex
+func (s *ExampleScheduler) publishStatus(t *taskRecord, status *mesosproto.TaskStatus) {
+	e := Event{
+		Type:     EventStatus,
+		JobID:    t.jobId,
+		TaskID:   t.id,
+		AgentID:  t.agentId,
+		Hostname: t.hostname,
+		State:    status.GetState().String(),
+		Message:  status.GetMessage(),
+		Source:   status.GetSource().String(),
+		Healthy:  status.Healthy,
+	}
+	if status.Reason != nil {
+		e.Reason = status.GetReason().String()
+	}
+
+	s.publish(e)
+}
+
+//setDeploymentState moves the deployment to the state, publishing the
+//transition. The caller must hold the mutex
+func (s *ExampleScheduler) setDeploymentState(d *deployment, state string) {
+	if d.state == state {
+		return
+	}
+
+	from := d.state
+	d.state = state
+	s.publishDeployment(d, from)
+}
+
+//publishDeployment publishes the deployment coming from the state, empty
+//for a deployment just started. The caller must hold the mutex
+func (s *ExampleScheduler) publishDeployment(d *deployment, from string) {
+	s.publish(Event{
+		Type:         EventDeployment,
+		JobID:        d.jobId,
+		State:        d.state,
+		From:         from,
+		DeploymentID: deploymentID(d.jobId, d.version),
+		Version:      d.version,
+		Strategy:     d.strategy,
+	})
+}
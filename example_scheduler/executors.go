@@ -0,0 +1,62 @@
+package example_scheduler
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//The executors of an agent that die more than maxExecutorFailures times in
+//executorFailureWindow put the agent in the cool-down of the lost agents
+const (
+	maxExecutorFailures   = 3
+	executorFailureWindow = 10 * time.Minute
+)
+
+//executorFailures are the crashes of the executors of an agent
+type executorFailures struct {
+	//Crashes of each executor since the scheduler started, by executor ID
+	byExecutor map[string]int
+
+	//When the recent crashes of any executor of the agent happened
+	recent []time.Time
+}
+
+//executorLost counts the crash of an executor of the agent. An executor
+//that exits with status 0 was shut down and doesn't count. The caller must
+//hold the mutex
+func (s *ExampleScheduler) executorLost(agentId, executorId string, status int) {
+	if status == 0 || s.stopping {
+		return
+	}
+
+	f, ok := s.executorFailures[agentId]
+	if !ok {
+		f = &executorFailures{byExecutor: make(map[string]int)}
+		s.executorFailures[agentId] = f
+	}
+	f.byExecutor[executorId]++
+
+	recent := []time.Time{time.Now()}
+	for _, at := range f.recent {
+		if time.Since(at) < executorFailureWindow {
+			recent = append(recent, at)
+		}
+	}
+	f.recent = recent
+
+	elog := log.WithFields(log.Fields{
+		"agent_id":          agentId,
+		"executor_id":       executorId,
+		"executor_failures": f.byExecutor[executorId],
+		"agent_failures":    len(f.recent),
+	})
+	if len(f.recent) <= maxExecutorFailures {
+		elog.Warnln("Executor crashed")
+		return
+	}
+
+	until := s.coolDownAgent(agentId)
+	f.recent = nil
+	elog.WithField("until", until).Errorln("Executors of the agent keep crashing, no task is placed on it until the end of its cool-down")
+}
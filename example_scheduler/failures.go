@@ -0,0 +1,114 @@
+package example_scheduler
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//AgentFailurePolicy decides when an agent where the tasks keep failing is
+//blacklisted: after MaxFailures failures in Window, for Blacklist, doubled
+//each time the agent is blacklisted again shortly after, up to
+//MaxBlacklist. The fields left at 0 use the defaults
+type AgentFailurePolicy struct {
+	MaxFailures  int
+	Window       time.Duration
+	Blacklist    time.Duration
+	MaxBlacklist time.Duration
+}
+
+//DefaultAgentFailurePolicy are the values used for the fields of an
+//AgentFailurePolicy left at 0
+var DefaultAgentFailurePolicy = AgentFailurePolicy{
+	MaxFailures:  5,
+	Window:       10 * time.Minute,
+	Blacklist:    5 * time.Minute,
+	MaxBlacklist: time.Hour,
+}
+
+//withDefaults returns the policy with the defaults in the fields left at 0
+func (p AgentFailurePolicy) withDefaults() AgentFailurePolicy {
+	if p.MaxFailures <= 0 {
+		p.MaxFailures = DefaultAgentFailurePolicy.MaxFailures
+	}
+	if p.Window <= 0 {
+		p.Window = DefaultAgentFailurePolicy.Window
+	}
+	if p.Blacklist <= 0 {
+		p.Blacklist = DefaultAgentFailurePolicy.Blacklist
+	}
+	if p.MaxBlacklist <= 0 {
+		p.MaxBlacklist = DefaultAgentFailurePolicy.MaxBlacklist
+	}
+
+	return p
+}
+
+//agentHealth is the failure history of the tasks of an agent
+type agentHealth struct {
+	//When the recent failures happened
+	failures []time.Time
+
+	//Times the agent was blacklisted in a row, and when the last
+	//blacklisting ends
+	blacklistings int
+	until         time.Time
+}
+
+//taskFailed counts a failure of a task on its agent, and blacklists the
+//agent when they are too many. The caller must hold the mutex
+func (s *ExampleScheduler) taskFailed(t *taskRecord) {
+	if t.agentId == "" {
+		return
+	}
+
+	policy := s.AgentFailures.withDefaults()
+	h, ok := s.agentHealth[t.agentId]
+	if !ok {
+		h = &agentHealth{}
+		s.agentHealth[t.agentId] = h
+	}
+
+	failures := []time.Time{time.Now()}
+	for _, at := range h.failures {
+		if time.Since(at) < policy.Window {
+			failures = append(failures, at)
+		}
+	}
+	h.failures = failures
+	if len(h.failures) < policy.MaxFailures {
+		return
+	}
+
+	//An agent that behaved for a while after its last blacklisting starts
+	//over
+	if time.Since(h.until) > policy.MaxBlacklist {
+		h.blacklistings = 0
+	}
+
+	wait := time.Duration(float64(policy.Blacklist) * math.Pow(2, float64(h.blacklistings)))
+	if wait > policy.MaxBlacklist {
+		wait = policy.MaxBlacklist
+	}
+	h.blacklistings++
+	h.failures = nil
+	h.until = s.coolDownAgentFor(t.agentId, wait)
+
+	log.WithFields(log.Fields{
+		"agent_id":      t.agentId,
+		"hostname":      t.hostname,
+		"failures":      policy.MaxFailures,
+		"blacklistings": h.blacklistings,
+		"until":         h.until,
+	}).Warnln("Too many tasks failed on the agent, no task is placed on it until the end of its blacklisting")
+
+	s.publish(Event{
+		Type:     EventAgent,
+		AgentID:  t.agentId,
+		Hostname: t.hostname,
+		State:    AgentBlacklisted,
+		Reason:   fmt.Sprintf("%d tasks failed in %v, blacklisted until %s", policy.MaxFailures, policy.Window, h.until.Format(time.RFC3339)),
+	})
+}
@@ -0,0 +1,60 @@
+package example_scheduler
+
+//gangsPending returns the instances each gang job, but the skipped ones,
+//has to launch at once. The caller must hold the mutex
+func (s *ExampleScheduler) gangsPending(planned map[string]int, skip map[string]bool) map[string]int {
+	want := make(map[string]int)
+	for _, job := range s.jobs {
+		if job.Gang && !skip[job.ID] && !s.inBackoff(job) {
+			want[job.ID] = s.pendingInstances(job) - planned[job.ID]
+		}
+	}
+
+	return want
+}
+
+//incompleteGangs returns the gang jobs with some of their instances placed
+//on the agents, but not all of them
+func (s *ExampleScheduler) incompleteGangs(agents []*agentOffers, want map[string]int) []string {
+	placed := make(map[string]int)
+	for _, agent := range agents {
+		for _, task := range agent.tasks {
+			placed[jobOfTask(task.TaskId.GetValue())]++
+		}
+	}
+
+	var incomplete []string
+	for jobId, n := range want {
+		if placed[jobId] > 0 && placed[jobId] < n {
+			incomplete = append(incomplete, jobId)
+		}
+	}
+
+	return incomplete
+}
+
+//unplace forgets the tasks placed on the agents, with their records and the
+//volumes they would have created, putting their instances back in the
+//launch queue, and gives the agents back all the resources of their
+//offers. The caller must hold the mutex
+func (s *ExampleScheduler) unplace(agents []*agentOffers, planned map[string]int) {
+	for _, agent := range agents {
+		s.requeue(agent.tasks, false)
+		for _, task := range agent.tasks {
+			if s.DryRun {
+				planned[jobOfTask(task.TaskId.GetValue())]--
+			} else {
+				delete(s.tasks, task.TaskId.GetValue())
+			}
+		}
+		for _, volume := range agent.create {
+			delete(s.volumes, volume.GetDisk().GetPersistence().GetId())
+		}
+
+		agent.tasks = nil
+		agent.reserve = nil
+		agent.create = nil
+		agent.groups = nil
+		agent.res = newRoleResources(agent.offers)
+	}
+}
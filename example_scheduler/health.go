@@ -0,0 +1,158 @@
+package example_scheduler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//The protocols of a health check
+const (
+	HealthCommand = "command"
+	HealthHTTP    = "http"
+	HealthTCP     = "tcp"
+)
+
+//HealthCheckSpec is a health check Mesos runs on each task of the job. A
+//task that fails it too many times in a row is killed by its executor and
+//replaced following the restart policy of the job
+type HealthCheckSpec struct {
+	//Protocol is command, http or tcp
+	Protocol string `json:"protocol"`
+
+	//Command run inside the container of the task, healthy if it exits
+	//with 0
+	Command string `json:"cmd,omitempty"`
+
+	//Path requested with http, healthy on a 2xx or 3xx response
+	Path string `json:"path,omitempty"`
+
+	//Name of the port checked with http and tcp. Empty is the first port
+	//of the task
+	Port string `json:"port,omitempty"`
+
+	//Seconds between the checks, and to wait for each of them. 0 leaves
+	//the defaults of Mesos, 10 and 20 seconds
+	Interval float64 `json:"interval,omitempty"`
+	Timeout  float64 `json:"timeout,omitempty"`
+
+	//Seconds after the start of the task during which the failed checks
+	//don't count, while it warms up. 0 is 10 seconds
+	GracePeriod float64 `json:"grace_period,omitempty"`
+
+	//Consecutive failures after which the task is killed. 0 is 3
+	MaxConsecutiveFailures uint32 `json:"max_consecutive_failures,omitempty"`
+}
+
+//validate checks the health check against the job it belongs to
+func (h *HealthCheckSpec) validate(job *JobSpec) error {
+	switch h.Protocol {
+	case HealthCommand:
+		if h.Command == "" {
+			return errors.New("a command health check needs a command")
+		}
+	case HealthHTTP, HealthTCP:
+		if h.Port != "" && h.portIndex(job) < 0 {
+			return fmt.Errorf("the health check port %s isn't a port of the job", h.Port)
+		}
+	default:
+		return fmt.Errorf("unknown health check protocol %q, use %s, %s or %s", h.Protocol, HealthCommand, HealthHTTP, HealthTCP)
+	}
+
+	switch {
+	case h.Interval < 0 || h.Timeout < 0 || h.GracePeriod < 0:
+		return errors.New("health check times can't be negative")
+	case job.Image == "" && len(job.Containers) == 0:
+		return errors.New("health checks are run by the executors of Mesos, they need an image or containers")
+	}
+
+	return nil
+}
+
+//portIndex returns the index of the checked port in the ports of the job,
+//-1 if it isn't one of them
+func (h *HealthCheckSpec) portIndex(job *JobSpec) int {
+	if h.Port == "" {
+		return 0
+	}
+
+	for i, p := range job.portSpecs() {
+		if p.Name == h.Port {
+			return i
+		}
+	}
+
+	return -1
+}
+
+//healthCheck builds the HealthCheck of a task of the job given its ports,
+//nil if the job has none
+func (j *JobSpec) healthCheck(ports []uint64) *mesosproto.HealthCheck {
+	h := j.HealthCheck
+	if h == nil {
+		return nil
+	}
+
+	check := &mesosproto.HealthCheck{}
+	if h.Interval > 0 {
+		check.IntervalSeconds = proto.Float64(h.Interval)
+	}
+	if h.Timeout > 0 {
+		check.TimeoutSeconds = proto.Float64(h.Timeout)
+	}
+	if h.GracePeriod > 0 {
+		check.GracePeriodSeconds = proto.Float64(h.GracePeriod)
+	}
+	if h.MaxConsecutiveFailures > 0 {
+		check.ConsecutiveFailures = proto.Uint32(h.MaxConsecutiveFailures)
+	}
+
+	port := proto.Uint32(uint32(ports[h.portIndex(j)]))
+	switch h.Protocol {
+	case HealthCommand:
+		check.Type = mesosproto.HealthCheck_COMMAND.Enum()
+		check.Command = &mesosproto.CommandInfo{
+			Shell: proto.Bool(true),
+			Value: proto.String(h.Command),
+		}
+	case HealthHTTP:
+		check.Type = mesosproto.HealthCheck_HTTP.Enum()
+		check.Http = &mesosproto.HealthCheck_HTTPCheckInfo{Port: port}
+		if h.Path != "" {
+			check.Http.Path = proto.String(h.Path)
+		}
+	case HealthTCP:
+		check.Type = mesosproto.HealthCheck_TCP.Enum()
+		check.Tcp = &mesosproto.HealthCheck_TCPCheckInfo{Port: port}
+	}
+
+	return check
+}
+
+//trackHealth records the result of the last health check of the task,
+//logging when it changes. The caller must hold the mutex
+func trackHealth(t *taskRecord, status *mesosproto.TaskStatus) {
+	if status.Healthy == nil {
+		return
+	}
+
+	healthy := status.GetHealthy()
+	if t.healthy == nil || *t.healthy != healthy {
+		tlog := taskLog(t)
+		if healthy {
+			tlog.Infoln("Task healthy")
+		} else {
+			tlog.WithField("message", status.GetMessage()).Warnln("Task unhealthy")
+		}
+	}
+	t.healthy = &healthy
+}
+
+//killedUnhealthy reports if the executor killed the task for failing its
+//health checks
+func killedUnhealthy(t *taskRecord, status *mesosproto.TaskStatus) bool {
+	return status.GetState() == mesosproto.TaskState_TASK_KILLED &&
+		!t.killed && t.healthy != nil && !*t.healthy
+}
@@ -0,0 +1,79 @@
+package example_scheduler
+
+import (
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//historyInterval is how often the records of the ended tasks beyond the
+//retention are pruned
+const historyInterval = time.Minute
+
+//TaskHistoryPolicy decides how long the records of the ended tasks, with
+//their last state, message and agent, are kept in memory and in the store
+type TaskHistoryPolicy struct {
+	//Retention is how long a task is kept after it ended. Zero keeps them
+	//regardless of their age
+	Retention time.Duration
+
+	//MaxTasks is the most ended tasks kept, the ones that ended first are
+	//pruned first. Zero is no limit
+	MaxTasks int
+}
+
+//ended returns when the task ended, the time of its last status update
+func (t *taskRecord) ended() time.Time {
+	if t.Updated.IsZero() {
+		return t.launched
+	}
+
+	return t.Updated
+}
+
+//CollectTaskHistory prunes the ended tasks beyond the TaskHistory policy
+//every minute. It never returns, run it in its own goroutine
+func (s *ExampleScheduler) CollectTaskHistory() {
+	for range time.Tick(historyInterval) {
+		s.mutex.Lock()
+		s.pruneTaskHistory(time.Now())
+		s.mutex.Unlock()
+	}
+}
+
+//pruneTaskHistory forgets the ended tasks older than the retention and the
+//oldest ones beyond the max count, in memory and in the store. The caller
+//must hold the mutex
+func (s *ExampleScheduler) pruneTaskHistory(now time.Time) {
+	var ended []*taskRecord
+	for _, t := range s.tasks {
+		if tasks.IsTerminal(t.State) {
+			ended = append(ended, t)
+		}
+	}
+	sort.Slice(ended, func(i, j int) bool {
+		return ended[i].ended().After(ended[j].ended())
+	})
+
+	pruned := 0
+	for i, t := range ended {
+		tooOld := s.TaskHistory.Retention > 0 && now.Sub(t.ended()) > s.TaskHistory.Retention
+		tooMany := s.TaskHistory.MaxTasks > 0 && i >= s.TaskHistory.MaxTasks
+		if !tooOld && !tooMany {
+			continue
+		}
+
+		delete(s.tasks, t.id)
+		s.deleteTask(t.id)
+		pruned++
+	}
+
+	if pruned > 0 {
+		log.WithFields(log.Fields{
+			"pruned": pruned,
+			"kept":   len(ended) - pruned,
+		}).Debugln("Pruned the task history")
+	}
+}
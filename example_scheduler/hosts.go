@@ -0,0 +1,70 @@
+package example_scheduler
+
+import (
+	log "github.com/Sirupsen/logrus"
+)
+
+//HostFilter pins the framework to some agents or excludes others, by
+//hostname. An empty Whitelist allows every agent not in the Blacklist
+type HostFilter struct {
+	Whitelist []string `json:"whitelist"`
+	Blacklist []string `json:"blacklist"`
+}
+
+//allows reports if tasks can run on the agent
+func (f *HostFilter) allows(hostname string) bool {
+	for _, h := range f.Blacklist {
+		if h == hostname {
+			return false
+		}
+	}
+
+	if len(f.Whitelist) == 0 {
+		return true
+	}
+	for _, h := range f.Whitelist {
+		if h == hostname {
+			return true
+		}
+	}
+
+	return false
+}
+
+//HostFilter returns the agents the framework is pinned to or excludes
+func (s *ExampleScheduler) HostFilter() HostFilter {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return HostFilter{
+		Whitelist: append([]string{}, s.hosts.Whitelist...),
+		Blacklist: append([]string{}, s.hosts.Blacklist...),
+	}
+}
+
+//SetHostFilter replaces the agents the framework is pinned to or excludes.
+//The running tasks aren't moved. As the offers of the agents excluded
+//until now were refused for a long time, the offers are revived, unless the
+//scheduling is paused
+func (s *ExampleScheduler) SetHostFilter(filter HostFilter) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.hosts = HostFilter{
+		Whitelist: append([]string{}, filter.Whitelist...),
+		Blacklist: append([]string{}, filter.Blacklist...),
+	}
+	log.WithFields(log.Fields{
+		"whitelist": s.hosts.Whitelist,
+		"blacklist": s.hosts.Blacklist,
+	}).Infoln("Host filter updated")
+
+	if s.driver != nil && !s.disconnected && !s.paused {
+		if _, err := s.driver.ReviveOffers(); err != nil {
+			log.WithError(err).Errorln("Unable to revive the offers")
+		} else {
+			s.suppressed = false
+			s.filtered = false
+		}
+	}
+}
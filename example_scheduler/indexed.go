@@ -0,0 +1,173 @@
+package example_scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//indexEnv is the environment variable with the index of a task of an
+//indexed job
+const indexEnv = "TASK_INDEX"
+
+//The states of an index of an indexed job
+const (
+	IndexPending   = "pending"
+	IndexRunning   = "running"
+	IndexSucceeded = "succeeded"
+	IndexFailed    = "failed"
+)
+
+//ErrNotIndexed is returned when asking for the indexes of a job that isn't
+//indexed
+var ErrNotIndexed = errors.New("the job is not indexed")
+
+//IndexSummary describes an index of an indexed job
+type IndexSummary struct {
+	Index int    `json:"index"`
+	State string `json:"state"`
+
+	//TaskID is the task running the index, if any
+	TaskID string `json:"task_id,omitempty"`
+
+	//Attempts are the tasks launched for the index since the job was
+	//submitted, updated or retried
+	Attempts int `json:"attempts"`
+}
+
+//validateIndexed checks that an indexed job runs to completion and that
+//its tasks get the environment with their index
+func (j *JobSpec) validateIndexed() error {
+	switch {
+	case !j.Indexed:
+		return nil
+	case !j.isBatch():
+		return fmt.Errorf("indexed jobs must be %s jobs", JobBatch)
+	case j.Cron != nil:
+		return errors.New("indexed jobs can't have a cron schedule, the runs would share the indexes")
+	case j.Image == "" && len(j.Containers) == 0:
+		return errors.New("the index is passed in the environment of the task, indexed jobs need an image or containers")
+	}
+
+	return nil
+}
+
+//addIndexEnv adds the index of the task to its environment, if it has one
+func addIndexEnv(env map[string]string, index int) {
+	if index >= 0 {
+		env[indexEnv] = strconv.Itoa(index)
+	}
+}
+
+//nextIndex returns the lowest index of the job that is neither running nor
+//done, nor taken by a task placed in this round, or -1 if there is none or
+//the job isn't indexed. The caller must hold the mutex
+func (s *ExampleScheduler) nextIndex(job *JobSpec, taken map[int]bool) int {
+	if !job.Indexed {
+		return -1
+	}
+
+	busy := make(map[int]bool)
+	for _, t := range s.activeTasks(job.ID) {
+		busy[t.index] = true
+	}
+
+	r := s.restartState(job.ID)
+	for i := 0; i < job.Instances; i++ {
+		if _, done := r.indexes[i]; !done && !busy[i] && !taken[i] {
+			return i
+		}
+	}
+
+	return -1
+}
+
+//Indexes returns the state of each index of an indexed job
+func (s *ExampleScheduler) Indexes(jobId string) ([]IndexSummary, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job := s.job(jobId)
+	if job == nil {
+		return nil, ErrUnknownJob
+	}
+	if !job.Indexed {
+		return nil, ErrNotIndexed
+	}
+
+	running := make(map[int]string)
+	for _, t := range s.activeTasks(job.ID) {
+		running[t.index] = t.id
+	}
+
+	r := s.restartState(job.ID)
+	summaries := []IndexSummary{}
+	for i := 0; i < job.Instances; i++ {
+		summary := IndexSummary{
+			Index:    i,
+			State:    IndexPending,
+			Attempts: r.attempts[i],
+		}
+
+		succeeded, done := r.indexes[i]
+		switch {
+		case running[i] != "":
+			summary.State, summary.TaskID = IndexRunning, running[i]
+		case done && succeeded:
+			summary.State = IndexSucceeded
+		case done:
+			summary.State = IndexFailed
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+//RetryIndexes launches again the indexes of an indexed job that failed for
+//good, with a clean retry count, leaving the ones that succeeded done. It
+//returns the indexes retried
+func (s *ExampleScheduler) RetryIndexes(jobId string) ([]int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job := s.job(jobId)
+	if job == nil {
+		return nil, ErrUnknownJob
+	}
+	if !job.Indexed {
+		return nil, ErrNotIndexed
+	}
+
+	r := s.restartState(job.ID)
+	retried := []int{}
+	for i, succeeded := range r.indexes {
+		if !succeeded {
+			retried = append(retried, i)
+		}
+	}
+	sort.Ints(retried)
+
+	for _, i := range retried {
+		delete(r.indexes, i)
+		r.attempts[i] = 0
+		r.done--
+	}
+	r.failures = 0
+	r.backoffUntil = time.Time{}
+
+	if len(retried) > 0 {
+		log.WithFields(log.Fields{
+			"job_id":  job.ID,
+			"indexes": retried,
+		}).Infoln("Retrying the failed indexes")
+		s.evaluatePipeline()
+		s.reviveIfNeeded(true)
+	}
+
+	return retried, nil
+}
@@ -0,0 +1,193 @@
+package example_scheduler
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mesos/mesos-go/mesosproto"
+	"github.com/satori/go.uuid"
+)
+
+//JobSpec describes a workload managed by the scheduler: what its tasks run,
+//the resources each of them needs and how many copies must be running
+type JobSpec struct {
+	//Unique name of the job. It prefixes the ID of its tasks
+	ID string `json:"id"`
+
+	//Type is service, the default, for the jobs whose tasks keep running,
+	//or batch for the ones whose tasks run to completion
+	Type string `json:"type,omitempty"`
+
+	//Docker image the task runs. When empty the task is launched with the
+	//ExecutorInfo of the scheduler instead of a container
+	Image string `json:"image,omitempty"`
+
+	//Command to run. If Args is empty it is run with a shell, otherwise it
+	//is executed directly with Args. When both are empty the image's
+	//entrypoint is used
+	Command string   `json:"cmd,omitempty"`
+	Args    []string `json:"args,omitempty"`
+
+	//Environment variables of the task
+	Env map[string]string `json:"env,omitempty"`
+
+	//The CPUs that each task needs
+	Cpus float64 `json:"cpus"`
+
+	//The RAM that each task needs
+	Mem float64 `json:"mem"`
+
+	//The disk that each task needs, besides its persistent volume
+	Disk float64 `json:"disk,omitempty"`
+
+	//The GPUs that each task needs. Only the tasks without an image get
+	//them, the Docker containerizer doesn't support GPUs
+	Gpus float64 `json:"gpus,omitempty"`
+
+	//The host ports each task needs. Without any, each task takes a single
+	//port
+	Ports []PortSpec `json:"ports,omitempty"`
+
+	//Revocable lets the tasks use revocable resources, the idle capacity
+	//of the agents that Mesos oversubscribes. They are used before the
+	//others, but the tasks may be killed when the capacity is needed back,
+	//so it is only meant for best-effort work
+	Revocable bool `json:"revocable,omitempty"`
+
+	//Role whose offers the tasks use when the framework has several.
+	//Empty is the first role of the framework
+	Role string `json:"role,omitempty"`
+
+	//The number of copies of the task that must be running
+	Instances int `json:"instances"`
+
+	//Priority of the job. The jobs with a higher priority are placed
+	//first, and preempt the tasks of the lower priority jobs when their
+	//instances can't find resources
+	Priority int `json:"priority,omitempty"`
+
+	//Indexed makes an array job: each instance is an index, from 0 to
+	//Instances-1, passed to its task in TASK_INDEX. Only the failed
+	//indexes are retried
+	Indexed bool `json:"indexed,omitempty"`
+
+	//Gang makes the pending instances launch all at once, possibly on
+	//several agents, or wait until the offers fit all of them
+	Gang bool `json:"gang,omitempty"`
+
+	//What to do when a task ends
+	Restart RestartPolicy `json:"restart"`
+
+	//Cron, if set, launches the instances at the times of its schedule
+	//instead of keeping them running
+	Cron *CronSpec `json:"cron,omitempty"`
+
+	//DependsOn are the IDs of the jobs whose instances must all finish
+	//successfully before the instances of this job are launched. If any
+	//of them fails, this job is never launched
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	//How the tasks are replaced when the spec of the job changes
+	Upgrade UpgradeStrategy `json:"upgrade"`
+
+	//MaxRuntime is the seconds a task may run before it is killed, for
+	//the tasks that may get stuck. 0 is no limit
+	MaxRuntime float64 `json:"max_runtime,omitempty"`
+
+	//KillGracePeriod is the seconds a task has to shut down cleanly after
+	//SIGTERM before it gets SIGKILL. 0 is the default of the executor
+	KillGracePeriod float64 `json:"kill_grace_period,omitempty"`
+
+	//The agents where the tasks can run
+	Constraints []Constraint `json:"constraints,omitempty"`
+
+	//IDs of the jobs whose tasks must be running on an agent to launch a
+	//task of this job there, and of the jobs that must not
+	Affinity     []string `json:"affinity,omitempty"`
+	AntiAffinity []string `json:"anti_affinity,omitempty"`
+
+	//Persistent volume of each task. It needs the scheduler to reserve
+	//resources
+	Volume *VolumeSpec `json:"volume,omitempty"`
+
+	//Containers make each instance a pod, a group of tasks launched
+	//together on the same agent. Cpus, Mem and Disk are then the
+	//resources of the executor of the group
+	Containers []ContainerSpec `json:"containers,omitempty"`
+
+	//HealthCheck, if set, is run by Mesos on each task. Only for the
+	//tasks with an image or containers
+	HealthCheck *HealthCheckSpec `json:"health_check,omitempty"`
+
+	//Readiness, if set, is checked by the scheduler on each running task,
+	//which only counts as a ready instance once it passes
+	Readiness *ReadinessCheckSpec `json:"readiness,omitempty"`
+}
+
+//taskJobSeparator separates the job ID from the unique part of a task ID
+const taskJobSeparator = "."
+
+//newTaskID creates a unique task ID for a task of the job
+func (j *JobSpec) newTaskID() string {
+	return j.ID + taskJobSeparator + uuid.NewV4().String()
+}
+
+//jobOfTask extracts the job ID from a task ID created by newTaskID
+func jobOfTask(taskId string) string {
+	if i := strings.Index(taskId, taskJobSeparator); i >= 0 {
+		return taskId[:i]
+	}
+
+	return ""
+}
+
+//commandInfo builds the CommandInfo of a containerized task. extraEnv is
+//added to the environment of the job, like the ports of the task
+func (j *JobSpec) commandInfo(extraEnv map[string]string) *mesosproto.CommandInfo {
+	command := &mesosproto.CommandInfo{
+		Shell: proto.Bool(len(j.Args) == 0 && j.Command != ""),
+	}
+
+	if j.Command != "" {
+		command.Value = proto.String(j.Command)
+	}
+	command.Arguments = j.Args
+
+	env := make(map[string]string, len(j.Env)+len(extraEnv))
+	for name, value := range j.Env {
+		env[name] = value
+	}
+	for name, value := range extraEnv {
+		env[name] = value
+	}
+
+	if len(env) > 0 {
+		//Sort the names so the same spec always produces the same TaskInfo
+		names := make([]string, 0, len(env))
+		for name := range env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		command.Environment = &mesosproto.Environment{}
+		for _, name := range names {
+			command.Environment.Variables = append(command.Environment.Variables, &mesosproto.Environment_Variable{
+				Name:  proto.String(name),
+				Value: proto.String(env[name]),
+			})
+		}
+	}
+
+	return command
+}
+
+//containerInfo builds the Docker ContainerInfo of a containerized task
+func (j *JobSpec) containerInfo() *mesosproto.ContainerInfo {
+	return &mesosproto.ContainerInfo{
+		Type: mesosproto.ContainerInfo_DOCKER.Enum(),
+		Docker: &mesosproto.ContainerInfo_DockerInfo{
+			Image: proto.String(j.Image),
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package example_scheduler
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//killGraceMargin is how much longer than the longest grace period of the
+//jobs Shutdown waits, for the terminal updates to arrive
+const killGraceMargin = 5 * time.Second
+
+//killGrace returns the time the tasks of the job have to shut down after
+//SIGTERM, 0 for the default of their executor
+func (j *JobSpec) killGrace() time.Duration {
+	return time.Duration(j.KillGracePeriod * float64(time.Second))
+}
+
+//killPolicy builds the KillPolicy of the tasks of the job, nil if it
+//leaves the default grace period of the executor
+func (j *JobSpec) killPolicy() *mesosproto.KillPolicy {
+	if j.KillGracePeriod <= 0 {
+		return nil
+	}
+
+	return &mesosproto.KillPolicy{
+		GracePeriod: &mesosproto.DurationInfo{
+			Nanoseconds: proto.Int64(int64(j.killGrace())),
+		},
+	}
+}
+
+//longestKillGrace returns the longest grace period of the jobs. The caller
+//must hold the mutex
+func (s *ExampleScheduler) longestKillGrace() time.Duration {
+	var longest time.Duration
+	for _, job := range s.jobs {
+		if grace := job.killGrace(); grace > longest {
+			longest = grace
+		}
+	}
+
+	return longest
+}
@@ -0,0 +1,151 @@
+package example_scheduler
+
+import (
+	"errors"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/golang/protobuf/proto"
+	"github.com/mesos/mesos-go/mesosproto"
+	"github.com/satori/go.uuid"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//killRetryTimeout is how long the terminal update of a killed task is
+//waited for, on top of the grace period of its job, before the kill is sent
+//again. Kills are not reliable: the master or the agent may drop them
+const killRetryTimeout = 30 * time.Second
+
+//killOpsKept is how many kills requested through the operations are kept
+//to be polled, the oldest are forgotten first
+const killOpsKept = 1000
+
+//ErrUnknownKill is returned when polling a kill that was never requested
+//or was forgotten
+var ErrUnknownKill = errors.New("unknown kill")
+
+//killOp is a kill requested through the operations, of a task or of every
+//task of a removed job
+type killOp struct {
+	id        string
+	jobId     string
+	taskId    string
+	requested time.Time
+	tasks     []string
+}
+
+//KillSummary describes a kill requested through the operations and how far
+//it went
+type KillSummary struct {
+	ID        string    `json:"id"`
+	JobID     string    `json:"job_id,omitempty"`
+	TaskID    string    `json:"task_id,omitempty"`
+	Requested time.Time `json:"requested"`
+
+	//Tasks are the tasks killed, Remaining the ones whose terminal update
+	//didn't arrive yet
+	Tasks     []string `json:"tasks"`
+	Remaining []string `json:"remaining"`
+
+	//Done is set once every task killed reached a terminal state
+	Done bool `json:"done"`
+}
+
+//sendKill sends the kill of the task to the driver, recording when so it
+//is retried if the task doesn't end. The caller must hold the mutex
+func (s *ExampleScheduler) sendKill(t *taskRecord) error {
+	if _, err := s.driver.KillTask(&mesosproto.TaskID{Value: proto.String(t.id)}); err != nil {
+		return err
+	}
+
+	t.killed = true
+	t.killSent = time.Now()
+	t.killAttempts++
+	s.saveTask(t)
+
+	return nil
+}
+
+//killTimeout returns how long the terminal update of the killed task is
+//waited for. The caller must hold the mutex
+func (s *ExampleScheduler) killTimeout(t *taskRecord) time.Duration {
+	timeout := killRetryTimeout
+	if job := s.job(t.jobId); job != nil {
+		timeout += job.killGrace()
+	}
+
+	return timeout
+}
+
+//retryKills sends again the kills of the tasks that didn't end in time.
+//The caller must hold the mutex
+func (s *ExampleScheduler) retryKills() {
+	for _, t := range s.tasks {
+		if !t.killed || t.killSent.IsZero() || tasks.IsTerminal(t.State) || s.pendingKills[t.id] ||
+			time.Since(t.killSent) < s.killTimeout(t) {
+			continue
+		}
+
+		tlog := taskLog(t).WithFields(log.Fields{
+			"state":    t.State.String(),
+			"attempts": t.killAttempts,
+		})
+		tlog.Warnf("Task still not killed after %v, killing it again", s.killTimeout(t))
+		if err := s.sendKill(t); err != nil {
+			tlog.WithError(err).Errorln("Unable to kill the task")
+		}
+	}
+}
+
+//trackKill records a kill of the tasks so it can be polled until they end,
+//returning its ID. The caller must hold the mutex
+func (s *ExampleScheduler) trackKill(jobId, taskId string, tasks []*taskRecord) string {
+	op := &killOp{
+		id:        uuid.NewV4().String(),
+		jobId:     jobId,
+		taskId:    taskId,
+		requested: time.Now(),
+	}
+	for _, t := range tasks {
+		op.tasks = append(op.tasks, t.id)
+	}
+
+	s.killOps = append(s.killOps, op)
+	if len(s.killOps) > killOpsKept {
+		s.killOps = s.killOps[len(s.killOps)-killOpsKept:]
+	}
+
+	return op.id
+}
+
+//Kill returns how far the kill with the given ID went. The tasks already
+//pruned from the task history count as ended
+func (s *ExampleScheduler) Kill(id string) (KillSummary, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, op := range s.killOps {
+		if op.id != id {
+			continue
+		}
+
+		summary := KillSummary{
+			ID:        op.id,
+			JobID:     op.jobId,
+			TaskID:    op.taskId,
+			Requested: op.requested,
+			Tasks:     append([]string{}, op.tasks...),
+			Remaining: []string{},
+		}
+		for _, taskId := range op.tasks {
+			if t, ok := s.tasks[taskId]; ok && !tasks.IsTerminal(t.State) {
+				summary.Remaining = append(summary.Remaining, taskId)
+			}
+		}
+		summary.Done = len(summary.Remaining) == 0
+
+		return summary, nil
+	}
+
+	return KillSummary{}, ErrUnknownKill
+}
@@ -0,0 +1,103 @@
+package example_scheduler
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//defaultLostAgentCooldown is how long no task is placed on a lost agent
+//when LostAgentCooldown isn't set
+const defaultLostAgentCooldown = 10 * time.Minute
+
+//lostAgentCooldown returns how long no task is placed on a lost agent
+func (s *ExampleScheduler) lostAgentCooldown() time.Duration {
+	if s.LostAgentCooldown > 0 {
+		return s.LostAgentCooldown
+	}
+
+	return defaultLostAgentCooldown
+}
+
+//agentLost replaces the tasks of a lost agent, unless their job never
+//replaces its tasks, and keeps the tasks away from it for the cool-down.
+//The agent is remembered by its ID and its hostname, since it registers
+//again with a new ID. The caller must hold the mutex
+func (s *ExampleScheduler) agentLost(agentId string) {
+	until := s.coolDownAgent(agentId)
+	delete(s.offers, agentId)
+
+	for _, t := range s.tasks {
+		if t.agentId != agentId || tasks.IsTerminal(t.State) || t.replaced {
+			continue
+		}
+
+		job := s.job(t.jobId)
+		if job == nil || job.Restart.Policy == RestartNever {
+			continue
+		}
+
+		t.replaced = true
+		taskLog(t).Warnln("Agent of the task lost, replacing it")
+	}
+
+	log.WithFields(log.Fields{
+		"agent_id": agentId,
+		"until":    until,
+	}).Warnln("No task is placed on the lost agent until the end of its cool-down")
+}
+
+//coolDownAgent keeps the tasks away from the agent for the cool-down. It
+//returns the end of the cool-down. The caller must hold the mutex
+func (s *ExampleScheduler) coolDownAgent(agentId string) time.Time {
+	return s.coolDownAgentFor(agentId, s.lostAgentCooldown())
+}
+
+//coolDownAgentFor keeps the tasks away from the agent for the given time,
+//by its ID and by the hostname its offers and tasks had. A longer cool-down
+//in progress is kept. It returns the end of the cool-down. The caller must
+//hold the mutex
+func (s *ExampleScheduler) coolDownAgentFor(agentId string, d time.Duration) time.Time {
+	until := time.Now().Add(d)
+	keys := []string{agentId}
+
+	if held := s.offers[agentId]; len(held) > 0 {
+		keys = append(keys, held[0].offer.GetHostname())
+	}
+	for _, t := range s.tasks {
+		if t.agentId == agentId && t.hostname != "" {
+			keys = append(keys, t.hostname)
+		}
+	}
+
+	for _, key := range keys {
+		if s.lostAgents[key].Before(until) {
+			s.lostAgents[key] = until
+		}
+	}
+
+	return until
+}
+
+//isLost reports if the agent was lost and is still in its cool-down. It
+//returns the seconds left. The caller must hold the mutex.
+//The agents where the executors kept dying are cooled down the same way
+func (s *ExampleScheduler) isLost(agentId, hostname string) (float64, bool) {
+	var left time.Duration
+	for _, key := range []string{agentId, hostname} {
+		until, ok := s.lostAgents[key]
+		if !ok {
+			continue
+		}
+		if time.Now().After(until) {
+			delete(s.lostAgents, key)
+			continue
+		}
+		if d := time.Until(until); d > left {
+			left = d
+		}
+	}
+
+	return left.Seconds(), left > 0
+}
@@ -0,0 +1,90 @@
+package example_scheduler
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/mesos/mesos-go/scheduler"
+)
+
+//Maintenance tells if the scheduling is paused, why and since when. While
+//paused the offers are suppressed and no task is launched, but the status
+//updates, the health of the tasks and the kills are still handled. The
+//tasks that couldn't be replaced aren't killed: the deployments, the
+//preemptions and the scaling down wait for the resume
+type Maintenance struct {
+	Paused bool       `json:"paused"`
+	Reason string     `json:"reason,omitempty"`
+	Since  *time.Time `json:"since,omitempty"`
+}
+
+//Maintenance returns whether the scheduling is paused
+func (s *ExampleScheduler) Maintenance() Maintenance {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.maintenance()
+}
+
+//maintenance returns the state of the pause. The caller must hold the mutex
+func (s *ExampleScheduler) maintenance() Maintenance {
+	if !s.paused {
+		return Maintenance{}
+	}
+
+	since := s.pausedSince
+	return Maintenance{Paused: true, Reason: s.pauseReason, Since: &since}
+}
+
+//Pause stops the scheduling, for example during an upgrade of the masters:
+//the offers in the pool are declined for long, so the master stops
+//sending them, and the ones received later are declined the same way
+//until Resume. Pausing again only changes the reason. The pause is saved
+//in the store, so a restarted scheduler stays paused
+func (s *ExampleScheduler) Pause(reason string) Maintenance {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.paused {
+		s.paused = true
+		s.pausedSince = time.Now()
+	}
+	s.pauseReason = reason
+	s.saveMaintenance()
+	log.WithField("reason", reason).Warnln("Scheduling paused, no task is launched until resumed")
+
+	if s.driver != nil && !s.disconnected {
+		s.auditAllOffers("scheduling paused")
+		s.suppressAllOffers(s.driver)
+	}
+
+	return s.maintenance()
+}
+
+//Resume starts the scheduling again after Pause, reviving the offers if
+//any instance waits to be launched
+func (s *ExampleScheduler) Resume() Maintenance {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.paused {
+		return s.maintenance()
+	}
+
+	s.paused = false
+	s.pauseReason = ""
+	s.saveMaintenance()
+	log.WithField("paused_for", time.Since(s.pausedSince).String()).Infoln("Scheduling resumed")
+
+	s.reviveIfNeeded(true)
+	return s.maintenance()
+}
+
+//suppressAllOffers declines every offer of the pool for long, without
+//releasing the reserved resources: the jobs still need them once the
+//scheduling is resumed. The caller must hold the mutex
+func (s *ExampleScheduler) suppressAllOffers(driver scheduler.SchedulerDriver) {
+	for agentId := range s.offers {
+		s.declineOffers(driver, agentId, declineIdle)
+	}
+}
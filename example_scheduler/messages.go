@@ -0,0 +1,49 @@
+package example_scheduler
+
+import (
+	"encoding/json"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//TaskMessage is the envelope of the framework messages the executors send
+//about their tasks
+type TaskMessage struct {
+	TaskID string `json:"task_id"`
+
+	//Type of the message, like serving, and its free form data
+	Type string `json:"type"`
+	Data string `json:"data,omitempty"`
+
+	//When the scheduler received the message
+	Received time.Time `json:"received"`
+}
+
+//frameworkMessage decodes a message of an executor and keeps it as the
+//last message of its task. The caller must hold the mutex
+func (s *ExampleScheduler) frameworkMessage(executorId, agentId, msg string) {
+	mlog := log.WithFields(log.Fields{
+		"executor_id": executorId,
+		"agent_id":    agentId,
+	})
+
+	var m TaskMessage
+	if err := json.Unmarshal([]byte(msg), &m); err != nil || m.TaskID == "" {
+		mlog.WithField("message", msg).Warnln("Framework message without a task envelope, ignoring it")
+		return
+	}
+
+	t, ok := s.tasks[m.TaskID]
+	if !ok {
+		mlog.WithField("task_id", m.TaskID).Warnln("Framework message about an unknown task, ignoring it")
+		return
+	}
+
+	m.Received = time.Now()
+	t.message = &m
+	taskLog(t).WithFields(log.Fields{
+		"type": m.Type,
+		"data": m.Data,
+	}).Infoln("Message from the task")
+}
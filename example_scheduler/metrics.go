@@ -0,0 +1,64 @@
+package example_scheduler
+
+import (
+	"strings"
+
+	"minimal-mesos-go-framework/metrics"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//Metrics implements metrics.Source: the instances of every job, the
+//active tasks by state, the offers, the launch queue and the deployments
+//in progress
+func (s *ExampleScheduler) Metrics() []metrics.Metric {
+	var ms []metrics.Metric
+	gauge := func(value float64, name ...string) {
+		ms = append(ms, metrics.Metric{Name: metrics.Name(name...), Value: value})
+	}
+	counter := func(value float64, name ...string) {
+		ms = append(ms, metrics.Metric{Name: metrics.Name(name...), Value: value, Counter: true})
+	}
+
+	for _, job := range s.Jobs() {
+		gauge(float64(job.Instances), "jobs", job.ID, "instances")
+		gauge(float64(job.Running), "jobs", job.ID, "running")
+		gauge(float64(job.Ready), "jobs", job.ID, "ready")
+		gauge(float64(job.Unhealthy), "jobs", job.ID, "unhealthy")
+		gauge(float64(job.Pending), "jobs", job.ID, "pending")
+	}
+
+	offers := s.Offers()
+	counter(float64(offers.Received), "offers", "received")
+	counter(float64(offers.Accepted), "offers", "accepted")
+	counter(float64(offers.Declined), "offers", "declined")
+	counter(float64(offers.Rescinded), "offers", "rescinded")
+	gauge(float64(offers.Held), "offers", "held")
+
+	queue := s.Queue()
+	gauge(float64(queue.Depth), "queue", "depth")
+	gauge(queue.OldestWait, "queue", "oldest_wait")
+	counter(float64(queue.Launched), "queue", "launched")
+
+	var deploying int
+	for _, d := range s.Deployments() {
+		if d.State == DeploymentRunning || d.State == DeploymentWaiting {
+			deploying++
+		}
+	}
+	gauge(float64(deploying), "deployments", "in_progress")
+
+	//The usual states are sent even without tasks, so they drop to zero
+	states := map[string]int{"staging": 0, "starting": 0, "running": 0, "killing": 0, "unreachable": 0}
+	s.mutex.Lock()
+	for _, t := range s.tasks {
+		if !tasks.IsTerminal(t.State) {
+			states[strings.ToLower(strings.TrimPrefix(t.State.String(), "TASK_"))]++
+		}
+	}
+	s.mutex.Unlock()
+	for state, n := range states {
+		gauge(float64(n), "tasks", state)
+	}
+
+	return ms
+}
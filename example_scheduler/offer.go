@@ -0,0 +1,482 @@
+package example_scheduler
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/golang/protobuf/proto"
+	"github.com/mesos/mesos-go/mesosproto"
+	"github.com/mesos/mesos-go/scheduler"
+)
+
+//offerHoldTime is how long an offer that doesn't fit any task is kept,
+//waiting for more offers of the same agent to merge with
+const offerHoldTime = 2 * time.Second
+
+//heldOffer is an offer in the pool of the scheduler
+type heldOffer struct {
+	offer    *mesosproto.Offer
+	received time.Time
+}
+
+//offerStats are the totals of the offers received and of what became of
+//them
+type offerStats struct {
+	received  int64
+	accepted  int64
+	declined  int64
+	rescinded int64
+}
+
+//OfferStats are the offers received since the scheduler started, the ones
+//accepted, declined and rescinded, and the ones held in the pool now
+type OfferStats struct {
+	Received  int64 `json:"received"`
+	Accepted  int64 `json:"accepted"`
+	Declined  int64 `json:"declined"`
+	Rescinded int64 `json:"rescinded"`
+	Held      int   `json:"held"`
+}
+
+//Offers returns the statistics of the offers
+func (s *ExampleScheduler) Offers() OfferStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := OfferStats{
+		Received:  s.offerStats.received,
+		Accepted:  s.offerStats.accepted,
+		Declined:  s.offerStats.declined,
+		Rescinded: s.offerStats.rescinded,
+	}
+	for _, held := range s.offers {
+		stats.Held += len(held)
+	}
+
+	return stats
+}
+
+//holdOffer adds the offer to the pool. The caller must hold the mutex
+func (s *ExampleScheduler) holdOffer(offer *mesosproto.Offer) {
+	s.offerStats.received++
+	agentId := offer.SlaveId.GetValue()
+	s.offers[agentId] = append(s.offers[agentId], &heldOffer{offer: offer, received: time.Now()})
+}
+
+//removeOffer removes a rescinded offer from the pool. The caller must hold
+//the mutex
+func (s *ExampleScheduler) removeOffer(offerId string) {
+	for agentId, held := range s.offers {
+		for i, h := range held {
+			if h.offer.Id.GetValue() != offerId {
+				continue
+			}
+
+			s.offerStats.rescinded++
+			held = append(held[:i], held[i+1:]...)
+			if len(held) == 0 {
+				delete(s.offers, agentId)
+			} else {
+				s.offers[agentId] = held
+			}
+			return
+		}
+	}
+}
+
+//declineOffers declines every offer of the agent in the pool, asking the
+//master not to offer its resources again for the time the DeclinePolicy
+//gives to the reason. The caller must hold the mutex
+func (s *ExampleScheduler) declineOffers(driver scheduler.SchedulerDriver, agentId string, reason declineReason) {
+	s.declineOffersFor(driver, agentId, s.refuseSeconds(reason))
+}
+
+//declineOffersFor declines every offer of the agent in the pool, asking the
+//master not to offer its resources again for the given seconds. The caller
+//must hold the mutex
+func (s *ExampleScheduler) declineOffersFor(driver scheduler.SchedulerDriver, agentId string, seconds float64) {
+	filters := &mesosproto.Filters{RefuseSeconds: proto.Float64(seconds)}
+	for _, h := range s.offers[agentId] {
+		driver.DeclineOffer(h.offer.Id, filters)
+	}
+	delete(s.offers, agentId)
+}
+
+//declineAllOffers empties the pool for a short while. The caller must hold
+//the mutex
+func (s *ExampleScheduler) declineAllOffers(driver scheduler.SchedulerDriver) {
+	for agentId := range s.offers {
+		s.declineOffers(driver, agentId, declineWaiting)
+	}
+}
+
+//launchOperation returns the operation of an Accept call that launches the
+//tasks
+func launchOperation(tasks []*mesosproto.TaskInfo) *mesosproto.Offer_Operation {
+	return &mesosproto.Offer_Operation{
+		Type:   mesosproto.Offer_Operation_LAUNCH.Enum(),
+		Launch: &mesosproto.Offer_Operation_Launch{TaskInfos: tasks},
+	}
+}
+
+//launchFailed forgets the tasks and the volumes of an Accept call the
+//driver couldn't send, so their instances wait again in the launch queue
+//for the next offers, and declines its offers for a short while. The caller
+//must hold the mutex
+func (s *ExampleScheduler) launchFailed(driver scheduler.SchedulerDriver, agent *agentOffers) {
+	s.requeue(agent.tasks, true)
+	for _, task := range agent.tasks {
+		delete(s.tasks, task.TaskId.GetValue())
+		s.deleteTask(task.TaskId.GetValue())
+	}
+	for _, volume := range agent.create {
+		delete(s.volumes, volume.GetDisk().GetPersistence().GetId())
+	}
+
+	filters := &mesosproto.Filters{RefuseSeconds: proto.Float64(s.Decline.refuseSeconds(declineWaiting))}
+	for _, offer := range agent.offers {
+		if _, err := driver.DeclineOffer(offer.Id, filters); err != nil {
+			offerLog(offer).WithError(err).Errorln("Unable to decline the offer")
+		}
+	}
+}
+
+//heldOffers returns the offers of the pool entries
+func heldOffers(held []*heldOffer) []*mesosproto.Offer {
+	offers := make([]*mesosproto.Offer, len(held))
+	for i, h := range held {
+		offers[i] = h.offer
+	}
+
+	return offers
+}
+
+//oldestHeld returns when the oldest of the offers was received
+func oldestHeld(held []*heldOffer) time.Time {
+	oldest := held[0].received
+	for _, h := range held[1:] {
+		if h.received.Before(oldest) {
+			oldest = h.received
+		}
+	}
+
+	return oldest
+}
+
+//offerIDs returns the IDs of the offers
+func offerIDs(offers []*mesosproto.Offer) []*mesosproto.OfferID {
+	ids := make([]*mesosproto.OfferID, len(offers))
+	for i, offer := range offers {
+		ids[i] = offer.Id
+	}
+
+	return ids
+}
+
+//agentLog returns a logger with the fields that identify a set of offers of
+//the same agent
+func agentLog(offers []*mesosproto.Offer) *log.Entry {
+	ids := make([]string, len(offers))
+	for i, offer := range offers {
+		ids[i] = offer.Id.GetValue()
+	}
+
+	return log.WithFields(log.Fields{
+		"offer_ids": ids,
+		"hostname":  offers[0].GetHostname(),
+	})
+}
+
+//offerResources are the resources of an offer not yet assigned to a task
+type offerResources struct {
+	//The cpus, mem, disk and gpus by name, split by role and reservation.
+	//The parts reserved for us go first, so they are used before the
+	//unreserved ones
+	scalars map[string][]*scalarPart
+
+	//The free port ranges, the reserved ones first
+	ports []*portRange
+
+	//The persistent volumes offered, not used by any task
+	volumes []*mesosproto.Resource
+
+	//The resources dynamically reserved for us as offered, to unreserve
+	//them
+	reserved []*mesosproto.Resource
+}
+
+//scalarPart is the amount left of a scalar resource of a role and
+//reservation
+type scalarPart struct {
+	//A resource of the part as offered, to copy its role and reservation
+	//from
+	template *mesosproto.Resource
+	value    float64
+}
+
+//portRange is a range of free ports of a role and reservation
+type portRange struct {
+	*mesosproto.Value_Range
+	template *mesosproto.Resource
+}
+
+//taken are the resources assigned to a task
+type taken struct {
+	ports []uint64
+
+	//The cpus, mem, disk and gpus taken, with the role and reservation
+	//they were offered with
+	scalars []*mesosproto.Resource
+
+	//The ports resources, one per role and reservation
+	portResources []*mesosproto.Resource
+}
+
+//newOfferResources sums the resources of the offers
+func newOfferResources(offers ...*mesosproto.Offer) *offerResources {
+	res := &offerResources{scalars: make(map[string][]*scalarPart)}
+
+	for _, offer := range offers {
+		for _, resource := range offer.Resources {
+			if resource.Reservation != nil && resource.GetDisk().GetPersistence() == nil {
+				res.reserved = append(res.reserved, resource)
+			}
+
+			switch resource.GetName() {
+			case "cpus", "mem", "gpus":
+				res.addScalar(resource)
+			case "disk":
+				if resource.GetDisk().GetPersistence() != nil {
+					res.volumes = append(res.volumes, resource)
+					continue
+				}
+				res.addScalar(resource)
+			case "ports":
+				//Only cpus and mem are oversubscribed
+				if resource.Revocable != nil {
+					continue
+				}
+				for _, r := range resource.GetRanges().GetRange() {
+					res.ports = append(res.ports, &portRange{
+						Value_Range: &mesosproto.Value_Range{Begin: r.Begin, End: r.End},
+						template:    resource,
+					})
+				}
+			}
+		}
+	}
+
+	for _, parts := range res.scalars {
+		sort.SliceStable(parts, func(i, j int) bool { return resourceRank(parts[i].template) < resourceRank(parts[j].template) })
+	}
+	sort.SliceStable(res.ports, func(i, j int) bool {
+		return resourceRank(res.ports[i].template) < resourceRank(res.ports[j].template)
+	})
+
+	return res
+}
+
+//resourceRank orders the resources by preference: the ones dynamically
+//reserved for us, the ones statically reserved for our role, the
+//unreserved ones and the revocable ones
+func resourceRank(resource *mesosproto.Resource) int {
+	switch {
+	case resource.Revocable != nil:
+		return 3
+	case resource.Reservation != nil:
+		return 0
+	case !isUnreserved(resource):
+		return 1
+	}
+
+	return 2
+}
+
+//isUnreserved reports if the resource can be used by any role
+func isUnreserved(resource *mesosproto.Resource) bool {
+	return resource.Reservation == nil && (resource.GetRole() == "" || resource.GetRole() == "*")
+}
+
+//sameKind reports if two resources have the same role, reservation and
+//revocability, so their amounts can be merged
+func sameKind(a, b *mesosproto.Resource) bool {
+	return a.GetRole() == b.GetRole() &&
+		(a.Reservation == nil) == (b.Reservation == nil) &&
+		(a.Revocable == nil) == (b.Revocable == nil) &&
+		a.GetReservation().GetPrincipal() == b.GetReservation().GetPrincipal()
+}
+
+func (r *offerResources) addScalar(resource *mesosproto.Resource) {
+	name := resource.GetName()
+	for _, part := range r.scalars[name] {
+		if sameKind(part.template, resource) {
+			part.value += resource.GetScalar().GetValue()
+			return
+		}
+	}
+
+	r.scalars[name] = append(r.scalars[name], &scalarPart{template: resource, value: resource.GetScalar().GetValue()})
+}
+
+//scalar returns the amount left of a scalar resource, of any role. The
+//revocable resources don't count
+func (r *offerResources) scalar(name string) float64 {
+	var value float64
+	for _, part := range r.scalars[name] {
+		if part.template.Revocable == nil {
+			value += part.value
+		}
+	}
+
+	return value
+}
+
+//revocable returns the amount left of a scalar resource that is revocable
+func (r *offerResources) revocable(name string) float64 {
+	var value float64
+	for _, part := range r.scalars[name] {
+		if part.template.Revocable != nil {
+			value += part.value
+		}
+	}
+
+	return value
+}
+
+//available returns the amount left of a scalar resource the tasks of the
+//job can use, counting the revocable resources if the job accepts them
+func (r *offerResources) available(name string, job *JobSpec) float64 {
+	if job.Revocable {
+		return r.scalar(name) + r.revocable(name)
+	}
+
+	return r.scalar(name)
+}
+
+//scalarPartOf returns the first part of the scalar resource with at least
+//value left, among the reserved or the unreserved ones, or nil. The
+//revocable parts are never returned
+func (r *offerResources) scalarPartOf(name string, value float64, reserved bool) *scalarPart {
+	for _, part := range r.scalars[name] {
+		if part.template.Revocable == nil && isUnreserved(part.template) != reserved && part.value >= value {
+			return part
+		}
+	}
+
+	return nil
+}
+
+//takeScalar subtracts value from a scalar resource, which must have
+//enough, using the reserved parts first. With revocable, the revocable parts
+//are used before any other, otherwise they aren't used at all. It returns
+//the resources taken
+func (r *offerResources) takeScalar(name string, value float64, revocable bool) []*mesosproto.Resource {
+	var resources []*mesosproto.Resource
+
+	parts := r.scalars[name]
+	if revocable {
+		parts = make([]*scalarPart, 0, len(r.scalars[name]))
+		for _, part := range r.scalars[name] {
+			if part.template.Revocable != nil {
+				parts = append(parts, part)
+			}
+		}
+		for _, part := range r.scalars[name] {
+			if part.template.Revocable == nil {
+				parts = append(parts, part)
+			}
+		}
+	}
+
+	for _, part := range parts {
+		if value <= 0 {
+			break
+		}
+		if part.template.Revocable != nil && !revocable {
+			continue
+		}
+
+		amount := math.Min(part.value, value)
+		if amount <= 0 {
+			continue
+		}
+		part.value -= amount
+		value -= amount
+
+		resources = append(resources, part.resource(amount))
+	}
+
+	return resources
+}
+
+//resource returns a resource of the part with the given amount
+func (p *scalarPart) resource(value float64) *mesosproto.Resource {
+	resource := proto.Clone(p.template).(*mesosproto.Resource)
+	resource.Scalar = &mesosproto.Value_Scalar{Value: proto.Float64(value)}
+
+	return resource
+}
+
+//volumeOf returns a volume of the job in the offers, or nil
+func (r *offerResources) volumeOf(job *JobSpec) *mesosproto.Resource {
+	for _, volume := range r.volumes {
+		if jobOfTask(volume.GetDisk().GetPersistence().GetId()) == job.ID {
+			return volume
+		}
+	}
+
+	return nil
+}
+
+//removeVolume removes a volume assigned to a task
+func (r *offerResources) removeVolume(volume *mesosproto.Resource) {
+	for i, v := range r.volumes {
+		if v == volume {
+			r.volumes = append(r.volumes[:i], r.volumes[i+1:]...)
+			return
+		}
+	}
+}
+
+//portCount returns the number of free ports
+func (r *offerResources) portCount() uint64 {
+	var n uint64
+	for _, p := range r.ports {
+		n += p.GetEnd() - p.GetBegin() + 1
+	}
+
+	return n
+}
+
+//fits reports if a task of the job fits in the remaining resources
+func (r *offerResources) fits(job *JobSpec) bool {
+	return r.available("cpus", job) >= job.needs("cpus") &&
+		r.available("mem", job) >= job.needs("mem") &&
+		r.available("disk", job) >= job.needs("disk") &&
+		r.available("gpus", job) >= job.needs("gpus") &&
+		r.fitsPorts(job)
+}
+
+//take subtracts the resources of a task of the job, which must fit, using
+//the reserved ones first
+func (r *offerResources) take(job *JobSpec) taken {
+	var t taken
+
+	for _, scalar := range []struct {
+		name  string
+		value float64
+	}{
+		{"cpus", job.Cpus},
+		{"mem", job.Mem},
+		{"disk", job.Disk},
+		{"gpus", job.Gpus},
+	} {
+		t.scalars = append(t.scalars, r.takeScalar(scalar.name, scalar.value, job.Revocable)...)
+	}
+
+	t.ports, t.portResources = r.takePorts(job)
+
+	return t
+}
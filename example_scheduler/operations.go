@@ -0,0 +1,520 @@
+package example_scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/tasks"
+)
+
+var (
+	//ErrUnknownJob is returned by the operations on a job that doesn't exist
+	ErrUnknownJob = errors.New("unknown job")
+
+	//ErrUnknownTask is returned by the operations on a task that doesn't exist
+	ErrUnknownTask = errors.New("unknown task")
+
+	//ErrJobExists is returned when submitting a job with the ID of another one
+	ErrJobExists = errors.New("job already exists")
+
+	//ErrNotRegistered is returned by the operations that need the driver
+	//before the scheduler has received any callback from it
+	ErrNotRegistered = errors.New("scheduler not registered with the master")
+)
+
+//Validate checks that the job can be launched
+func (j *JobSpec) Validate() error {
+	switch {
+	case j.ID == "":
+		return errors.New("job id is required")
+	case strings.Contains(j.ID, taskJobSeparator):
+		return fmt.Errorf("job id can't contain %q", taskJobSeparator)
+	case j.Cpus <= 0:
+		return errors.New("cpus must be greater than 0")
+	case j.Mem <= 0:
+		return errors.New("mem must be greater than 0")
+	case j.Disk < 0:
+		return errors.New("disk can't be negative")
+	case j.Gpus < 0:
+		return errors.New("gpus can't be negative")
+	case j.Gpus > 0 && j.Image != "":
+		return errors.New("gpus are only supported by tasks without an image")
+	case j.Type != "" && j.Type != JobService && j.Type != JobBatch:
+		return fmt.Errorf("unknown job type %q, use %s or %s", j.Type, JobService, JobBatch)
+	case j.Instances < 0:
+		return errors.New("instances can't be negative")
+	case j.MaxRuntime < 0:
+		return errors.New("max runtime can't be negative")
+	case j.KillGracePeriod < 0:
+		return errors.New("kill grace period can't be negative")
+	}
+
+	for _, c := range j.Constraints {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := j.validatePorts(); err != nil {
+		return err
+	}
+
+	if err := j.validateAffinity(); err != nil {
+		return err
+	}
+
+	if err := j.validateContainers(); err != nil {
+		return err
+	}
+
+	if err := j.validateDependencies(); err != nil {
+		return err
+	}
+
+	if err := j.validateIndexed(); err != nil {
+		return err
+	}
+
+	if j.Readiness != nil {
+		if err := j.Readiness.validate(j); err != nil {
+			return err
+		}
+	}
+
+	if j.Volume != nil {
+		if err := j.Volume.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if j.HealthCheck != nil {
+		if err := j.HealthCheck.validate(j); err != nil {
+			return err
+		}
+	}
+
+	if err := j.Upgrade.Validate(); err != nil {
+		return err
+	}
+
+	if j.Cron != nil {
+		if err := j.Cron.validate(j); err != nil {
+			return err
+		}
+	}
+
+	return j.Restart.Validate()
+}
+
+//SubmitJob adds a new job to the scheduler. Its tasks are launched on the
+//next offers
+func (s *ExampleScheduler) SubmitJob(job *JobSpec) error {
+	if err := job.Validate(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.submitJob(job)
+}
+
+//submitJob adds the validated job to the scheduler. The caller must hold
+//the mutex
+func (s *ExampleScheduler) submitJob(job *JobSpec) error {
+	if err := s.checkVolume(job); err != nil {
+		return err
+	}
+	if err := s.checkRole(job); err != nil {
+		return err
+	}
+	if err := s.checkCommandReadiness(job); err != nil {
+		return err
+	}
+	if s.job(job.ID) != nil {
+		return ErrJobExists
+	}
+	if err := s.checkDependencies(job); err != nil {
+		return err
+	}
+	if err := s.checkQueue(job.Instances); err != nil {
+		return err
+	}
+
+	s.jobs = append(s.jobs, job)
+	s.saveJob(job)
+	s.evaluatePipeline()
+	s.reviveIfNeeded(true)
+	log.WithField("job_id", job.ID).Infof("Job submitted with %d instances", job.Instances)
+
+	return nil
+}
+
+//BatchError is the error of a job of a batch submission, because of which
+//none of its jobs was submitted
+type BatchError struct {
+	//Index is the position of the job in the batch, from 0
+	Index int
+	JobID string
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("job %d (%s): %v", e.Index, e.JobID, e.Err)
+}
+
+//SubmitJobs adds several jobs at once, all of them or none: every job is
+//checked as SubmitJob does, before any is submitted. The jobs may depend
+//on each other. The error of a job is a BatchError
+func (s *ExampleScheduler) SubmitJobs(jobs []*JobSpec) error {
+	for i, job := range jobs {
+		if err := job.Validate(); err != nil {
+			return &BatchError{Index: i, JobID: job.ID, Err: err}
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	given := make(map[string]bool)
+	instances := 0
+	for i, job := range jobs {
+		err := s.checkVolume(job)
+		if err == nil {
+			err = s.checkRole(job)
+		}
+		if err == nil {
+			err = s.checkCommandReadiness(job)
+		}
+		if err == nil && (s.job(job.ID) != nil || given[job.ID]) {
+			err = ErrJobExists
+		}
+		if err != nil {
+			return &BatchError{Index: i, JobID: job.ID, Err: err}
+		}
+		given[job.ID] = true
+		instances += job.Instances
+	}
+	if err := s.checkQueue(instances); err != nil {
+		return err
+	}
+
+	//The dependencies are checked with the whole batch in, so the jobs can
+	//depend on the ones after them
+	existing := s.jobs
+	s.jobs = append(append([]*JobSpec{}, existing...), jobs...)
+	for i, job := range jobs {
+		if err := s.checkDependencies(job); err != nil {
+			s.jobs = existing
+			return &BatchError{Index: i, JobID: job.ID, Err: err}
+		}
+	}
+
+	for _, job := range jobs {
+		s.saveJob(job)
+		log.WithField("job_id", job.ID).Infof("Job submitted with %d instances", job.Instances)
+	}
+	s.evaluatePipeline()
+	s.reviveIfNeeded(true)
+
+	return nil
+}
+
+//Tasks returns a summary of every task known by the scheduler, sorted by
+//job and launch time
+func (s *ExampleScheduler) Tasks() []TaskSummary {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	summaries := make([]TaskSummary, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		summaries = append(summaries, TaskSummary{
+			ID:            t.id,
+			JobID:         t.jobId,
+			Hostname:      t.hostname,
+			AgentID:       t.agentId,
+			State:         t.State.String(),
+			Launched:      t.launched,
+			Updated:       t.Updated,
+			StatusMessage: t.StatusMessage,
+			History:       append([]tasks.Transition{}, t.History...),
+			TimedOut:      t.timedOut,
+			Healthy:       t.healthy,
+			Ready:         s.isReady(t),
+			Cpus:          t.cpus,
+			Mem:           t.mem,
+			Disk:          t.disk,
+			Gpus:          t.gpus,
+			Message:       t.message,
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].JobID != summaries[j].JobID {
+			return summaries[i].JobID < summaries[j].JobID
+		}
+		return summaries[i].Launched.Before(summaries[j].Launched)
+	})
+
+	return summaries
+}
+
+//KillTask asks Mesos to kill a task, sending the kill again until the task
+//ends. As the job keeps its number of instances, a replacement will be
+//launched on the next offers, unless scale is set: the job loses the
+//instance instead. It returns the ID of the kill to poll with Kill
+func (s *ExampleScheduler) KillTask(taskId string, scale bool) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	t, ok := s.tasks[taskId]
+	if !ok {
+		return "", ErrUnknownTask
+	}
+	if tasks.IsTerminal(t.State) {
+		return "", fmt.Errorf("task %s already ended", taskId)
+	}
+
+	killed := t.killed
+	if err := s.kill(t, "killed by the operator"); err != nil {
+		return "", err
+	}
+
+	//A task being killed already was accounted for
+	job := s.job(t.jobId)
+	if scale && !killed && job != nil && job.Instances > 0 {
+		job.Instances--
+		log.WithField("job_id", job.ID).Infof("Job scaled down to %d instances with the kill of task %s", job.Instances, taskId)
+	}
+
+	return s.trackKill(t.jobId, t.id, []*taskRecord{t}), nil
+}
+
+//RemoveJob kills every task of a job and forgets it, so nothing replaces
+//them. The ended tasks stay in the task history. It returns the ID of the
+//kill to poll with Kill. The jobs depending on it must be removed first
+func (s *ExampleScheduler) RemoveJob(jobId string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job := s.job(jobId)
+	if job == nil {
+		return "", ErrUnknownJob
+	}
+	for _, other := range s.jobs {
+		for _, id := range other.DependsOn {
+			if id == jobId {
+				return "", ErrHasDependents
+			}
+		}
+	}
+
+	var running []*taskRecord
+	for _, t := range s.tasks {
+		if t.jobId == jobId && !tasks.IsTerminal(t.State) {
+			running = append(running, t)
+		}
+	}
+	if len(running) > 0 && s.driver == nil {
+		return "", ErrNotRegistered
+	}
+
+	for _, t := range running {
+		if err := s.kill(t, "job removed"); err != nil {
+			taskLog(t).WithError(err).Errorln("Unable to kill the task")
+		}
+	}
+
+	jobs := s.jobs[:0]
+	for _, j := range s.jobs {
+		if j.ID != jobId {
+			jobs = append(jobs, j)
+		}
+	}
+	s.jobs = jobs
+	delete(s.restarts, jobId)
+	delete(s.versions, jobId)
+	delete(s.deployments, jobId)
+	delete(s.starving, jobId)
+	delete(s.crons, jobId)
+	delete(s.pipeline, jobId)
+	delete(s.unplaced, jobId)
+	s.syncQueue()
+	s.deleteJob(jobId)
+
+	log.WithField("job_id", jobId).Infof("Job removed, killing its %d tasks", len(running))
+	return s.trackKill(jobId, "", running), nil
+}
+
+//Scale changes the number of instances of a job. Scaling up queues the new
+//instances for the next offers, scaling down kills the tasks chosen by the
+//kill selection, the least healthy first if empty. It returns the ID of the
+//deployment to follow until the job runs its new number of instances
+func (s *ExampleScheduler) Scale(jobId string, instances int, selection string) (string, error) {
+	if instances < 0 {
+		return "", errors.New("instances can't be negative")
+	}
+	if err := validKillSelection(selection); err != nil {
+		return "", err
+	}
+	if selection == "" {
+		selection = KillLeastHealthyFirst
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	job := s.job(jobId)
+	if job == nil {
+		return "", ErrUnknownJob
+	}
+	if err := s.checkQueue(instances - job.Instances); err != nil {
+		return "", err
+	}
+
+	log.WithField("job_id", jobId).Infof("Scaling job from %d to %d instances", job.Instances, instances)
+	job.Instances = instances
+	s.saveJob(job)
+	d := s.startScale(job)
+	s.reviveIfNeeded(true)
+
+	if err := s.killExcessBy(job, selection); err != nil {
+		return "", err
+	}
+
+	return deploymentID(d.jobId, d.version), nil
+}
+
+//UpdateJob replaces the spec of an existing job. The new one applies to the
+//next launches, and if it changes the tasks a deployment replaces the
+//running ones following the upgrade strategy of the job. If the new spec
+//has less instances the least healthy tasks are killed
+func (s *ExampleScheduler) UpdateJob(spec *JobSpec) error {
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.checkVolume(spec); err != nil {
+		return err
+	}
+	if err := s.checkRole(spec); err != nil {
+		return err
+	}
+	if err := s.checkCommandReadiness(spec); err != nil {
+		return err
+	}
+	job := s.job(spec.ID)
+	if job == nil {
+		return ErrUnknownJob
+	}
+	if err := s.checkDependencies(spec); err != nil {
+		return err
+	}
+	if err := s.checkQueue(spec.Instances - job.Instances); err != nil {
+		return err
+	}
+
+	log.WithField("job_id", spec.ID).Infoln("Updating job")
+	previous := *job
+	*job = *spec
+	s.saveJob(job)
+	if specChanged(&previous, job) && len(s.activeTasks(job.ID)) > 0 && job.Cron == nil {
+		s.startDeployment(job, &previous)
+	}
+
+	//The new spec may have fixed what made the tasks fail
+	delete(s.restarts, job.ID)
+	s.reviveIfNeeded(true)
+
+	return s.killExcess(job)
+}
+
+//checkVolume verifies that the scheduler can create the volumes of the job.
+//The caller must hold the mutex
+func (s *ExampleScheduler) checkVolume(job *JobSpec) error {
+	if job.Volume != nil && s.Reservation == nil {
+		return errors.New("persistent volumes need the scheduler to reserve resources")
+	}
+
+	return nil
+}
+
+//killExcess kills the tasks of the job above its number of instances, the
+//least healthy first. The caller must hold the mutex
+func (s *ExampleScheduler) killExcess(job *JobSpec) error {
+	return s.killExcessBy(job, KillLeastHealthyFirst)
+}
+
+//killExcessBy kills the tasks of the job above its number of instances in
+//the order of the selection. The caller must hold the mutex
+func (s *ExampleScheduler) killExcessBy(job *JobSpec, selection string) error {
+	//The runs of a cron job only launch the tasks they need
+	if job.Cron != nil {
+		return nil
+	}
+
+	//The tasks already being killed are on their way out
+	var active []*taskRecord
+	for _, t := range s.instanceTasks(job) {
+		if !t.killed {
+			active = append(active, t)
+		}
+	}
+	if len(active) <= job.Instances {
+		return nil
+	}
+
+	s.sortVictims(active, selection)
+	for _, t := range active[:len(active)-job.Instances] {
+		if err := s.kill(t, fmt.Sprintf("above the %d instances of the job", job.Instances)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//kill sends the kill of the task to the driver, recording the reason in the
+//audit log. The caller must hold the mutex
+func (s *ExampleScheduler) kill(t *taskRecord, reason string) error {
+	if s.driver == nil {
+		return ErrNotRegistered
+	}
+
+	s.audit(AuditEntry{
+		Action:   AuditKill,
+		JobID:    t.jobId,
+		TaskID:   t.id,
+		AgentID:  t.agentId,
+		Hostname: t.hostname,
+		Reason:   reason,
+	})
+
+	if s.disconnected {
+		taskLog(t).Infoln("Disconnected from the master, the kill is sent once connected again")
+		s.pendingKills[t.id] = true
+		t.killed = true
+		s.saveTask(t)
+		return nil
+	}
+
+	taskLog(t).Infoln("Killing task")
+	return s.sendKill(t)
+}
+
+//job returns the job with the given ID or nil. The caller must hold the mutex
+func (s *ExampleScheduler) job(jobId string) *JobSpec {
+	for _, job := range s.jobs {
+		if job.ID == jobId {
+			return job
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package example_scheduler
+
+import (
+	"time"
+
+	"minimal-mesos-go-framework/tasks"
+)
+
+//defaultUnreachableGrace is how long an unreachable task is waited for
+//when UnreachableGrace isn't set
+const defaultUnreachableGrace = 5 * time.Minute
+
+//unreachableGrace returns how long an unreachable task is waited for
+//before it is replaced
+func (s *ExampleScheduler) unreachableGrace() time.Duration {
+	if s.UnreachableGrace > 0 {
+		return s.UnreachableGrace
+	}
+
+	return defaultUnreachableGrace
+}
+
+//trackUnreachable records since when the task is unreachable, or that it
+//came back. The caller must hold the mutex
+func (s *ExampleScheduler) trackUnreachable(t *taskRecord) {
+	tlog := taskLog(t)
+
+	switch {
+	case tasks.IsUnreachable(t.State):
+		if t.unreachableSince.IsZero() {
+			t.unreachableSince = time.Now()
+			tlog.Warnf("Task unreachable, waiting %v for it to come back before replacing it", s.unreachableGrace())
+		}
+	case !t.unreachableSince.IsZero():
+		t.unreachableSince = time.Time{}
+		if !tasks.IsTerminal(t.State) && t.replaced {
+			//The controller kills the excess tasks of the job
+			t.replaced = false
+			tlog.Warnln("Task reachable again after being replaced")
+		} else if !tasks.IsTerminal(t.State) {
+			tlog.Infoln("Task reachable again")
+		}
+	}
+}
+
+//replaceUnreachable replaces the tasks unreachable for longer than the
+//grace period, unless their job never replaces its tasks. They stop
+//counting as instances of their job. The caller must hold the mutex
+func (s *ExampleScheduler) replaceUnreachable() {
+	for _, t := range s.tasks {
+		if t.replaced || !tasks.IsUnreachable(t.State) ||
+			time.Since(t.unreachableSince) < s.unreachableGrace() {
+			continue
+		}
+
+		job := s.job(t.jobId)
+		if job == nil || job.Restart.Policy == RestartNever {
+			continue
+		}
+
+		t.replaced = true
+		taskLog(t).WithField("unreachable_since", t.unreachableSince).Warnln("Task unreachable for too long, replacing it")
+	}
+}
@@ -0,0 +1,346 @@
+package example_scheduler
+
+import (
+	"encoding/json"
+	"reflect"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/mesos/mesos-go/mesosproto"
+	"minimal-mesos-go-framework/store"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//saveTask saves the task in the store. The ended tasks are kept for the
+//task history until they are pruned. The caller must hold the mutex
+func (s *ExampleScheduler) saveTask(t *taskRecord) {
+	if s.Store == nil {
+		return
+	}
+
+	task := &store.Task{
+		ID:       t.id,
+		JobID:    t.jobId,
+		Hostname: t.hostname,
+		AgentID:  t.agentId,
+		State:    t.State.String(),
+		Launched: t.launched,
+		Updated:  t.Updated,
+		Message:  t.StatusMessage,
+		Fields:   t.fields,
+		Cpus:     t.cpus,
+		Mem:      t.mem,
+		Disk:     t.disk,
+		Gpus:     t.gpus,
+		Volume:   t.volume,
+		Ports:    t.ports,
+		Version:  t.version,
+		Run:      t.run,
+		Index:    t.index,
+		Killed:   t.killed,
+		TimedOut: t.timedOut,
+	}
+	//The launch is journaled with its offers until it is confirmed
+	if t.Updated.IsZero() {
+		task.Offers = t.offerIds
+	}
+
+	var err error
+	if history, ok := s.Store.(store.TaskHistory); ok && tasks.IsTerminal(t.State) {
+		err = history.EndTask(task)
+	} else {
+		err = s.Store.SaveTask(task)
+	}
+	if err != nil {
+		taskLog(t).WithError(err).Errorln("Unable to save the task")
+	}
+}
+
+//saveTasks saves the tasks about to be launched. The caller must hold the
+//mutex
+func (s *ExampleScheduler) saveTasks(tasks []*mesosproto.TaskInfo) {
+	for _, task := range tasks {
+		if t, ok := s.tasks[task.TaskId.GetValue()]; ok {
+			s.saveTask(t)
+		}
+	}
+}
+
+//deleteTask forgets the task in the store. The caller must hold the mutex
+func (s *ExampleScheduler) deleteTask(taskId string) {
+	if s.Store == nil {
+		return
+	}
+
+	if err := s.Store.DeleteTask(taskId); err != nil {
+		log.WithField("task_id", taskId).WithError(err).Errorln("Unable to forget the task")
+	}
+}
+
+//saveJob saves the spec of the job in the store. The caller must hold the
+//mutex
+func (s *ExampleScheduler) saveJob(job *JobSpec) {
+	if s.Store == nil {
+		return
+	}
+
+	spec, err := json.Marshal(job)
+	if err == nil {
+		err = s.Store.SaveJob(job.ID, spec)
+	}
+	if err != nil {
+		log.WithField("job_id", job.ID).WithError(err).Errorln("Unable to save the job")
+	}
+}
+
+//deleteJob forgets the job and its deployment in the store. The caller
+//must hold the mutex
+func (s *ExampleScheduler) deleteJob(jobId string) {
+	if s.Store == nil {
+		return
+	}
+
+	if err := s.Store.DeleteJob(jobId); err != nil {
+		log.WithField("job_id", jobId).WithError(err).Errorln("Unable to forget the job")
+	}
+}
+
+//saveSubmission saves the submission in the store. The caller must hold
+//the mutex
+func (s *ExampleScheduler) saveSubmission(submission *store.Submission) {
+	if s.Store == nil {
+		return
+	}
+
+	if err := s.Store.SaveSubmission(submission); err != nil {
+		log.WithField("job_id", submission.JobID).WithError(err).Errorln("Unable to save the idempotency key of the submission")
+	}
+}
+
+//saveMaintenance saves in the store whether the scheduling is paused. The
+//caller must hold the mutex
+func (s *ExampleScheduler) saveMaintenance() {
+	if s.Store == nil {
+		return
+	}
+
+	var maintenance *store.Maintenance
+	if s.paused {
+		maintenance = &store.Maintenance{Reason: s.pauseReason, Since: s.pausedSince}
+	}
+	if err := s.Store.SaveMaintenance(maintenance); err != nil {
+		log.WithError(err).Errorln("Unable to save the pause of the scheduling")
+	}
+}
+
+//deleteSubmission forgets the submission of the key in the store. The
+//caller must hold the mutex
+func (s *ExampleScheduler) deleteSubmission(key string) {
+	if s.Store == nil {
+		return
+	}
+
+	if err := s.Store.DeleteSubmission(key); err != nil {
+		log.WithError(err).Errorln("Unable to forget the idempotency key of a submission")
+	}
+}
+
+//saveDeployments saves the deployments that changed since they were last
+//saved. The caller must hold the mutex
+func (s *ExampleScheduler) saveDeployments() {
+	if s.Store == nil {
+		return
+	}
+
+	for _, d := range s.deployments {
+		saved := &store.Deployment{
+			JobID:       d.jobId,
+			Version:     d.version,
+			Strategy:    d.strategy,
+			State:       d.state,
+			Started:     d.started,
+			Updated:     d.updated,
+			Cutover:     d.cutover,
+			Canary:      d.canary,
+			CanaryReady: d.canaryReady,
+			Promoted:    d.promoted,
+			Rollback:    d.rollback,
+			Failures:    d.failures,
+		}
+		if d.previous != nil {
+			previous, err := json.Marshal(d.previous)
+			if err != nil {
+				log.WithField("job_id", d.jobId).WithError(err).Errorln("Unable to save the deployment")
+				continue
+			}
+			saved.Previous = previous
+		}
+		if reflect.DeepEqual(saved, d.saved) {
+			continue
+		}
+
+		if err := s.Store.SaveDeployment(saved); err != nil {
+			log.WithField("job_id", d.jobId).WithError(err).Errorln("Unable to save the deployment")
+			continue
+		}
+		d.saved = saved
+	}
+}
+
+//Restore loads the jobs, the tasks, the deployments and the submissions
+//saved in the store by a previous run of the scheduler. The jobs given to
+//NewExampleScheduler keep their spec, the saved ones are added after them.
+//The tasks are confirmed by the reconciliation on registration, the ended
+//ones are kept for the task history, the deployments in progress go on
+//from where they were and the idempotency keys still recent are
+//remembered. It must be called before starting the driver
+func (s *ExampleScheduler) Restore() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Store == nil {
+		return nil
+	}
+
+	specs, err := s.Store.LoadJobs()
+	if err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		job := &JobSpec{}
+		if err := json.Unmarshal(spec, job); err != nil {
+			return err
+		}
+
+		jlog := log.WithField("job_id", job.ID)
+		switch err := job.Validate(); {
+		case err != nil:
+			jlog.WithError(err).Errorln("Ignoring the invalid saved job")
+		case s.job(job.ID) != nil:
+			jlog.Infoln("Ignoring the saved job, the configured one replaces it")
+		default:
+			s.jobs = append(s.jobs, job)
+			jlog.Infof("Job restored with %d instances", job.Instances)
+		}
+	}
+
+	records, err := s.Store.LoadTasks()
+	if err != nil {
+		return err
+	}
+	restored, ended, journaled := 0, 0, 0
+	for _, saved := range records {
+		state, ok := mesosproto.TaskState_value[saved.State]
+		if !ok {
+			s.deleteTask(saved.ID)
+			continue
+		}
+
+		t := s.record(saved.ID)
+		t.hostname = saved.Hostname
+		t.agentId = saved.AgentID
+		t.launched = saved.Launched
+		t.Updated = saved.Updated
+		t.StatusMessage = saved.Message
+		t.fields = saved.Fields
+		t.cpus = saved.Cpus
+		t.mem = saved.Mem
+		t.disk = saved.Disk
+		t.gpus = saved.Gpus
+		t.volume = saved.Volume
+		t.ports = saved.Ports
+		t.version = saved.Version
+		t.run = saved.Run
+		t.index = saved.Index
+		t.killed = saved.Killed
+		t.timedOut = saved.TimedOut
+		t.Start(mesosproto.TaskState(state), t.launched)
+		if tasks.IsTerminal(t.State) {
+			t.History[0].At = t.ended()
+			ended++
+		} else {
+			restored++
+		}
+
+		//A launch journaled without any status update may never have
+		//reached the master, the reconciliation tells
+		if len(saved.Offers) > 0 && !tasks.IsTerminal(t.State) {
+			t.offerIds = saved.Offers
+			t.journaled = true
+			journaled++
+			taskLog(t).WithField("offer_ids", saved.Offers).Warnln("Launch journaled but never confirmed, reconciling it")
+		}
+
+		if t.version > s.versions[t.jobId] {
+			s.versions[t.jobId] = t.version
+		}
+	}
+
+	deployments, err := s.Store.LoadDeployments()
+	if err != nil {
+		return err
+	}
+	for _, saved := range deployments {
+		if s.job(saved.JobID) == nil {
+			continue
+		}
+
+		d := &deployment{
+			jobId:       saved.JobID,
+			version:     saved.Version,
+			strategy:    saved.Strategy,
+			state:       saved.State,
+			started:     saved.Started,
+			updated:     saved.Updated,
+			cutover:     saved.Cutover,
+			canary:      saved.Canary,
+			canaryReady: saved.CanaryReady,
+			promoted:    saved.Promoted,
+			rollback:    saved.Rollback,
+			failures:    saved.Failures,
+			saved:       saved,
+		}
+		if len(saved.Previous) > 0 {
+			d.previous = &JobSpec{}
+			if err := json.Unmarshal(saved.Previous, d.previous); err != nil {
+				return err
+			}
+		}
+		s.deployments[d.jobId] = d
+
+		if d.version > s.versions[d.jobId] {
+			s.versions[d.jobId] = d.version
+		}
+	}
+
+	submissions, err := s.Store.LoadSubmissions()
+	if err != nil {
+		return err
+	}
+	for _, saved := range submissions {
+		s.submissions[saved.Key] = saved
+	}
+	s.expireSubmissions()
+
+	maintenance, err := s.Store.LoadMaintenance()
+	if err != nil {
+		return err
+	}
+	if maintenance != nil {
+		s.paused = true
+		s.pauseReason = maintenance.Reason
+		s.pausedSince = maintenance.Since
+		log.WithField("reason", maintenance.Reason).Warnln("Scheduling paused before the restart, no task is launched until resumed")
+	}
+
+	log.WithFields(log.Fields{
+		"jobs":        len(s.jobs),
+		"tasks":       restored,
+		"ended_tasks": ended,
+		"journaled":   journaled,
+		"deployments": len(s.deployments),
+		"submissions": len(s.submissions),
+		"paused":      s.paused,
+	}).Infoln("State restored")
+
+	return nil
+}
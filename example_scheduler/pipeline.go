@@ -0,0 +1,196 @@
+package example_scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//The states of a job of a pipeline, one with dependencies
+const (
+	//PipelineWaiting is a job whose dependencies didn't all succeed yet
+	PipelineWaiting = "waiting"
+
+	//PipelineReady is a job whose dependencies all succeeded, its
+	//instances are launched
+	PipelineReady = "ready"
+
+	//PipelineFailed is a job with a dependency that failed, directly or
+	//because of its own dependencies. It is never launched
+	PipelineFailed = "upstream-failed"
+)
+
+//ErrHasDependents is returned when removing a job other jobs depend on
+var ErrHasDependents = errors.New("other jobs depend on the job")
+
+//PipelineSummary describes a job of a pipeline
+type PipelineSummary struct {
+	JobID     string   `json:"job_id"`
+	DependsOn []string `json:"depends_on"`
+	State     string   `json:"state"`
+
+	//Upstream is the dependency the job waits for or that failed
+	Upstream string `json:"upstream,omitempty"`
+
+	//Succeeded is set once every instance of the job finished
+	//successfully
+	Succeeded bool `json:"succeeded"`
+}
+
+//validateDependencies checks that the dependencies of the job aren't
+//repeated nor the job itself
+func (j *JobSpec) validateDependencies() error {
+	seen := make(map[string]bool)
+	for _, id := range j.DependsOn {
+		switch {
+		case id == j.ID:
+			return fmt.Errorf("job %s can't depend on itself", id)
+		case seen[id]:
+			return fmt.Errorf("dependency %s is repeated", id)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+//checkDependencies verifies that the dependencies of the job exist and
+//don't make a cycle. The caller must hold the mutex
+func (s *ExampleScheduler) checkDependencies(job *JobSpec) error {
+	for _, id := range job.DependsOn {
+		if s.job(id) == nil {
+			return fmt.Errorf("dependency %s is not a job", id)
+		}
+	}
+
+	//Walk the dependencies, with the new spec of the job, looking for a
+	//way back to it
+	visited := make(map[string]bool)
+	var reaches func(id string) bool
+	reaches = func(id string) bool {
+		if id == job.ID {
+			return true
+		}
+		if visited[id] {
+			return false
+		}
+		visited[id] = true
+
+		dep := s.job(id)
+		if dep == nil {
+			return false
+		}
+		for _, next := range dep.DependsOn {
+			if reaches(next) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, id := range job.DependsOn {
+		if reaches(id) {
+			return fmt.Errorf("dependency %s makes a cycle", id)
+		}
+	}
+
+	return nil
+}
+
+//jobSucceeded reports if every instance of the job finished successfully
+//and none is running. The caller must hold the mutex
+func (s *ExampleScheduler) jobSucceeded(job *JobSpec) bool {
+	r, ok := s.restarts[job.ID]
+	return ok && r.succeeded >= job.Instances && len(s.activeTasks(job.ID)) == 0
+}
+
+//jobFailed reports if an instance of the job ended for good without
+//succeeding. The caller must hold the mutex
+func (s *ExampleScheduler) jobFailed(job *JobSpec) bool {
+	r, ok := s.restarts[job.ID]
+	return ok && r.done > r.succeeded
+}
+
+//dependencyState returns the state of the job in its pipeline, and the
+//dependency it waits for or that failed. The caller must hold the mutex
+func (s *ExampleScheduler) dependencyState(job *JobSpec) (string, string) {
+	waiting := ""
+	for _, id := range job.DependsOn {
+		dep := s.job(id)
+		if dep == nil {
+			continue
+		}
+		if state, _ := s.dependencyState(dep); state == PipelineFailed || s.jobFailed(dep) {
+			return PipelineFailed, id
+		}
+		if waiting == "" && !s.jobSucceeded(dep) {
+			waiting = id
+		}
+	}
+
+	if waiting != "" {
+		return PipelineWaiting, waiting
+	}
+
+	return PipelineReady, ""
+}
+
+//evaluatePipeline updates the state of the jobs with dependencies after a
+//task ended: the jobs whose dependencies all succeeded are launched, and
+//the ones downstream of a failed job are failed. The caller must hold the
+//mutex
+func (s *ExampleScheduler) evaluatePipeline() {
+	for _, job := range s.jobs {
+		if len(job.DependsOn) == 0 {
+			continue
+		}
+
+		state, upstream := s.dependencyState(job)
+		if state == s.pipeline[job.ID] {
+			continue
+		}
+		s.pipeline[job.ID] = state
+
+		jlog := log.WithFields(log.Fields{
+			"job_id":   job.ID,
+			"upstream": upstream,
+		})
+		switch state {
+		case PipelineReady:
+			jlog.Infoln("Dependencies succeeded, launching the job")
+			s.reviveIfNeeded(true)
+		case PipelineFailed:
+			jlog.Errorln("Dependency failed, the job won't be launched")
+		default:
+			jlog.Infoln("Job waiting for its dependencies")
+		}
+	}
+}
+
+//Pipeline returns the state of the jobs with dependencies, sorted by ID
+func (s *ExampleScheduler) Pipeline() []PipelineSummary {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	summaries := []PipelineSummary{}
+	for _, job := range s.jobs {
+		if len(job.DependsOn) == 0 {
+			continue
+		}
+
+		state, upstream := s.dependencyState(job)
+		summaries = append(summaries, PipelineSummary{
+			JobID:     job.ID,
+			DependsOn: append([]string{}, job.DependsOn...),
+			State:     state,
+			Upstream:  upstream,
+			Succeeded: s.jobSucceeded(job),
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].JobID < summaries[j].JobID })
+
+	return summaries
+}
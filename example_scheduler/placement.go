@@ -0,0 +1,178 @@
+package example_scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mesos/mesos-go/mesosproto"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//Placement chooses the agent where each task is launched. For every task
+//to place, the agents whose offers fit it and satisfy the constraints of
+//its job are scored and the task goes to the one with the highest score.
+//Ties go to the agent whose offers arrived first
+type Placement interface {
+	Score(c *Candidate, job *JobSpec) float64
+}
+
+//Candidate is an agent whose offers can take a task, as seen by a Placement
+type Candidate struct {
+	Hostname string
+	AgentID  string
+
+	//Offers of the agent, merged to place tasks
+	Offers []*mesosproto.Offer
+
+	//Resources of the offers not assigned to a task yet
+	Cpus float64
+	Mem  float64
+
+	//Tasks of the framework staging or running on the agent, including the
+	//ones just placed on these offers, and the resources they use
+	Tasks    int
+	UsedCpus float64
+	UsedMem  float64
+}
+
+//FirstFit places every task on the first agent that fits it, in the order
+//the offers arrived
+type FirstFit struct{}
+
+//Score is the same for every agent
+func (FirstFit) Score(c *Candidate, job *JobSpec) float64 {
+	return 0
+}
+
+//BinPacking fills first the agents that already run our tasks, highest
+//utilization first, to use as few agents as possible. Useful when idle
+//agents are scaled down
+type BinPacking struct{}
+
+//Score is the share of the resources of the agent, the ones used by our
+//tasks plus the offered ones, that our tasks would use after placing the
+//task. Between agents without our tasks the one with the least resources
+//left wins
+func (BinPacking) Score(c *Candidate, job *JobSpec) float64 {
+	cpus := (c.UsedCpus + job.Cpus) / (c.UsedCpus + c.Cpus)
+	mem := (c.UsedMem + job.Mem) / (c.UsedMem + c.Mem)
+
+	return (cpus + mem) / 2
+}
+
+//Spread prefers the least loaded agent, to spread the tasks across as many
+//agents as possible so losing one of them affects the fewest tasks
+type Spread struct{}
+
+//Score is higher the fewer tasks of ours the agent runs. Between agents
+//with the same number of tasks the one our tasks would use the smallest
+//share of wins
+func (Spread) Score(c *Candidate, job *JobSpec) float64 {
+	cpus := (c.UsedCpus + job.Cpus) / (c.UsedCpus + c.Cpus)
+	mem := (c.UsedMem + job.Mem) / (c.UsedMem + c.Mem)
+
+	return -float64(c.Tasks) + 1 - (cpus+mem)/2
+}
+
+//placements are the placements selectable by name
+var placements = map[string]Placement{
+	"first-fit":   FirstFit{},
+	"bin-packing": BinPacking{},
+	"spread":      Spread{},
+}
+
+//RegisterPlacement makes a Placement selectable by name with
+//PlacementByName, so programs embedding the scheduler can plug in their own
+//policies. It must be called before the configuration is loaded
+func RegisterPlacement(name string, p Placement) {
+	placements[name] = p
+}
+
+//PlacementByName returns the Placement registered with the name
+func PlacementByName(name string) (Placement, error) {
+	p, ok := placements[name]
+	if !ok {
+		names := make([]string, 0, len(placements))
+		for n := range placements {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("unknown placement %q, use %s", name, strings.Join(names, ", "))
+	}
+
+	return p, nil
+}
+
+//agentOffers are the offers of an agent in the pool while placing tasks
+type agentOffers struct {
+	id     string
+	held   []*heldOffer
+	offers []*mesosproto.Offer
+	tasks  []*mesosproto.TaskInfo
+
+	//The resources of the offers, by the role they are allocated to
+	res map[string]*offerResources
+
+	//Resources to reserve and persistent volumes to create before
+	//launching the tasks
+	reserve []*mesosproto.Resource
+	create  []*mesosproto.Resource
+
+	//The task groups of the pods placed on the agent
+	groups []*mesosproto.Offer_Operation_LaunchGroup
+}
+
+//selectAgent returns the agent where a task of the job is placed, or nil if
+//no agent can take it. The caller must hold the mutex
+func (s *ExampleScheduler) selectAgent(agents []*agentOffers, job *JobSpec) *agentOffers {
+	placement := s.Placement
+	if placement == nil {
+		placement = FirstFit{}
+	}
+
+	var best *agentOffers
+	var bestScore float64
+	for _, agent := range agents {
+		if !s.resOf(agent, job).fits(job) || !s.fitsVolume(agent, job) || !s.acceptsOffer(job, agent.offers[0]) || !s.acceptsAffinity(job, agent.offers[0]) {
+			continue
+		}
+
+		score := placement.Score(s.candidate(agent), job)
+		if best == nil || score > bestScore {
+			best, bestScore = agent, score
+		}
+	}
+
+	return best
+}
+
+//candidate describes the agent to the Placement. The caller must hold the
+//mutex
+func (s *ExampleScheduler) candidate(agent *agentOffers) *Candidate {
+	c := &Candidate{
+		Hostname: agent.offers[0].GetHostname(),
+		AgentID:  agent.id,
+		Offers:   agent.offers,
+		Cpus:     agent.scalar("cpus"),
+		Mem:      agent.scalar("mem"),
+	}
+
+	for _, t := range s.tasks {
+		if t.agentId != agent.id || tasks.IsTerminal(t.State) {
+			continue
+		}
+
+		c.Tasks++
+		cpus, mem := t.cpus, t.mem
+		if job := s.job(t.jobId); job != nil && cpus == 0 {
+			//Found by a reconciliation, assume the current spec
+			cpus, mem = job.Cpus, job.Mem
+		}
+		c.UsedCpus += cpus
+		c.UsedMem += mem
+	}
+
+	return c
+}
@@ -0,0 +1,229 @@
+package example_scheduler
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//ContainerSpec is a task of a pod, a job whose instances are groups of
+//tasks launched together on the same agent, sharing the default executor
+//of Mesos. The first container is the main one, the rest are its sidecars,
+//like a log shipper
+type ContainerSpec struct {
+	//Name of the container, unique in the job. It suffixes the task ID of
+	//the sidecars
+	Name string `json:"name"`
+
+	//Docker image the container runs, with the Mesos containerizer. Empty
+	//to run the command on the agent
+	Image string `json:"image,omitempty"`
+
+	//Command to run, like the one of a job
+	Command string            `json:"cmd,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+
+	//The resources of the container alone
+	Cpus float64 `json:"cpus"`
+	Mem  float64 `json:"mem"`
+	Disk float64 `json:"disk,omitempty"`
+}
+
+//containerNameRegexp matches the names that can be part of a task ID
+var containerNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+//validateContainers checks the containers of a pod and that the job
+//doesn't use what pods don't support
+func (j *JobSpec) validateContainers() error {
+	if len(j.Containers) == 0 {
+		return nil
+	}
+
+	switch {
+	case j.Image != "" || j.Command != "" || len(j.Args) > 0:
+		return errors.New("the image and the command of a pod go in its containers")
+	case j.Gpus > 0:
+		return errors.New("pods don't support gpus")
+	case j.Volume != nil:
+		return errors.New("pods don't support persistent volumes")
+	}
+
+	names := make(map[string]bool)
+	for _, c := range j.Containers {
+		switch {
+		case !containerNameRegexp.MatchString(c.Name):
+			return fmt.Errorf("invalid container name %q, use letters, digits, - and _", c.Name)
+		case names[c.Name]:
+			return fmt.Errorf("container %s is repeated", c.Name)
+		case c.Image == "" && c.Command == "":
+			return fmt.Errorf("container %s needs an image or a command", c.Name)
+		case c.Cpus <= 0 || c.Mem <= 0:
+			return fmt.Errorf("cpus and mem of container %s must be greater than 0", c.Name)
+		case c.Disk < 0:
+			return fmt.Errorf("disk of container %s can't be negative", c.Name)
+		}
+		names[c.Name] = true
+	}
+
+	return nil
+}
+
+//needs returns the amount of a scalar resource each instance of the job
+//takes: the one of the job or, for a pod, the one of its executor plus the
+//ones of its containers
+func (j *JobSpec) needs(name string) float64 {
+	var value float64
+	switch name {
+	case "cpus":
+		value = j.Cpus
+	case "mem":
+		value = j.Mem
+	case "disk":
+		value = j.Disk
+	case "gpus":
+		value = j.Gpus
+	}
+
+	for _, c := range j.Containers {
+		switch name {
+		case "cpus":
+			value += c.Cpus
+		case "mem":
+			value += c.Mem
+		case "disk":
+			value += c.Disk
+		}
+	}
+
+	return value
+}
+
+//isSidecar reports if the task is a sidecar of a pod. Their IDs have the
+//name of the container after the ID of the main task
+func isSidecar(taskId string) bool {
+	return strings.Count(taskId, taskJobSeparator) > 1
+}
+
+//newPod builds the task group of a new instance of a pod, taking its
+//resources from the offers of the agent, and adds it to the groups the
+//agent launches. The ports go to the main task, and the index, if not -1,
+//to every task. It returns the main task.
+//The caller must hold the mutex
+func (s *ExampleScheduler) newPod(job *JobSpec, agent *agentOffers, index int) *mesosproto.TaskInfo {
+	offer := agent.offers[0]
+	res := s.resOf(agent, job)
+	groupId := job.newTaskID()
+
+	//The executor of the group, with the resources of the job itself
+	executor := &mesosproto.ExecutorInfo{
+		Type:        mesosproto.ExecutorInfo_DEFAULT.Enum(),
+		ExecutorId:  &mesosproto.ExecutorID{Value: proto.String(groupId)},
+		FrameworkId: offer.FrameworkId,
+		Resources:   s.takeScalars(agent, res, job, job.Cpus, job.Mem, job.Disk),
+	}
+
+	ports, portResources := res.takePorts(job)
+	group := &mesosproto.TaskGroupInfo{}
+	for i, c := range job.Containers {
+		taskId := groupId
+		resources := s.takeScalars(agent, res, job, c.Cpus, c.Mem, c.Disk)
+		if i == 0 {
+			resources = append(resources, portResources...)
+		} else {
+			taskId += taskJobSeparator + c.Name
+		}
+
+		//The ports and the env of the container override the env of the
+		//job
+		env := job.portsEnv(ports)
+		addIndexEnv(env, index)
+		for name, value := range c.Env {
+			env[name] = value
+		}
+		spec := &JobSpec{Command: c.Command, Args: c.Args, Env: job.Env}
+
+		name := "go-task-" + taskId
+		task := &mesosproto.TaskInfo{
+			Name:       proto.String(name),
+			TaskId:     &mesosproto.TaskID{Value: proto.String(taskId)},
+			SlaveId:    offer.SlaveId,
+			Resources:  resources,
+			Command:    spec.commandInfo(env),
+			KillPolicy: job.killPolicy(),
+		}
+		if i == 0 {
+			task.Discovery = job.portsDiscovery(name, ports)
+			task.HealthCheck = job.healthCheck(ports)
+		}
+		if c.Image != "" {
+			task.Container = &mesosproto.ContainerInfo{
+				Type: mesosproto.ContainerInfo_MESOS.Enum(),
+				Mesos: &mesosproto.ContainerInfo_MesosInfo{
+					Image: &mesosproto.Image{
+						Type:   mesosproto.Image_DOCKER.Enum(),
+						Docker: &mesosproto.Image_Docker{Name: proto.String(c.Image)},
+					},
+				},
+			}
+		}
+
+		group.Tasks = append(group.Tasks, task)
+	}
+
+	agent.groups = append(agent.groups, &mesosproto.Offer_Operation_LaunchGroup{
+		Executor:  executor,
+		TaskGroup: group,
+	})
+
+	return group.Tasks[0]
+}
+
+//takeScalars takes cpus, mem and disk from the offers of the agent,
+//adding to it the resources to reserve. It returns the resources taken.
+//The caller must hold the mutex
+func (s *ExampleScheduler) takeScalars(agent *agentOffers, res *offerResources, job *JobSpec, cpus, mem, disk float64) []*mesosproto.Resource {
+	var t taken
+	t.scalars = append(t.scalars, res.takeScalar("cpus", cpus, job.Revocable)...)
+	t.scalars = append(t.scalars, res.takeScalar("mem", mem, job.Revocable)...)
+	t.scalars = append(t.scalars, res.takeScalar("disk", disk, job.Revocable)...)
+
+	resources, reserve := s.scalarResources(t)
+	agent.reserve = append(agent.reserve, reserve...)
+
+	return resources
+}
+
+//launchOperations returns the operations of the Accept call that launch
+//the tasks placed on the agent: the tasks that aren't in a pod together,
+//and each pod on its own
+func (a *agentOffers) launchOperations() []*mesosproto.Offer_Operation {
+	grouped := make(map[string]bool)
+	for _, group := range a.groups {
+		grouped[group.TaskGroup.Tasks[0].TaskId.GetValue()] = true
+	}
+
+	var tasks []*mesosproto.TaskInfo
+	for _, task := range a.tasks {
+		if !grouped[task.TaskId.GetValue()] {
+			tasks = append(tasks, task)
+		}
+	}
+
+	var operations []*mesosproto.Offer_Operation
+	if len(tasks) > 0 {
+		operations = append(operations, launchOperation(tasks))
+	}
+	for _, group := range a.groups {
+		operations = append(operations, &mesosproto.Offer_Operation{
+			Type:        mesosproto.Offer_Operation_LAUNCH_GROUP.Enum(),
+			LaunchGroup: group,
+		})
+	}
+
+	return operations
+}
@@ -0,0 +1,249 @@
+package example_scheduler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mesos/mesos-go/mesosproto"
+	"github.com/mesos/mesos-go/mesosutil"
+)
+
+//PortSpec is a host port every task of a job needs
+type PortSpec struct {
+	//Name of the port, like http, admin or metrics. It names the
+	//environment variable of the port and its entry in the DiscoveryInfo
+	Name string `json:"name,omitempty"`
+
+	//Fixed port number, 0 to take any port offered
+	Port uint64 `json:"port,omitempty"`
+}
+
+//defaultPorts are the ports of the jobs that don't ask for any: a single
+//port taken from the offers
+var defaultPorts = []PortSpec{{}}
+
+//portNameRegexp matches the names that can be part of an environment
+//variable name
+var portNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+//portSpecs returns the ports each task of the job needs
+func (j *JobSpec) portSpecs() []PortSpec {
+	if len(j.Ports) == 0 {
+		return defaultPorts
+	}
+
+	return j.Ports
+}
+
+//validatePorts checks that the names and the fixed numbers of the ports are
+//valid and not repeated
+func (j *JobSpec) validatePorts() error {
+	names := make(map[string]bool)
+	numbers := make(map[uint64]bool)
+
+	for _, p := range j.Ports {
+		if p.Name != "" {
+			if !portNameRegexp.MatchString(p.Name) {
+				return fmt.Errorf("invalid port name %q, use letters, digits, - and _", p.Name)
+			}
+			if names[portEnvName(p.Name)] {
+				return fmt.Errorf("port %s is repeated", p.Name)
+			}
+			names[portEnvName(p.Name)] = true
+		}
+
+		if p.Port > 65535 {
+			return fmt.Errorf("invalid port number %d", p.Port)
+		}
+		if p.Port > 0 {
+			if numbers[p.Port] {
+				return fmt.Errorf("port %d is repeated", p.Port)
+			}
+			numbers[p.Port] = true
+		}
+	}
+
+	return nil
+}
+
+//portEnvName is the name of the environment variable of a named port
+func portEnvName(name string) string {
+	return "PORT_" + strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
+
+//hasPort reports if the port is in the remaining ports
+func (r *offerResources) hasPort(port uint64) bool {
+	for _, p := range r.ports {
+		if p.GetBegin() <= port && port <= p.GetEnd() {
+			return true
+		}
+	}
+
+	return false
+}
+
+//fitsPorts reports if the remaining ports have the fixed ports of the job
+//and enough others for its dynamic ones
+func (r *offerResources) fitsPorts(job *JobSpec) bool {
+	var fixed, dynamic uint64
+	for _, p := range job.portSpecs() {
+		if p.Port == 0 {
+			dynamic++
+			continue
+		}
+		if !r.hasPort(p.Port) {
+			return false
+		}
+		fixed++
+	}
+
+	return r.portCount() >= fixed+dynamic
+}
+
+//missingPorts returns the fixed ports of the jobs with pending instances
+//that the offers of the agent don't have, if every job misses some. It
+//returns nil if any job could still fit, waiting for more offers. The
+//caller must hold the mutex
+func (s *ExampleScheduler) missingPorts(agent *agentOffers, planned map[string]int) []uint64 {
+	var missing []uint64
+
+	for _, job := range s.jobs {
+		if s.inBackoff(job) || s.pendingInstances(job) <= planned[job.ID] {
+			continue
+		}
+
+		var jobMissing []uint64
+		for _, p := range job.portSpecs() {
+			if p.Port > 0 && !s.resOf(agent, job).hasPort(p.Port) {
+				jobMissing = append(jobMissing, p.Port)
+			}
+		}
+		if len(jobMissing) == 0 {
+			return nil
+		}
+
+		missing = append(missing, jobMissing...)
+	}
+
+	return missing
+}
+
+//takePort removes a port from the remaining ports, splitting its range. It
+//returns the resource the port was offered in
+func (r *offerResources) takePort(port uint64) *mesosproto.Resource {
+	for i, p := range r.ports {
+		if port < p.GetBegin() || port > p.GetEnd() {
+			continue
+		}
+
+		var split []*portRange
+		if port > p.GetBegin() {
+			split = append(split, &portRange{
+				Value_Range: &mesosproto.Value_Range{Begin: p.Begin, End: proto.Uint64(port - 1)},
+				template:    p.template,
+			})
+		}
+		if port < p.GetEnd() {
+			split = append(split, &portRange{
+				Value_Range: &mesosproto.Value_Range{Begin: proto.Uint64(port + 1), End: p.End},
+				template:    p.template,
+			})
+		}
+
+		r.ports = append(r.ports[:i], append(split, r.ports[i+1:]...)...)
+		return p.template
+	}
+
+	return nil
+}
+
+//takePorts takes the ports of a task of the job, which must fit, in the
+//order of its specs. The fixed ports are taken first so the dynamic ones
+//don't use them. It returns the ports and their resources, one for each
+//role and reservation they were offered with
+func (r *offerResources) takePorts(job *JobSpec) ([]uint64, []*mesosproto.Resource) {
+	specs := job.portSpecs()
+	ports := make([]uint64, len(specs))
+	templates := make([]*mesosproto.Resource, len(specs))
+
+	for i, p := range specs {
+		if p.Port > 0 {
+			templates[i] = r.takePort(p.Port)
+			ports[i] = p.Port
+		}
+	}
+
+	for i, p := range specs {
+		if p.Port == 0 {
+			ports[i] = r.ports[0].GetBegin()
+			templates[i] = r.takePort(ports[i])
+		}
+	}
+
+	var resources []*mesosproto.Resource
+	for i, port := range ports {
+		var resource *mesosproto.Resource
+		for _, res := range resources {
+			if sameKind(res, templates[i]) {
+				resource = res
+				break
+			}
+		}
+		if resource == nil {
+			resource = proto.Clone(templates[i]).(*mesosproto.Resource)
+			resource.Ranges = &mesosproto.Value_Ranges{}
+			resources = append(resources, resource)
+		}
+
+		resource.Ranges.Range = append(resource.Ranges.Range, mesosutil.NewValueRange(port, port))
+	}
+
+	return ports, resources
+}
+
+//portsDiscovery describes the ports of a task, with their names, so the
+//executor and the service discovery tools know which is which
+func (j *JobSpec) portsDiscovery(taskName string, ports []uint64) *mesosproto.DiscoveryInfo {
+	discovery := &mesosproto.DiscoveryInfo{
+		Visibility: mesosproto.DiscoveryInfo_FRAMEWORK.Enum(),
+		Name:       proto.String(taskName),
+		Ports:      &mesosproto.Ports{},
+	}
+
+	for i, p := range j.portSpecs() {
+		port := &mesosproto.Port{
+			Number:   proto.Uint32(uint32(ports[i])),
+			Protocol: proto.String("tcp"),
+		}
+		if p.Name != "" {
+			port.Name = proto.String(p.Name)
+		}
+		discovery.Ports.Ports = append(discovery.Ports.Ports, port)
+	}
+
+	return discovery
+}
+
+//portsEnv returns the environment variables with the ports of a task:
+//PORT0, PORT1... in the order of the specs, PORT for the first one and
+//PORT_<NAME> for the named ones
+func (j *JobSpec) portsEnv(ports []uint64) map[string]string {
+	env := make(map[string]string)
+
+	for i, p := range j.portSpecs() {
+		value := strconv.FormatUint(ports[i], 10)
+
+		env["PORT"+strconv.Itoa(i)] = value
+		if i == 0 {
+			env["PORT"] = value
+		}
+		if p.Name != "" {
+			env[portEnvName(p.Name)] = value
+		}
+	}
+
+	return env
+}
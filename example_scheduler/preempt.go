@@ -0,0 +1,173 @@
+package example_scheduler
+
+import (
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//defaultPreemptionGrace is how long the instances of a job wait for
+//resources before lower priority tasks are preempted for them, when
+//PreemptionGrace isn't set
+const defaultPreemptionGrace = time.Minute
+
+//preemptionGrace returns how long the instances of a job wait for
+//resources before lower priority tasks are preempted for them
+func (s *ExampleScheduler) preemptionGrace() time.Duration {
+	if s.PreemptionGrace > 0 {
+		return s.PreemptionGrace
+	}
+
+	return defaultPreemptionGrace
+}
+
+//byPriority returns the jobs grouped by priority, the highest first, each
+//group in submission order. The caller must hold the mutex
+func (s *ExampleScheduler) byPriority() [][]*JobSpec {
+	jobs := append([]*JobSpec(nil), s.jobs...)
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].Priority > jobs[j].Priority })
+
+	var classes [][]*JobSpec
+	for i, job := range jobs {
+		if i == 0 || job.Priority != jobs[i-1].Priority {
+			classes = append(classes, nil)
+		}
+		classes[len(classes)-1] = append(classes[len(classes)-1], job)
+	}
+
+	return classes
+}
+
+//canRunBeside reports if a task of the job could run on the agent of the
+//task t: an agent allowed by the host filter, not lost and matching the
+//constraints of the job. The caller must hold the mutex
+func (s *ExampleScheduler) canRunBeside(job *JobSpec, t *taskRecord) bool {
+	if !s.hosts.allows(t.hostname) {
+		return false
+	}
+	if _, lost := s.isLost(t.agentId, t.hostname); lost {
+		return false
+	}
+
+	active := s.activeTasks(job.ID)
+	for _, c := range job.Constraints {
+		if !c.accepts(t.fields, active) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//preemptionVictims chooses the tasks to kill to make room for a task of
+//the job: tasks of lower priority jobs of the same role, all on one agent,
+//the lowest priority and the newest first, until their resources add up
+//to what the task needs. The agent needing the fewest victims is chosen.
+//The tasks already taken aren't chosen again. It returns nil if no agent
+//has enough. The caller must hold the mutex
+func (s *ExampleScheduler) preemptionVictims(job *JobSpec, taken map[string]bool) []*taskRecord {
+	byAgent := make(map[string][]*taskRecord)
+	for _, t := range s.tasks {
+		victimJob := s.job(t.jobId)
+		switch {
+		case victimJob == nil || victimJob.Priority >= job.Priority:
+			continue
+		case taken[t.id] || t.killed || t.replaced || t.sidecar || tasks.IsTerminal(t.State):
+			continue
+		case t.cpus == 0 && t.mem == 0:
+			//Not launched by this scheduler instance, its resources are
+			//unknown
+			continue
+		case s.roleOf(victimJob) != s.roleOf(job) || !s.canRunBeside(job, t):
+			continue
+		}
+
+		byAgent[t.agentId] = append(byAgent[t.agentId], t)
+	}
+
+	agentIds := make([]string, 0, len(byAgent))
+	for agentId := range byAgent {
+		agentIds = append(agentIds, agentId)
+	}
+	sort.Strings(agentIds)
+
+	var best []*taskRecord
+	for _, agentId := range agentIds {
+		candidates := byAgent[agentId]
+		sort.Slice(candidates, func(i, j int) bool {
+			pi, pj := s.job(candidates[i].jobId).Priority, s.job(candidates[j].jobId).Priority
+			if pi != pj {
+				return pi < pj
+			}
+			return candidates[i].launched.After(candidates[j].launched)
+		})
+
+		var cpus, mem, disk, gpus float64
+		for i, t := range candidates {
+			cpus, mem, disk, gpus = cpus+t.cpus, mem+t.mem, disk+t.disk, gpus+t.gpus
+			if cpus < job.needs("cpus") || mem < job.needs("mem") || disk < job.needs("disk") || gpus < job.needs("gpus") {
+				continue
+			}
+			if best == nil || i+1 < len(best) {
+				best = candidates[:i+1]
+			}
+			break
+		}
+	}
+
+	return best
+}
+
+//preemptTasks kills tasks of lower priority jobs for the jobs whose
+//instances waited longer than the preemption grace period for resources,
+//the highest priority ones first. The victims get the kill grace period of
+//their job to shut down, and are launched again once there is room for
+//them. The caller must hold the mutex
+func (s *ExampleScheduler) preemptTasks() {
+	taken := make(map[string]bool)
+
+	for _, class := range s.byPriority() {
+		for _, job := range class {
+			pending := s.pendingInstances(job)
+			if pending == 0 || s.inBackoff(job) {
+				delete(s.starving, job.ID)
+				continue
+			}
+
+			since, ok := s.starving[job.ID]
+			if !ok {
+				s.starving[job.ID] = time.Now()
+				continue
+			}
+			if time.Since(since) < s.preemptionGrace() {
+				continue
+			}
+
+			jlog := log.WithFields(log.Fields{
+				"job_id":   job.ID,
+				"priority": job.Priority,
+			})
+			for i := 0; i < pending; i++ {
+				victims := s.preemptionVictims(job, taken)
+				if victims == nil {
+					jlog.Debugln("No lower priority tasks to preempt for the pending instances")
+					break
+				}
+
+				for _, t := range victims {
+					taken[t.id] = true
+					taskLog(t).WithField("preempted_by", job.ID).Warnln("Preempting task for a higher priority job")
+					if err := s.kill(t, "preempted by the higher priority job "+job.ID); err != nil {
+						taskLog(t).WithError(err).Errorln("Unable to kill the task")
+					}
+				}
+			}
+
+			//The resources freed take a while to be offered, the job
+			//waits another grace period before preempting more
+			s.starving[job.ID] = time.Now()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package example_scheduler
+
+import (
+	"errors"
+	"fmt"
+
+	"minimal-mesos-go-framework/store"
+)
+
+//Healthy returns why the scheduler process is broken, nil if it isn't: it
+//is disconnected from the master, or its driver was aborted. A supervisor
+//restarts the process when it stays unhealthy
+func (s *ExampleScheduler) Healthy() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case s.aborted != "":
+		return fmt.Errorf("the driver was aborted: %s", s.aborted)
+	case s.disconnected:
+		return errors.New("disconnected from the master")
+	}
+
+	return nil
+}
+
+//Ready returns why the scheduler can't manage the jobs, nil if it can: it
+//isn't registered with the master yet, or its state store can't be reached
+func (s *ExampleScheduler) Ready() error {
+	if err := s.Healthy(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	registered := s.driver != nil
+	s.mutex.Unlock()
+	if !registered {
+		return ErrNotRegistered
+	}
+
+	//The store is reached without the mutex, it may take a while
+	if pinger, ok := s.Store.(store.Pinger); ok {
+		if err := pinger.Ping(); err != nil {
+			return fmt.Errorf("the state store can't be reached: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package example_scheduler
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//ErrQueueFull is returned when adding instances to a job would queue more
+//launches than MaxQueued
+var ErrQueueFull = errors.New("the launch queue is full")
+
+//queuedLaunch is an instance of a job waiting in the launch queue for
+//offers that fit it
+type queuedLaunch struct {
+	jobId    string
+	enqueued time.Time
+
+	//The task placed for the instance while its offers are accepted, empty
+	//while it waits
+	taskId string
+
+	//The launches of the instance that failed, each one requeued it
+	attempts int
+}
+
+//queueStats are the totals of the instances that left the queue launched
+type queueStats struct {
+	launched  int
+	totalWait time.Duration
+	maxWait   time.Duration
+}
+
+//QueuedLaunch describes an instance waiting in the launch queue
+type QueuedLaunch struct {
+	JobID    string    `json:"job_id"`
+	Priority int       `json:"priority"`
+	Enqueued time.Time `json:"enqueued"`
+
+	//Wait is the seconds the instance has waited so far
+	Wait float64 `json:"wait"`
+
+	//Attempts are the launches of the instance that failed
+	Attempts int `json:"attempts"`
+
+	//Backoff is set while the restart policy of the job delays it
+	Backoff bool `json:"backoff,omitempty"`
+}
+
+//QueueSummary describes the launch queue: the instances waiting, in the
+//order they get the offers, and the wait of the ones launched, in seconds
+type QueueSummary struct {
+	Depth    int            `json:"depth"`
+	Launches []QueuedLaunch `json:"launches"`
+
+	//The oldest wait of the instances in the queue
+	OldestWait float64 `json:"oldest_wait"`
+
+	//The instances launched from the queue and their mean and longest wait
+	Launched int     `json:"launched"`
+	MeanWait float64 `json:"mean_wait"`
+	MaxWait  float64 `json:"max_wait"`
+}
+
+//syncQueue queues the instances pending to launch of every job and drops
+//the ones not pending anymore, the newest first. The instances keep their
+//place in the queue while they wait. The caller must hold the mutex
+func (s *ExampleScheduler) syncQueue() {
+	want := make(map[string]int)
+	for _, job := range s.jobs {
+		want[job.ID] = s.pendingInstances(job)
+	}
+
+	queued := make(map[string]int)
+	kept := s.queue[:0]
+	for _, q := range s.queue {
+		if q.taskId == "" && queued[q.jobId] >= want[q.jobId] {
+			continue
+		}
+		if q.taskId == "" {
+			queued[q.jobId]++
+		}
+		kept = append(kept, q)
+	}
+	s.queue = kept
+
+	now := time.Now()
+	for _, job := range s.jobs {
+		for i := queued[job.ID]; i < want[job.ID]; i++ {
+			s.queue = append(s.queue, &queuedLaunch{jobId: job.ID, enqueued: now})
+		}
+	}
+}
+
+//queueOrder returns the instances waiting in the launch queue in the order
+//they get the offers: the jobs of the highest priority first and, among
+//jobs of the same priority, the first instance of each job in the order
+//they were queued, then the second one of each, so a job with many
+//instances queued doesn't hold back the others. The caller must hold the
+//mutex
+func (s *ExampleScheduler) queueOrder() []*queuedLaunch {
+	var waiting []*queuedLaunch
+	rank := make(map[*queuedLaunch]int)
+	seen := make(map[string]int)
+	for _, q := range s.queue {
+		if q.taskId == "" && s.job(q.jobId) != nil {
+			waiting = append(waiting, q)
+			rank[q] = seen[q.jobId]
+			seen[q.jobId]++
+		}
+	}
+
+	sort.SliceStable(waiting, func(i, j int) bool {
+		pi, pj := s.job(waiting[i].jobId).Priority, s.job(waiting[j].jobId).Priority
+		if pi != pj {
+			return pi > pj
+		}
+		return rank[waiting[i]] < rank[waiting[j]]
+	})
+
+	return waiting
+}
+
+//dequeue removes from the queue the instances whose tasks were launched,
+//adding their wait to the stats. The caller must hold the mutex
+func (s *ExampleScheduler) dequeue(tasks []*mesosproto.TaskInfo) {
+	launched := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		launched[task.TaskId.GetValue()] = true
+	}
+
+	kept := s.queue[:0]
+	for _, q := range s.queue {
+		if q.taskId == "" || !launched[q.taskId] {
+			kept = append(kept, q)
+			continue
+		}
+
+		wait := time.Since(q.enqueued)
+		s.queueStats.launched++
+		s.queueStats.totalWait += wait
+		if wait > s.queueStats.maxWait {
+			s.queueStats.maxWait = wait
+		}
+	}
+	s.queue = kept
+}
+
+//requeue puts back to wait, in their place, the instances of the tasks that
+//weren't launched. A failed launch counts as an attempt. The caller must
+//hold the mutex
+func (s *ExampleScheduler) requeue(tasks []*mesosproto.TaskInfo, failed bool) {
+	placed := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		placed[task.TaskId.GetValue()] = true
+	}
+
+	for _, q := range s.queue {
+		if q.taskId != "" && placed[q.taskId] {
+			q.taskId = ""
+			if failed {
+				q.attempts++
+			}
+		}
+	}
+}
+
+//checkQueue verifies that extra more instances fit in the launch queue. The
+//caller must hold the mutex
+func (s *ExampleScheduler) checkQueue(extra int) error {
+	if s.MaxQueued <= 0 || extra <= 0 {
+		return nil
+	}
+
+	s.syncQueue()
+	if len(s.queue)+extra > s.MaxQueued {
+		return ErrQueueFull
+	}
+
+	return nil
+}
+
+//Queue returns the instances waiting in the launch queue and the wait of
+//the ones launched
+func (s *ExampleScheduler) Queue() QueueSummary {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.syncQueue()
+	summary := QueueSummary{
+		Depth:    len(s.queue),
+		Launches: []QueuedLaunch{},
+		Launched: s.queueStats.launched,
+		MaxWait:  s.queueStats.maxWait.Seconds(),
+	}
+	if s.queueStats.launched > 0 {
+		summary.MeanWait = s.queueStats.totalWait.Seconds() / float64(s.queueStats.launched)
+	}
+
+	for _, q := range s.queueOrder() {
+		job := s.job(q.jobId)
+		wait := time.Since(q.enqueued).Seconds()
+		if wait > summary.OldestWait {
+			summary.OldestWait = wait
+		}
+
+		summary.Launches = append(summary.Launches, QueuedLaunch{
+			JobID:    q.jobId,
+			Priority: job.Priority,
+			Enqueued: q.enqueued,
+			Wait:     wait,
+			Attempts: q.attempts,
+			Backoff:  s.inBackoff(job),
+		})
+	}
+
+	return summary
+}
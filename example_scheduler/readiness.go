@@ -0,0 +1,250 @@
+package example_scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//The protocols of a readiness check
+const (
+	ReadinessHTTP    = "http"
+	ReadinessCommand = "command"
+)
+
+//Defaults of the ReadinessCheckSpec fields left empty
+const (
+	defaultReadinessInterval = 5 * time.Second
+	defaultReadinessTimeout  = 5 * time.Second
+)
+
+//ReadinessCheckSpec is a check the scheduler runs on each running task of
+//the job until it passes, like a service loading its data before taking
+//traffic. Unlike a health check it never kills the task, but the task
+//isn't ready, so it doesn't count as an available instance, until it
+//passes
+type ReadinessCheckSpec struct {
+	//Protocol is http or command
+	Protocol string `json:"protocol"`
+
+	//Path requested with http on the agent of the task, ready on a 2xx
+	//response
+	Path string `json:"path,omitempty"`
+
+	//Name of the port requested with http. Empty is the first port of the
+	//task
+	Port string `json:"port,omitempty"`
+
+	//Command run by the scheduler, ready if it exits with 0. It gets the
+	//task in TASK_ID, TASK_HOST and TASK_PORT
+	Command string `json:"cmd,omitempty"`
+
+	//Seconds between the checks of a task and to wait for each one. 0 is
+	//5 seconds
+	Interval float64 `json:"interval,omitempty"`
+	Timeout  float64 `json:"timeout,omitempty"`
+}
+
+//validate checks the readiness check against the job it belongs to
+func (r *ReadinessCheckSpec) validate(job *JobSpec) error {
+	switch r.Protocol {
+	case ReadinessHTTP:
+		if r.Port != "" && r.portIndex(job) < 0 {
+			return fmt.Errorf("the readiness check port %s isn't a port of the job", r.Port)
+		}
+	case ReadinessCommand:
+		if r.Command == "" {
+			return errors.New("a command readiness check needs a command")
+		}
+	default:
+		return fmt.Errorf("unknown readiness check protocol %q, use %s or %s", r.Protocol, ReadinessHTTP, ReadinessCommand)
+	}
+
+	if r.Interval < 0 || r.Timeout < 0 {
+		return errors.New("readiness check times can't be negative")
+	}
+
+	return nil
+}
+
+//checkCommandReadiness refuses the command readiness checks unless
+//AllowCommandReadiness is set: anyone submitting the job would run any
+//command on the scheduler host. The caller must hold the mutex
+func (s *ExampleScheduler) checkCommandReadiness(job *JobSpec) error {
+	if job.Readiness != nil && job.Readiness.Protocol == ReadinessCommand && !s.AllowCommandReadiness {
+		return errors.New("command readiness checks aren't allowed, they need --allow-command-readiness")
+	}
+
+	return nil
+}
+
+//portIndex returns the index of the checked port in the ports of the job,
+//-1 if it isn't one of them
+func (r *ReadinessCheckSpec) portIndex(job *JobSpec) int {
+	if r.Port == "" {
+		return 0
+	}
+
+	for i, p := range job.portSpecs() {
+		if p.Name == r.Port {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (r *ReadinessCheckSpec) interval() time.Duration {
+	if r.Interval > 0 {
+		return time.Duration(r.Interval * float64(time.Second))
+	}
+
+	return defaultReadinessInterval
+}
+
+func (r *ReadinessCheckSpec) timeout() time.Duration {
+	if r.Timeout > 0 {
+		return time.Duration(r.Timeout * float64(time.Second))
+	}
+
+	return defaultReadinessTimeout
+}
+
+//probe runs the check once against a task listening on host:port. The
+//command only gets the PATH of the scheduler, not its settings and secrets
+func (r *ReadinessCheckSpec) probe(taskId, host string, port uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout())
+	defer cancel()
+
+	if r.Protocol == ReadinessCommand {
+		cmd := exec.CommandContext(ctx, "sh", "-c", r.Command)
+		cmd.Env = []string{
+			"PATH=" + os.Getenv("PATH"),
+			"TASK_ID=" + taskId,
+			"TASK_HOST=" + host,
+			"TASK_PORT=" + strconv.FormatUint(port, 10),
+		}
+		return cmd.Run()
+	}
+
+	url := "http://" + net.JoinHostPort(host, strconv.FormatUint(port, 10)) + r.Path
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+
+	return nil
+}
+
+//isReady reports if the task is running and, if its job has a readiness
+//check, passed it. The caller must hold the mutex
+func (s *ExampleScheduler) isReady(t *taskRecord) bool {
+	if t.State != mesosproto.TaskState_TASK_RUNNING {
+		return false
+	}
+
+	job := s.job(t.jobId)
+	return job == nil || job.Readiness == nil || t.ready
+}
+
+//readyInstances is the number of instances of the job that are ready. The
+//caller must hold the mutex
+func (s *ExampleScheduler) readyInstances(job *JobSpec) int {
+	var ready int
+	for _, t := range s.activeTasks(job.ID) {
+		if !t.killed && s.isReady(t) {
+			ready++
+		}
+	}
+
+	return ready
+}
+
+//checkReadiness starts the readiness checks due of the running tasks that
+//aren't ready yet. Each check runs in its own goroutine, without the
+//mutex. The caller must hold the mutex
+func (s *ExampleScheduler) checkReadiness() {
+	for _, t := range s.tasks {
+		if t.ready || t.checkingReadiness || t.killed ||
+			t.State != mesosproto.TaskState_TASK_RUNNING {
+			continue
+		}
+
+		job := s.job(t.jobId)
+		if job == nil || job.Readiness == nil || time.Since(t.readinessChecked) < job.Readiness.interval() {
+			continue
+		}
+
+		var port uint64
+		if i := job.Readiness.portIndex(job); i >= 0 && i < len(t.ports) {
+			port = t.ports[i]
+		}
+		//The tasks launched before a restart of the scheduler were likely
+		//ready long ago, but their ports are unknown
+		if port == 0 && job.Readiness.Protocol == ReadinessHTTP {
+			t.ready = true
+			taskLog(t).Infoln("The port of the task is unknown, assuming it is ready")
+			continue
+		}
+
+		t.checkingReadiness = true
+		go s.runReadinessCheck(t, *job.Readiness, port)
+	}
+}
+
+//runReadinessCheck runs the readiness check of a task and records the
+//result
+func (s *ExampleScheduler) runReadinessCheck(t *taskRecord, check ReadinessCheckSpec, port uint64) {
+	err := check.probe(t.id, t.hostname, port)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	t.checkingReadiness = false
+	t.readinessChecked = time.Now()
+
+	tlog := taskLog(t)
+	if err != nil {
+		tlog.WithError(err).Debugln("Task not ready yet")
+		return
+	}
+	if t.State != mesosproto.TaskState_TASK_RUNNING {
+		return
+	}
+
+	t.ready = true
+	tlog.Infoln("Task ready")
+
+	if job := s.job(t.jobId); job != nil && s.readyInstances(job) == job.Instances {
+		log.WithField("job_id", job.ID).Infof("All the %d instances of the job are ready", job.Instances)
+	}
+}
+
+//portsOfTask returns the host ports of a task, in the order of the ports
+//of its job
+func portsOfTask(task *mesosproto.TaskInfo) []uint64 {
+	var ports []uint64
+	for _, p := range task.GetDiscovery().GetPorts().GetPorts() {
+		ports = append(ports, uint64(p.GetNumber()))
+	}
+
+	return ports
+}
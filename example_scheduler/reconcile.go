@@ -0,0 +1,91 @@
+package example_scheduler
+
+import (
+	"math/rand"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/golang/protobuf/proto"
+	"github.com/mesos/mesos-go/mesosproto"
+	"github.com/mesos/mesos-go/scheduler"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//reconcileTimeout bounds how long the launches wait for the master to answer
+//a reconciliation, in case some of the updates never arrive
+const reconcileTimeout = 30 * time.Second
+
+//implicitReconcileWait is how long the launches wait after an implicit
+//reconciliation. There is no way to tell when the master has sent the
+//state of every task, so we give it a moment
+const implicitReconcileWait = 5 * time.Second
+
+//reconcile asks the master for the state of every task we know about, so a
+//scheduler that was away learns which tasks are still running before
+//launching new ones. Without known tasks, after a restart, the list is
+//empty and the master answers with all the tasks of the framework. The
+//caller must hold the mutex
+func (s *ExampleScheduler) reconcile(driver scheduler.SchedulerDriver) {
+	statuses := make([]*mesosproto.TaskStatus, 0, len(s.tasks))
+	s.reconciling = make(map[string]bool)
+
+	for _, t := range s.tasks {
+		if tasks.IsTerminal(t.State) {
+			continue
+		}
+
+		status := &mesosproto.TaskStatus{
+			TaskId: &mesosproto.TaskID{Value: proto.String(t.id)},
+			State:  t.State.Enum(),
+		}
+		if t.agentId != "" {
+			status.SlaveId = &mesosproto.SlaveID{Value: proto.String(t.agentId)}
+		}
+
+		statuses = append(statuses, status)
+		s.reconciling[t.id] = true
+	}
+
+	s.reconcileImplicit = len(statuses) == 0
+	if s.reconcileImplicit {
+		s.reconcileDeadline = time.Now().Add(implicitReconcileWait)
+	} else {
+		s.reconcileDeadline = time.Now().Add(reconcileTimeout)
+	}
+
+	log.WithField("tasks", len(statuses)).Infoln("Reconciling tasks")
+	if _, err := driver.ReconcileTasks(statuses); err != nil {
+		log.WithError(err).Errorln("Unable to reconcile the tasks")
+		s.reconcileDeadline = time.Time{}
+	}
+}
+
+//isReconciling reports if the launches must wait for the answers of the
+//last reconciliation. The caller must hold the mutex
+func (s *ExampleScheduler) isReconciling() bool {
+	if !time.Now().Before(s.reconcileDeadline) {
+		return false
+	}
+
+	//An implicit reconciliation waits until the deadline
+	return s.reconcileImplicit || len(s.reconciling) > 0
+}
+
+//ReconcilePeriodically runs an implicit reconciliation every interval plus
+//a random jitter, a fraction of the interval. The master answers with the
+//state of every task of the framework, so the tasks we lost track of are
+//recorded again. It never returns, run it in its own goroutine
+func (s *ExampleScheduler) ReconcilePeriodically(interval time.Duration, jitter float64) {
+	for {
+		time.Sleep(interval + time.Duration(rand.Float64()*jitter*float64(interval)))
+
+		s.mutex.Lock()
+		if s.driver != nil {
+			log.Debugln("Running periodic implicit reconciliation")
+			if _, err := s.driver.ReconcileTasks([]*mesosproto.TaskStatus{}); err != nil {
+				log.WithError(err).Errorln("Unable to reconcile the tasks")
+			}
+		}
+		s.mutex.Unlock()
+	}
+}
@@ -0,0 +1,55 @@
+package example_scheduler
+
+import (
+	"github.com/mesos/mesos-go/mesosproto"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//trackLaunch records the offers the tasks of the agent are launched with,
+//until their first status update. The caller must hold the mutex
+func (s *ExampleScheduler) trackLaunch(agent *agentOffers) {
+	ids := make([]string, len(agent.offers))
+	for i, offer := range agent.offers {
+		ids[i] = offer.Id.GetValue()
+	}
+
+	for _, task := range agent.tasks {
+		s.record(task.TaskId.GetValue()).offerIds = ids
+	}
+}
+
+//rescindLaunches marks the tasks launched with a rescinded offer that
+//didn't get any status update yet. The master may have received the
+//launch after rescinding the offer, and then the task never starts. The
+//caller must hold the mutex
+func (s *ExampleScheduler) rescindLaunches(offerId string) {
+	for _, t := range s.tasks {
+		for _, id := range t.offerIds {
+			if id == offerId {
+				t.rescinded = true
+				taskLog(t).WithField("offer_id", offerId).Warnln("Offer rescinded while launching the task, it is requeued if it doesn't start")
+				break
+			}
+		}
+	}
+}
+
+//notLaunched reports if a task ended because the offers it was launched
+//with weren't valid anymore, or because the master never received its
+//launch, journaled before the scheduler crashed, so it never started.
+//A rescinded offer only counts until the first status update of the task.
+//The master answers the reconciliation of a launch it never received with
+//TASK_UNKNOWN, as the framework is partition aware
+func notLaunched(t *taskRecord, status *mesosproto.TaskStatus) bool {
+	state := status.GetState()
+	if t.journaled && len(t.offerIds) > 0 && status.GetReason() == mesosproto.TaskStatus_REASON_RECONCILIATION &&
+		(state == mesosproto.TaskState_TASK_UNKNOWN || tasks.IsTerminal(state)) {
+		return true
+	}
+
+	if !tasks.IsTerminal(state) {
+		return false
+	}
+
+	return (t.rescinded && len(t.offerIds) > 0) || status.GetReason() == mesosproto.TaskStatus_REASON_INVALID_OFFERS
+}
@@ -0,0 +1,39 @@
+package example_scheduler
+
+import (
+	"testing"
+
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+func TestNotLaunched(t *testing.T) {
+	reconciliation := mesosproto.TaskStatus_REASON_RECONCILIATION
+	invalidOffers := mesosproto.TaskStatus_REASON_INVALID_OFFERS
+
+	for _, c := range []struct {
+		name      string
+		journaled bool
+		rescinded bool
+		offerIds  []string
+		state     mesosproto.TaskState
+		reason    *mesosproto.TaskStatus_Reason
+		want      bool
+	}{
+		{"journaled launch unknown to the master", true, false, []string{"o1"}, mesosproto.TaskState_TASK_UNKNOWN, &reconciliation, true},
+		{"journaled launch lost by the master", true, false, []string{"o1"}, mesosproto.TaskState_TASK_LOST, &reconciliation, true},
+		{"journaled launch unknown outside a reconciliation", true, false, []string{"o1"}, mesosproto.TaskState_TASK_UNKNOWN, nil, false},
+		{"journaled launch already confirmed", true, false, nil, mesosproto.TaskState_TASK_UNKNOWN, &reconciliation, false},
+		{"launch unknown to the master", false, false, []string{"o1"}, mesosproto.TaskState_TASK_UNKNOWN, &reconciliation, false},
+		{"journaled launch running", true, false, []string{"o1"}, mesosproto.TaskState_TASK_RUNNING, &reconciliation, false},
+		{"rescinded offer", false, true, []string{"o1"}, mesosproto.TaskState_TASK_LOST, nil, true},
+		{"rescinded offer of a started task", false, true, nil, mesosproto.TaskState_TASK_LOST, nil, false},
+		{"invalid offers", false, false, nil, mesosproto.TaskState_TASK_LOST, &invalidOffers, true},
+		{"failed task", false, false, nil, mesosproto.TaskState_TASK_FAILED, nil, false},
+	} {
+		task := &taskRecord{journaled: c.journaled, rescinded: c.rescinded, offerIds: c.offerIds}
+		status := &mesosproto.TaskStatus{State: c.state.Enum(), Reason: c.reason}
+		if got := notLaunched(task, status); got != c.want {
+			t.Errorf("%s: notLaunched = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
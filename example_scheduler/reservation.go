@@ -0,0 +1,141 @@
+package example_scheduler
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/mesos/mesos-go/mesosproto"
+	"github.com/mesos/mesos-go/scheduler"
+)
+
+//Reservation makes the scheduler reserve dynamically the resources of
+//its tasks for Role when it accepts an offer. The reserved resources are
+//only offered to the role, so a task that restarts finds them again on the
+//same agent. They are unreserved when no job needs them anymore
+type Reservation struct {
+	Role      string
+	Principal string
+}
+
+//reserve returns the unreserved resource reserved for the role. With
+//several roles it is reserved for the role it is allocated to
+func (r *Reservation) reserve(unreserved *mesosproto.Resource) *mesosproto.Resource {
+	resource := proto.Clone(unreserved).(*mesosproto.Resource)
+	resource.Role = proto.String(r.Role)
+	if role := unreserved.GetAllocationInfo().GetRole(); role != "" {
+		resource.Role = proto.String(role)
+	}
+	resource.Reservation = &mesosproto.Resource_ReservationInfo{}
+	if r.Principal != "" {
+		resource.Reservation.Principal = proto.String(r.Principal)
+	}
+
+	return resource
+}
+
+//scalarResources returns the cpus, mem, disk and gpus resources of a task
+//that took t from the offers, and the unreserved resources to reserve before
+//launching it. The caller must hold the mutex.
+//The reserved resources are used with the role and reservation they were
+//offered with
+func (s *ExampleScheduler) scalarResources(t taken) ([]*mesosproto.Resource, []*mesosproto.Resource) {
+	var resources, reserve []*mesosproto.Resource
+
+	for _, resource := range t.scalars {
+		//The revocable resources can't be reserved
+		if s.Reservation != nil && isUnreserved(resource) && resource.Revocable == nil {
+			resource = s.Reservation.reserve(resource)
+			reserve = append(reserve, resource)
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources, reserve
+}
+
+//releaseOffers gives back the offers of the agent, refusing them for a long
+//time if no job needs resources. The resources reserved
+//for us in them are unreserved when no job needs them anymore or the
+//scheduler is tearing down, otherwise the offers are declined to keep them
+//for our next tasks. The caller must hold the mutex.
+//The reason they weren't used decides for how long they are refused
+func (s *ExampleScheduler) releaseOffers(driver scheduler.SchedulerDriver, agentId string, reason declineReason) {
+	held := s.offers[agentId]
+	if len(held) == 0 {
+		return
+	}
+
+	offers := heldOffers(held)
+	alog := agentLog(offers)
+	res := newOfferResources(offers...)
+
+	var operations []*mesosproto.Offer_Operation
+
+	//The volumes left after scaling down are destroyed and their disk
+	//unreserved. They are kept on teardown, the data may be needed later
+	if excess := s.excessVolumes(res); len(excess) > 0 {
+		var disks []*mesosproto.Resource
+		for _, volume := range excess {
+			id := volume.GetDisk().GetPersistence().GetId()
+			alog.WithField("volume_id", id).Infoln("Destroying persistent volume no task needs")
+			delete(s.volumes, id)
+
+			//The disk statically reserved for the role stays reserved
+			if volume.Reservation != nil {
+				disk := proto.Clone(volume).(*mesosproto.Resource)
+				disk.Disk = nil
+				disks = append(disks, disk)
+			}
+		}
+
+		operations = append(operations, &mesosproto.Offer_Operation{
+			Type:    mesosproto.Offer_Operation_DESTROY.Enum(),
+			Destroy: &mesosproto.Offer_Operation_Destroy{Volumes: excess},
+		})
+		if len(disks) > 0 {
+			operations = append(operations, &mesosproto.Offer_Operation{
+				Type:      mesosproto.Offer_Operation_UNRESERVE.Enum(),
+				Unreserve: &mesosproto.Offer_Operation_Unreserve{Resources: disks},
+			})
+		}
+	}
+
+	if len(res.reserved) > 0 && (s.teardown || (!s.stopping && !s.needsResources())) {
+		alog.WithField("resources", len(res.reserved)).Infoln("Unreserving resources no job needs")
+		operations = append(operations, &mesosproto.Offer_Operation{
+			Type:      mesosproto.Offer_Operation_UNRESERVE.Enum(),
+			Unreserve: &mesosproto.Offer_Operation_Unreserve{Resources: res.reserved},
+		})
+	}
+
+	if len(operations) == 0 {
+		s.declineOffers(driver, agentId, reason)
+		return
+	}
+
+	delete(s.offers, agentId)
+	s.auditOffers(AuditAccept, offers, "releasing the reserved resources and volumes no job needs")
+	filters := &mesosproto.Filters{RefuseSeconds: proto.Float64(s.refuseSeconds(reason))}
+	if _, err := driver.AcceptOffers(offerIDs(offers), operations, filters); err != nil {
+		alog.WithError(err).Errorln("Unable to release the resources")
+	}
+}
+
+//releaseAllOffers gives back every offer of the pool when no more tasks
+//are launched for now. The caller must hold the mutex
+func (s *ExampleScheduler) releaseAllOffers(driver scheduler.SchedulerDriver) {
+	for agentId := range s.offers {
+		s.releaseOffers(driver, agentId, declineIdle)
+	}
+}
+
+//needsResources reports if any job has instances to launch, now or once
+//its backoff ends. The caller must hold the mutex
+func (s *ExampleScheduler) needsResources() bool {
+	for _, job := range s.jobs {
+		if s.pendingInstances(job) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,247 @@
+package example_scheduler
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mesos/mesos-go/mesosproto"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//The types of job
+const (
+	//JobService keeps its instances running. A task isn't meant to end,
+	//finishing successfully is as much a failure as crashing
+	JobService = "service"
+
+	//JobBatch runs its instances to completion. A task that finishes
+	//successfully is done
+	JobBatch = "batch"
+)
+
+//The restart policies of a job
+const (
+	//RestartAlways replaces every task that ends, successfully or not
+	RestartAlways = "always"
+
+	//RestartOnFailure replaces the tasks that fail, the ones that finish
+	//successfully are done
+	RestartOnFailure = "on-failure"
+
+	//RestartNever doesn't replace any task
+	RestartNever = "never"
+)
+
+//Defaults of the RestartPolicy fields left empty
+const (
+	defaultBackoff    = 1 * time.Second
+	defaultMaxBackoff = 5 * time.Minute
+)
+
+//backoffResetAfter is how long a task must run before its failure is
+//considered unrelated to the previous ones, resetting the backoff
+const backoffResetAfter = 10 * time.Minute
+
+//RestartPolicy decides what happens when a task of the job ends
+type RestartPolicy struct {
+	//Policy is always, on-failure or never. Empty means always for the
+	//services and on-failure for the batch jobs
+	Policy string `json:"policy,omitempty"`
+
+	//MaxRetries is the number of consecutive failures after which the failed
+	//instances aren't replaced anymore. 0 means no limit
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	//Backoff is the seconds to wait before replacing a failed task, doubled
+	//on each consecutive failure up to MaxBackoff
+	Backoff    float64 `json:"backoff,omitempty"`
+	MaxBackoff float64 `json:"max_backoff,omitempty"`
+}
+
+//Validate checks the values of the policy
+func (p *RestartPolicy) Validate() error {
+	switch p.Policy {
+	case "", RestartAlways, RestartOnFailure, RestartNever:
+	default:
+		return fmt.Errorf("unknown restart policy %q, use %s, %s or %s", p.Policy, RestartAlways, RestartOnFailure, RestartNever)
+	}
+
+	switch {
+	case p.MaxRetries < 0:
+		return fmt.Errorf("max retries can't be negative")
+	case p.Backoff < 0 || p.MaxBackoff < 0:
+		return fmt.Errorf("backoff can't be negative")
+	}
+
+	return nil
+}
+
+//isBatch reports if the job runs its instances to completion
+func (j *JobSpec) isBatch() bool {
+	return j.Type == JobBatch
+}
+
+//restartPolicy returns the restart policy of the job, the default of its
+//type if it has none
+func (j *JobSpec) restartPolicy() string {
+	switch {
+	case j.Restart.Policy != "":
+		return j.Restart.Policy
+	case j.isBatch():
+		return RestartOnFailure
+	}
+
+	return RestartAlways
+}
+
+//restarts returns if a task of the job that ended with or without failure
+//must be replaced
+func (j *JobSpec) restarts(failed bool) bool {
+	switch j.restartPolicy() {
+	case RestartNever:
+		return false
+	case RestartOnFailure:
+		return failed
+	}
+
+	return true
+}
+
+//backoff returns the wait before replacing a task after the given number of
+//consecutive failures
+func (p *RestartPolicy) backoff(failures int) time.Duration {
+	base, max := defaultBackoff, defaultMaxBackoff
+	if p.Backoff > 0 {
+		base = time.Duration(p.Backoff * float64(time.Second))
+	}
+	if p.MaxBackoff > 0 {
+		max = time.Duration(p.MaxBackoff * float64(time.Second))
+	}
+
+	wait := float64(base) * math.Pow(2, float64(failures-1))
+	if wait > float64(max) {
+		return max
+	}
+
+	return time.Duration(wait)
+}
+
+//restartState is what the scheduler tracks to apply the restart policy of
+//a job
+type restartState struct {
+	//Consecutive failures of the tasks of the job
+	failures int
+
+	//No task of the job is launched before this time
+	backoffUntil time.Time
+
+	//Instances that ended and won't be replaced, and how many of them
+	//finished successfully
+	done      int
+	succeeded int
+
+	//The indexes of an indexed job that are done, and whether they
+	//succeeded, and the tasks launched for each index
+	indexes  map[int]bool
+	attempts map[int]int
+}
+
+//restartState returns the restart state of the job, creating it if needed.
+//The caller must hold the mutex
+func (s *ExampleScheduler) restartState(jobId string) *restartState {
+	r, ok := s.restarts[jobId]
+	if !ok {
+		r = &restartState{
+			indexes:  make(map[int]bool),
+			attempts: make(map[int]int),
+		}
+		s.restarts[jobId] = r
+	}
+
+	return r
+}
+
+//taskEnded applies the restart policy of the job of a task that reached a
+//terminal state not requested by us. The caller must hold the mutex
+func (s *ExampleScheduler) taskEnded(t *taskRecord, state mesosproto.TaskState) {
+	job := s.job(t.jobId)
+	if job == nil {
+		return
+	}
+
+	r := s.restartState(job.ID)
+	finished := state == mesosproto.TaskState_TASK_FINISHED
+	failed := !finished || !job.isBatch()
+	tlog := taskLog(t).WithField("restart_policy", job.restartPolicy())
+
+	//The tasks gone with their agent are replaced right away, it wasn't
+	//their fault
+	if tasks.IsGone(state) {
+		if job.Restart.Policy == RestartNever {
+			s.instanceDone(t, false)
+			tlog.Infoln("Task gone, it won't be replaced")
+		} else {
+			tlog.Infoln("Task gone, it will be replaced")
+		}
+		return
+	}
+
+	if !failed {
+		r.failures = 0
+	} else {
+		//A service that finished did it on its own, not because of its
+		//agent
+		if !finished {
+			s.taskFailed(t)
+		}
+		s.deploymentFailed(t)
+		if !t.launched.IsZero() && time.Since(t.launched) > backoffResetAfter {
+			r.failures = 0
+		}
+		r.failures++
+	}
+
+	switch {
+	case !job.restarts(failed):
+		s.instanceDone(t, !failed)
+		tlog.Infoln("Task ended, it won't be replaced")
+	case failed && job.Restart.MaxRetries > 0 && r.failures > job.Restart.MaxRetries:
+		s.instanceDone(t, false)
+		tlog.WithField("failures", r.failures).Errorln("Task failed too many times, it won't be replaced")
+	case failed:
+		wait := job.Restart.backoff(r.failures)
+		r.backoffUntil = time.Now().Add(wait)
+		tlog.WithField("failures", r.failures).Warnf("Task failed, it will be replaced in %v", wait)
+	default:
+		tlog.Infoln("Task finished, it will be replaced")
+	}
+}
+
+//taskTimedOut applies the restart policy of the job of a task killed for
+//running longer than its max runtime. It didn't complete, so it is
+//replaced unless the job never replaces its tasks, but it didn't crash
+//either: it doesn't count as a failure nor waits for a backoff. The caller
+//must hold the mutex
+func (s *ExampleScheduler) taskTimedOut(t *taskRecord) {
+	job := s.job(t.jobId)
+	if job == nil {
+		return
+	}
+
+	tlog := taskLog(t).WithField("restart_policy", job.restartPolicy())
+	if job.Restart.Policy == RestartNever {
+		s.instanceDone(t, false)
+		tlog.Infoln("Task timed out, it won't be replaced")
+		return
+	}
+
+	tlog.Infoln("Task timed out, it will be replaced")
+}
+
+//inBackoff reports if the launches of the job are delayed after a failure.
+//The caller must hold the mutex
+func (s *ExampleScheduler) inBackoff(job *JobSpec) bool {
+	r, ok := s.restarts[job.ID]
+	return ok && time.Now().Before(r.backoffUntil)
+}
@@ -0,0 +1,70 @@
+package example_scheduler
+
+import (
+	"fmt"
+
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//roleOf returns the role whose offers the tasks of the job use. Without
+//multiple roles the offers aren't allocated to any role in particular and
+//it is empty. The caller must hold the mutex
+func (s *ExampleScheduler) roleOf(job *JobSpec) string {
+	switch {
+	case len(s.Roles) == 0:
+		return ""
+	case job.Role == "":
+		return s.Roles[0]
+	}
+
+	return job.Role
+}
+
+//checkRole verifies that the role of the job is one of the roles of the
+//framework. The caller must hold the mutex
+func (s *ExampleScheduler) checkRole(job *JobSpec) error {
+	if job.Role != "" && !contains(s.Roles, job.Role) {
+		return fmt.Errorf("role %s is not one of the roles of the framework", job.Role)
+	}
+
+	return nil
+}
+
+//newRoleResources sums the resources of the offers of each role they are
+//allocated to. A task only uses the resources of a single role
+func newRoleResources(offers []*mesosproto.Offer) map[string]*offerResources {
+	byRole := make(map[string][]*mesosproto.Offer)
+	for _, offer := range offers {
+		role := offer.GetAllocationInfo().GetRole()
+		byRole[role] = append(byRole[role], offer)
+	}
+
+	res := make(map[string]*offerResources, len(byRole))
+	for role, offers := range byRole {
+		res[role] = newOfferResources(offers...)
+	}
+
+	return res
+}
+
+//resOf returns the resources of the agent allocated to the role of the
+//job. The caller must hold the mutex
+func (s *ExampleScheduler) resOf(agent *agentOffers, job *JobSpec) *offerResources {
+	role := s.roleOf(job)
+	if agent.res[role] == nil {
+		agent.res[role] = newOfferResources()
+	}
+
+	return agent.res[role]
+}
+
+//scalar returns the amount left of a scalar resource of the agent, of every
+//role
+func (a *agentOffers) scalar(name string) float64 {
+	var value float64
+	for _, res := range a.res {
+		value += res.scalar(name)
+	}
+
+	return value
+}
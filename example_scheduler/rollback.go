@@ -0,0 +1,75 @@
+package example_scheduler
+
+import (
+	"errors"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//ErrNoPrevious is returned when rolling back a job that was never deployed
+//with a new spec
+var ErrNoPrevious = errors.New("the job has no previous spec to roll back to")
+
+//rollback gives the job back the spec it had before the deployment. Before
+//the cutover of a blue-green deployment the old tasks are all still there
+//and the new ones are just killed; otherwise a rolling deployment, without
+//canary, replaces the new tasks with the previous spec. The caller must
+//hold the mutex
+func (s *ExampleScheduler) rollback(d *deployment) {
+	job := s.job(d.jobId)
+	if job == nil {
+		return
+	}
+
+	s.setDeploymentState(d, DeploymentRolledBack)
+	d.updated = time.Now()
+
+	//The failures of the new spec don't delay the tasks of the previous one
+	delete(s.restarts, job.ID)
+	s.reviveIfNeeded(true)
+
+	if d.strategy == UpgradeBlueGreen && !d.cutover {
+		_, fresh, _ := s.deploymentTasks(d)
+		s.restoreSpec(d)
+		for _, t := range fresh {
+			if err := s.kill(t, "rollback of the blue-green deployment"); err != nil {
+				taskLog(t).WithError(err).Errorln("Unable to kill the task")
+			}
+		}
+		return
+	}
+
+	failed := *job
+	instances := job.Instances
+	*job = *d.previous
+	job.Instances = instances
+	s.saveJob(job)
+
+	r := s.startDeployment(job, &failed)
+	r.strategy = UpgradeRolling
+	r.rollback = true
+}
+
+//RollbackDeployment redeploys the spec the job had before its last
+//deployment
+func (s *ExampleScheduler) RollbackDeployment(jobId string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.job(jobId) == nil {
+		return ErrUnknownJob
+	}
+	d, ok := s.deployments[jobId]
+	if !ok || d.previous == nil || d.state == DeploymentRolledBack {
+		return ErrNoPrevious
+	}
+
+	log.WithFields(log.Fields{
+		"job_id":  d.jobId,
+		"version": d.version,
+	}).Warnln("Rolling back the deployment")
+	s.rollback(d)
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package example_scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//The orders in which the excess tasks of a job are killed
+const (
+	//KillNewestFirst kills the most recently launched tasks
+	KillNewestFirst = "newest-first"
+
+	//KillLeastHealthyFirst kills the unhealthy tasks, then the ones not
+	//running yet, then the ones not ready, and the most recently launched
+	//among equals
+	KillLeastHealthyFirst = "least-healthy-first"
+)
+
+//validKillSelection checks the order of the kills, empty is the default
+func validKillSelection(selection string) error {
+	switch selection {
+	case "", KillNewestFirst, KillLeastHealthyFirst:
+		return nil
+	}
+
+	return fmt.Errorf("unknown kill selection %q, use %s or %s", selection, KillNewestFirst, KillLeastHealthyFirst)
+}
+
+//healthRank orders the tasks from the least healthy to the healthiest. The
+//caller must hold the mutex
+func (s *ExampleScheduler) healthRank(t *taskRecord) int {
+	switch {
+	case t.healthy != nil && !*t.healthy:
+		return 0
+	case t.State != mesosproto.TaskState_TASK_RUNNING:
+		return 1
+	case !s.isReady(t):
+		return 2
+	}
+
+	return 3
+}
+
+//sortVictims orders the tasks to kill first at the beginning. The tasks
+//with an older spec always go first. The caller must hold the mutex
+func (s *ExampleScheduler) sortVictims(tasks []*taskRecord, selection string) {
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].version != tasks[j].version {
+			return tasks[i].version < tasks[j].version
+		}
+		if selection != KillNewestFirst {
+			if ri, rj := s.healthRank(tasks[i]), s.healthRank(tasks[j]); ri != rj {
+				return ri < rj
+			}
+		}
+		return tasks[i].launched.After(tasks[j].launched)
+	})
+}
+
+//startScale returns the deployment bringing the job to its new number of
+//instances. A deployment in progress takes the scaling along, as it only
+//finishes once every instance runs, otherwise a new one is started. It
+//keeps the previous spec of the last deployment so the job can still be
+//rolled back to it. The caller must hold the mutex
+func (s *ExampleScheduler) startScale(job *JobSpec) *deployment {
+	last, ok := s.deployments[job.ID]
+	if ok && (last.state == DeploymentRunning || last.state == DeploymentWaiting) {
+		return last
+	}
+
+	s.versions[job.ID]++
+	now := time.Now()
+	d := &deployment{
+		jobId:    job.ID,
+		version:  s.versions[job.ID],
+		strategy: UpgradeScale,
+		state:    DeploymentRunning,
+		started:  now,
+		updated:  now,
+	}
+	if ok && last.state != DeploymentRolledBack {
+		d.previous = last.previous
+	}
+	s.deployments[job.ID] = d
+	s.publishDeployment(d, "")
+
+	log.WithFields(log.Fields{
+		"job_id":    job.ID,
+		"version":   d.version,
+		"instances": job.Instances,
+	}).Infoln("Scaling started")
+
+	return d
+}
+
+//advanceScale finishes the scaling of a job once its tasks are all
+//running, ready and healthy, the excess ones ended and no instance is
+//pending. The caller must hold the mutex
+func (s *ExampleScheduler) advanceScale(d *deployment, job *JobSpec, tasks []*taskRecord, inFlight int) {
+	for _, t := range tasks {
+		if t.killed {
+			return
+		}
+	}
+	if inFlight > 0 || s.pendingInstances(job) > 0 {
+		return
+	}
+
+	s.setDeploymentState(d, DeploymentFinished)
+	d.updated = time.Now()
+	log.WithFields(log.Fields{
+		"job_id":  d.jobId,
+		"version": d.version,
+	}).Infof("Scaling finished in %v", d.updated.Sub(d.started))
+}
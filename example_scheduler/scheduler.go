@@ -1,49 +1,330 @@
 package example_scheduler
 
 import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
 	log "github.com/Sirupsen/logrus"
 	"github.com/golang/protobuf/proto"
 	"github.com/mesos/mesos-go/mesosproto"
-	"github.com/mesos/mesos-go/mesosutil"
 	"github.com/mesos/mesos-go/scheduler"
-	"github.com/satori/go.uuid"
+	"minimal-mesos-go-framework/store"
+	"minimal-mesos-go-framework/tasks"
 )
 
 type ExampleScheduler struct {
 	ExecutorInfo *mesosproto.ExecutorInfo
 
-	//The CPUs that the tasks need
-	NeededCpu float64
+	//mutex guards everything below. The driver callbacks and the operations
+	//requested through the API run in different goroutines. The exported
+	//settings are set before starting the driver, the controller or the API
+	//and never change afterwards, the rest only changes with the mutex held
+	mutex sync.Mutex
+
+	//FrameworkIDStore, if set, saves the FrameworkID on registration so a
+	//restarted scheduler can fail over to the same framework
+	FrameworkIDStore store.FrameworkIDStore
+
+	//Store, if set, keeps the jobs and the tasks so a restarted scheduler
+	//knows them before the master tells it about them
+	Store store.Store
+
+	//DryRun makes the scheduler log the tasks it would launch on every offer
+	//and decline it instead of launching them
+	DryRun bool
+
+	//AllowCommandReadiness accepts the jobs with a command readiness check.
+	//The command runs on the scheduler host, so it is refused by default
+	AllowCommandReadiness bool
+
+	//The jobs managed by the scheduler, in submission order
+	jobs []*JobSpec
+
+	//Every task launched, by task ID
+	tasks map[string]*taskRecord
+
+	//The persistent volumes of the jobs, by persistence ID
+	volumes map[string]*volumeRecord
+
+	//The state of the restart policy of each job, by job ID
+	restarts map[string]*restartState
+
+	//The version of the spec of each job, increased by every deployment,
+	//and the last deployment of each job, by job ID
+	versions    map[string]int
+	deployments map[string]*deployment
+
+	//The tasks of the last reconciliation still waiting for their state,
+	//whether it was an implicit one, and until when the launches wait for
+	//it
+	reconciling       map[string]bool
+	reconcileImplicit bool
+	reconcileDeadline time.Time
+
+	//stopping is set by Shutdown, no more tasks are launched. teardown is
+	//set too if the tasks are killed, so their reserved resources are
+	//unreserved
+	stopping bool
+	teardown bool
+
+	//UnreachableGrace is how long an unreachable task is waited for before
+	//it is replaced. Zero is 5 minutes
+	UnreachableGrace time.Duration
+
+	//LaunchTimeout is how long a task may take to reach TASK_RUNNING
+	//before it is killed and replaced. Zero is 5 minutes
+	LaunchTimeout time.Duration
+
+	//LostAgentCooldown is how long no task is placed on an agent after it
+	//is lost. Zero is 10 minutes
+	LostAgentCooldown time.Duration
+
+	//PreemptionGrace is how long the instances of a job wait for resources
+	//before the tasks of lower priority jobs are preempted for them. Zero
+	//is 1 minute
+	PreemptionGrace time.Duration
+
+	//The jobs with instances waiting for resources, with since when or
+	//since their last preemption
+	starving map[string]time.Time
+
+	//The instances waiting to launch, in the order they were queued, and
+	//the wait of the ones launched
+	queue      []*queuedLaunch
+	queueStats queueStats
+
+	//The schedule and the runs of the cron jobs, by job ID
+	crons map[string]*cronState
+
+	//The last state of the jobs with dependencies, by job ID
+	pipeline map[string]string
+
+	//MaxQueued is the most instances waiting in the launch queue. Adding
+	//more instances to the jobs fails with ErrQueueFull. Zero is no limit
+	MaxQueued int
+
+	//The lost agents in their cool-down, by agent ID and hostname, with
+	//the end of the cool-down
+	lostAgents map[string]time.Time
+
+	//The crashes of the executors of each agent, by agent ID
+	executorFailures map[string]*executorFailures
+
+	//AgentFailures decides when the agents where the tasks keep failing
+	//are blacklisted
+	AgentFailures AgentFailurePolicy
+
+	//The failure history of the tasks of each agent, by agent ID
+	agentHealth map[string]*agentHealth
+
+	//TaskHistory decides how long the ended tasks are kept. The zero value
+	//keeps them all
+	TaskHistory TaskHistoryPolicy
+
+	//Placement chooses the agent of each task. Nil is FirstFit
+	Placement Placement
+
+	//Reservation, if set, reserves dynamically the resources of the tasks
+	Reservation *Reservation
+
+	//The agents where the tasks can run
+	hosts HostFilter
+
+	//Roles of the framework when it has several, the first one is the
+	//role of the jobs without any
+	Roles []string
+
+	//Decline chooses the filters of the offers given back
+	Decline DeclinePolicy
+
+	//suppressed is set when the offers are refused for a long time because
+	//no job needs resources, filtered when the offers of some agent are
+	//refused for not matching the jobs
+	suppressed bool
+	filtered   bool
+
+	//paused is set while the scheduling is paused for maintenance, with
+	//the reason given and since when
+	paused      bool
+	pauseReason string
+	pausedSince time.Time
+
+	//disconnected is set while the scheduler is disconnected from the
+	//master. The kills requested meanwhile wait in pendingKills
+	disconnected bool
+	pendingKills map[string]bool
 
-	//The RAM that the tasks need
-	NeededRam float64
+	//aborted is the error the driver was aborted for, if it was
+	aborted string
 
-	launched bool
+	//The offers not used yet, by agent ID, and what became of the offers
+	//received
+	offers     map[string][]*heldOffer
+	offerStats offerStats
+
+	//The driver received on the last callback, used by the operations that
+	//don't come from the driver
+	driver scheduler.SchedulerDriver
+
+	//The last entries of the audit log and the sequence number of the
+	//last one, and the last reason each job wasn't placed for
+	auditLog []AuditEntry
+	auditSeq int64
+	unplaced map[string]string
+
+	//The kills requested through the operations, oldest first
+	killOps []*killOp
+
+	//The submissions with an idempotency key, by key
+	submissions map[string]*store.Submission
+
+	//The channels of the subscribers to the events with their filters, the
+	//last events and the sequence number of the last one
+	subscribers map[chan Event]EventFilter
+	events      []Event
+	eventSeq    int64
+
+	//AuditLog, if set, receives every entry of the audit log as a line of
+	//JSON
+	AuditLog io.Writer
+}
+
+//NewExampleScheduler creates a scheduler that launches the tasks of jobs
+//using executorInfo for the jobs without a Docker image
+func NewExampleScheduler(executorInfo *mesosproto.ExecutorInfo, jobs ...*JobSpec) *ExampleScheduler {
+	return &ExampleScheduler{
+		ExecutorInfo:     executorInfo,
+		jobs:             jobs,
+		tasks:            make(map[string]*taskRecord),
+		restarts:         make(map[string]*restartState),
+		volumes:          make(map[string]*volumeRecord),
+		offers:           make(map[string][]*heldOffer),
+		lostAgents:       make(map[string]time.Time),
+		executorFailures: make(map[string]*executorFailures),
+		pendingKills:     make(map[string]bool),
+		agentHealth:      make(map[string]*agentHealth),
+		versions:         make(map[string]int),
+		deployments:      make(map[string]*deployment),
+		starving:         make(map[string]time.Time),
+		crons:            make(map[string]*cronState),
+		pipeline:         make(map[string]string),
+		unplaced:         make(map[string]string),
+		subscribers:      make(map[chan Event]EventFilter),
+		submissions:      make(map[string]*store.Submission),
+	}
 }
 
 //StatusUpdate is called by a running task to provide status information to the
 //scheduler.
 func (s *ExampleScheduler) StatusUpdate(driver scheduler.SchedulerDriver, status *mesosproto.TaskStatus) {
-	log.Infoln("Status update: task", status.TaskId.GetValue(), " is in state ", status.State.Enum().String())
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.driver = driver
+
+	t := s.record(status.TaskId.GetValue())
+	wasTerminal := tasks.IsTerminal(t.State)
+	delete(s.reconciling, t.id)
+
+	tlog := taskLog(t)
+	if !t.Update(status) {
+		tlog.WithFields(log.Fields{
+			"state":   status.GetState().String(),
+			"current": t.State.String(),
+		}).Debugln("Ignoring status update out of order")
+		return
+	}
+	if status.SlaveId != nil {
+		t.agentId = status.SlaveId.GetValue()
+	}
+
+	tlog.WithField("state", status.GetState().String()).Infoln("Status update")
+	s.publishStatus(t, status)
+	trackHealth(t, status)
+	s.saveTask(t)
+
+	//The main task of a pod stands for the instance, the default executor
+	//kills the whole group when any of its tasks fails
+	if t.sidecar {
+		return
+	}
+
+	s.trackUnreachable(t)
+
+	//A task launched with offers that weren't valid anymore never started,
+	//it is requeued without counting as a failure
+	if notLaunched(t, status) {
+		if !wasTerminal && t.journaled {
+			tlog.WithField("offer_ids", t.offerIds).Warnln("Task not launched, the master never received the launch journaled before the restart, requeuing it")
+			//A TASK_UNKNOWN task isn't ended, it stops counting as an
+			//instance right away instead of after the unreachable grace
+			t.replaced = !tasks.IsTerminal(t.State)
+			s.reviveIfNeeded(true)
+		} else if !wasTerminal {
+			tlog.WithField("message", status.GetMessage()).Warnln("Task not launched, its offers were rescinded, requeuing it")
+			s.reviveIfNeeded(true)
+		}
+		t.offerIds = nil
+		t.rescinded = false
+		return
+	}
+	t.offerIds = nil
+	t.rescinded = false
+
+	if t.killed && status.GetState() == mesosproto.TaskState_TASK_KILLED {
+		tlog.WithField("attempts", t.killAttempts).Infoln("Task killed as requested")
+		if t.timedOut && !wasTerminal {
+			s.taskTimedOut(t)
+			s.evaluatePipeline()
+			s.reviveIfNeeded(true)
+		}
+		return
+	}
 
 	if status.GetState() == mesosproto.TaskState_TASK_RUNNING {
-		s.launched = true
-		log.Info("Server is running")
+		tlog.Info("Server is running")
 	}
 
 	if status.GetState() == mesosproto.TaskState_TASK_FINISHED {
-		log.Info("Server is finished")
+		if job := s.job(t.jobId); job != nil && job.isBatch() {
+			tlog.Info("Task completed")
+		} else {
+			tlog.Warnln("Server is finished, a service isn't meant to end")
+		}
+	}
+
+	//The restart policy applies to the tasks killed for failing their
+	//health checks like to any other failure
+	if killedUnhealthy(t, status) {
+		tlog.WithField("message", status.GetMessage()).Warnln("Task killed for failing its health checks")
 	}
 
 	if status.GetState() == mesosproto.TaskState_TASK_LOST ||
 		status.GetState() == mesosproto.TaskState_TASK_KILLED ||
-		status.GetState() == mesosproto.TaskState_TASK_FAILED {
-		log.Infoln(
-			"Aborting because task", status.TaskId.GetValue(),
-			"is in unexpected state", status.State.String(),
-			"with message: ", status.GetMessage(),
-		)
-		driver.Abort()
+		status.GetState() == mesosproto.TaskState_TASK_FAILED ||
+		status.GetState() == mesosproto.TaskState_TASK_ERROR {
+		tlog.WithFields(log.Fields{
+			"state":   status.GetState().String(),
+			"message": status.GetMessage(),
+		}).Errorln("Task ended unexpectedly")
+	}
+
+	if tasks.IsGone(status.GetState()) {
+		tlog.WithFields(log.Fields{
+			"state":   status.GetState().String(),
+			"message": status.GetMessage(),
+		}).Warnln("Task gone with its agent")
+	}
+
+	//The restart policy decides if the controller replaces the task.
+	//Reconciliations may repeat the terminal update of a task, and the
+	//unreachable tasks that were replaced already don't count
+	if tasks.IsTerminal(t.State) && !wasTerminal && !t.replaced {
+		s.taskEnded(t, t.State)
+		s.evaluatePipeline()
+		s.reviveIfNeeded(true)
 	}
 }
 
@@ -51,104 +332,332 @@ func (s *ExampleScheduler) StatusUpdate(driver scheduler.SchedulerDriver, status
 //offers to this framework. Is up to you to check the content of the offers
 //and to accept or reject them if they don't fit the needs of the framework
 func (s *ExampleScheduler) ResourceOffers(driver scheduler.SchedulerDriver, offers []*mesosproto.Offer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.driver = driver
+
 	for _, offer := range offers {
-		if s.launched {
-			driver.DeclineOffer(offer.Id, &mesosproto.Filters{RefuseSeconds: proto.Float64(1)})
+		//Print information about the received offer
+		res := newOfferResources(offer)
+		offerLog(offer).WithFields(log.Fields{
+			"cpus":  res.scalar("cpus"),
+			"mem":   res.scalar("mem"),
+			"ports": res.portCount(),
+		}).Infoln("Received offer")
+
+		s.holdOffer(offer)
+	}
+
+	s.placeTasks(driver)
+}
+
+//placeTasks uses the offers in the pool to launch the pending instances.
+//The offers of the same agent are merged, so tasks that don't fit in any
+//of them alone can still be placed, and the Placement chooses the agent of
+//each task. The offers that don't fit any task are kept in the pool for a
+//while, waiting for more offers of their agent. The caller must hold the
+//mutex
+func (s *ExampleScheduler) placeTasks(driver scheduler.SchedulerDriver) {
+	//In a dry run no task is recorded, so count the instances that would
+	//have been launched from the offers in the pool
+	planned := make(map[string]int)
+	s.syncQueue()
+
+	switch {
+	case s.disconnected:
+		log.Debugln("Not launching tasks, disconnected from the master")
+		return
+	case s.stopping:
+		log.Debugln("Declining offers, shutting down")
+		s.auditAllOffers("shutting down")
+		s.releaseAllOffers(driver)
+		return
+	case s.paused:
+		log.Debugln("Declining offers, the scheduling is paused")
+		s.auditAllOffers("scheduling paused")
+		s.suppressAllOffers(driver)
+		return
+	case s.isReconciling():
+		log.Debugln("Declining offers, waiting for the reconciliation of the tasks")
+		s.auditAllOffers("waiting for the reconciliation of the tasks")
+		s.declineAllOffers(driver)
+		return
+	case !s.hasPendingInstances(planned):
+		log.Debugln("Declining offers, no instances pending to launch")
+		s.auditAllOffers("no instances pending to launch")
+		s.releaseAllOffers(driver)
+		return
+	}
+
+	var agents []*agentOffers
+	for agentId, held := range s.offers {
+		offers := heldOffers(held)
+
+		if !s.hosts.allows(offers[0].GetHostname()) {
+			agentLog(offers).Infoln("Declining offers, the agent is excluded by the host filter")
+			s.auditOffers(AuditDecline, offers, "the agent is excluded by the host filter")
+			s.declineOffers(driver, agentId, declineExcluded)
+			continue
+		}
+		if left, lost := s.isLost(agentId, offers[0].GetHostname()); lost {
+			agentLog(offers).Infoln("Declining offers, the agent was lost recently")
+			s.auditOffers(AuditDecline, offers, "the agent was lost recently")
+			s.declineOffersFor(driver, agentId, left)
 			continue
 		}
 
-		offeredCpu := 0.0
-		offeredMem := 0.0
-		var offeredPort []*mesosproto.Value_Range = make([]*mesosproto.Value_Range, 1)
-
-		// Iterate over resources to find one that fits all our needs. This
-		// usually isn't done this way as you must accept an offer that cover
-		// partially your needs and keep accepting until you fit all of them
-		for _, resource := range offer.Resources {
-			switch resource.GetName() {
-			case "cpus":
-				offeredCpu += resource.GetScalar().GetValue()
-			case "mem":
-				offeredMem += resource.GetScalar().GetValue()
-			case "ports":
-				ranges := resource.GetRanges()
-
-				//Take the first value of the range as we only need one port
-				if len(ranges.Range) > 0 {
-					firstRange := ranges.Range[0]
-
-					uniquePortRange := mesosproto.Value_Range{
-						Begin: firstRange.Begin,
-						End:   firstRange.Begin,
-					}
-
-					offeredPort[0] = &uniquePortRange
-				}
+		res := newRoleResources(offers)
+		for _, r := range res {
+			s.learnVolumes(agentId, r)
+		}
+
+		agents = append(agents, &agentOffers{
+			id:     agentId,
+			held:   held,
+			offers: offers,
+			res:    res,
+		})
+	}
+
+	//The agents whose offers arrived first go first
+	sort.Slice(agents, func(i, j int) bool { return oldestHeld(agents[i].held).Before(oldestHeld(agents[j].held)) })
+
+	//A gang job launches all its pending instances at once or none, so
+	//the placement starts over without the ones that didn't fit whole
+	skip := make(map[string]bool)
+	for {
+		want := s.gangsPending(planned, skip)
+		s.placeJobs(agents, planned, skip)
+
+		incomplete := s.incompleteGangs(agents, want)
+		if len(incomplete) == 0 {
+			break
+		}
+		for _, jobId := range incomplete {
+			log.WithField("job_id", jobId).Infoln("Not launching the gang, the offers don't fit all its tasks")
+			s.auditUnplaced(s.job(jobId), "the offers don't fit all the tasks of the gang")
+			skip[jobId] = true
+		}
+		s.unplace(agents, planned)
+	}
+
+	for _, agent := range agents {
+		alog := agentLog(agent.offers)
+
+		if len(agent.tasks) == 0 {
+			//More offers of the agent won't bring the fixed ports it
+			//doesn't have
+			if missing := s.missingPorts(agent, planned); len(missing) > 0 {
+				alog.WithField("ports", missing).Infoln("Declining offers, they don't have the fixed ports of the jobs")
+				s.auditOffers(AuditDecline, agent.offers, fmt.Sprintf("the fixed ports %v of the jobs aren't offered", missing))
+				s.releaseOffers(driver, agent.id, declineUnfit)
+				continue
+			}
+
+			switch {
+			case !s.matchesPendingJob(agent, planned):
+				alog.Infoln("Declining offers, the agent doesn't match the constraints of any job")
+				s.auditOffers(AuditDecline, agent.offers, "the agent doesn't match the constraints of any job")
+				s.releaseOffers(driver, agent.id, declineMismatch)
+			case oldestHeld(agent.held).Add(offerHoldTime).Before(time.Now()):
+				alog.Infoln("Declining offers, they don't fit any job")
+				s.auditOffers(AuditDecline, agent.offers, "the offers don't fit any job")
+				s.releaseOffers(driver, agent.id, declineUnfit)
+			default:
+				alog.Debugln("Holding offers, waiting for more offers of the agent")
 			}
+			continue
 		}
 
-		//Print information about the received offer
-		log.Infof("Received Offer <%v> with cpus=%v mem=%v, port=%v from %s",
-			offer.Id.GetValue(),
-			offeredCpu,
-			offeredMem,
-			offeredPort[0].GetBegin(),
-			*offer.Hostname)
-
-		//Decline offer if the offer doesn't satisfy our needs
-		if offeredCpu < s.NeededCpu || offeredMem < s.NeededRam || len(offeredPort) == 0 {
-			log.Infof("Declining offer <%v>\n", offer.Id.GetValue())
-			driver.DeclineOffer(offer.Id, &mesosproto.Filters{RefuseSeconds: proto.Float64(1)})
+		if s.DryRun {
+			for _, task := range agent.tasks {
+				alog.WithFields(log.Fields{
+					"task_id": task.TaskId.GetValue(),
+					"task":    proto.CompactTextString(task),
+				}).Infoln("Dry run: the offers would be accepted to launch the task")
+			}
+			s.requeue(agent.tasks, false)
+			s.auditOffers(AuditDecline, agent.offers, "dry run")
+			s.declineOffers(driver, agent.id, declineWaiting)
 			continue
 		}
 
-		// At this point we have determined we accept the offer
-
-		// We have to create a TaskID so we use the go-uuid library to create
-		// a random id.
-		taskId := &mesosproto.TaskID{
-			Value: proto.String(uuid.NewV4().String()),
-		}
-
-		//Provide information about the name of the task, id, the slave will
-		//be run of, the executor (that contains the command to execute as well
-		//as the uri to download the executor or executors from and the amount
-		//of resource the taks will use (not neccesary all from the offer)
-		task := &mesosproto.TaskInfo{
-			Name:    proto.String("go-task-" + taskId.GetValue()),
-			TaskId:  taskId,
-			SlaveId: offer.SlaveId,
-			Resources: []*mesosproto.Resource{
-				mesosutil.NewScalarResource("cpus", s.NeededCpu),
-				mesosutil.NewScalarResource("mem", s.NeededRam),
-				mesosutil.NewRangesResource("ports", offeredPort),
-			},
-			Command: &mesosproto.CommandInfo{
-				Value: proto.String("sleep 600"),
-			},
-			Container: &mesosproto.ContainerInfo{
-				Type: mesosproto.ContainerInfo_DOCKER.Enum(),
-				Docker: &mesosproto.ContainerInfo_DockerInfo{
-					Image: proto.String("index.alauda.cn/alauda/ubuntu"),
-				},
-			},
-			Data: []byte("Hello from Server"),
-		}
-
-		log.Infof("Prepared task: %s with offer %s for launch\n", task.GetName(), offer.Id.GetValue())
-
-		var tasks []*mesosproto.TaskInfo
-		tasks = append(tasks, task)
-
-		log.Infoln("Launching task for offer", offer.Id.GetValue())
-
-		//Launch the task
-		status, err := driver.LaunchTasks([]*mesosproto.OfferID{offer.Id}, tasks, &mesosproto.Filters{RefuseSeconds: proto.Float64(10)})
+		alog.WithField("tasks", len(agent.tasks)).Infoln("Launching tasks")
+
+		//Accept the offers with the operations that reserve the resources,
+		//create the volumes and launch the tasks
+		var operations []*mesosproto.Offer_Operation
+		if len(agent.reserve) > 0 {
+			operations = append(operations, &mesosproto.Offer_Operation{
+				Type:    mesosproto.Offer_Operation_RESERVE.Enum(),
+				Reserve: &mesosproto.Offer_Operation_Reserve{Resources: agent.reserve},
+			})
+		}
+		if len(agent.create) > 0 {
+			operations = append(operations, &mesosproto.Offer_Operation{
+				Type:   mesosproto.Offer_Operation_CREATE.Enum(),
+				Create: &mesosproto.Offer_Operation_Create{Volumes: agent.create},
+			})
+		}
+		operations = append(operations, agent.launchOperations()...)
+
+		delete(s.offers, agent.id)
+		s.trackLaunch(agent)
+		s.saveTasks(agent.tasks)
+		status, err := driver.AcceptOffers(offerIDs(agent.offers), operations, &mesosproto.Filters{RefuseSeconds: proto.Float64(s.Decline.refuseSeconds(declineAccepted))})
 		if err != nil {
-			log.Fatal(err)
+			alog.WithError(err).Errorln("Unable to launch the tasks, requeuing them")
+			s.auditOffers(AuditDecline, agent.offers, "unable to send the launch: "+err.Error())
+			s.launchFailed(driver, agent)
+			continue
 		}
 
-		log.Infof("Launch task status: %v", status)
-		s.launched = true
+		s.auditOffers(AuditAccept, agent.offers, fmt.Sprintf("launching %d tasks", len(agent.tasks)))
+		for _, task := range agent.tasks {
+			s.audit(AuditEntry{
+				Action:   AuditLaunch,
+				JobID:    jobOfTask(task.TaskId.GetValue()),
+				TaskID:   task.TaskId.GetValue(),
+				AgentID:  agent.id,
+				Hostname: agent.offers[0].GetHostname(),
+				OfferIDs: auditOfferIDs(agent.offers),
+			})
+		}
+		s.dequeue(agent.tasks)
+		alog.WithField("status", status.String()).Infoln("Tasks launched")
 	}
 }
+
+//placeJobs places the instances waiting in the launch queue, but the ones
+//of the skipped jobs, on the agents in the order of the queue. Once an
+//instance of a job fits no agent the later ones of the job are passed over
+//too. The caller must hold the mutex
+func (s *ExampleScheduler) placeJobs(agents []*agentOffers, planned map[string]int, skip map[string]bool) {
+	unfit := make(map[string]bool)
+	indexes := make(map[string]map[int]bool)
+
+	for _, q := range s.queueOrder() {
+		job := s.job(q.jobId)
+		if skip[job.ID] || unfit[job.ID] {
+			continue
+		}
+		if s.inBackoff(job) {
+			s.auditUnplaced(job, "in the backoff of its restart policy")
+			continue
+		}
+
+		agent := s.selectAgent(agents, job)
+		if agent == nil {
+			unfit[job.ID] = true
+			s.auditUnplaced(job, s.unfitReason(agents, job))
+			continue
+		}
+		delete(s.unplaced, job.ID)
+
+		index := s.nextIndex(job, indexes[job.ID])
+		if job.Indexed && index < 0 {
+			continue
+		}
+		if job.Indexed {
+			if indexes[job.ID] == nil {
+				indexes[job.ID] = make(map[int]bool)
+			}
+			indexes[job.ID][index] = true
+		}
+
+		task := s.newTask(job, agent, index)
+		agent.tasks = append(agent.tasks, task)
+		q.taskId = task.TaskId.GetValue()
+
+		agentLog(agent.offers).WithFields(log.Fields{
+			"task_id": task.TaskId.GetValue(),
+			"job_id":  job.ID,
+		}).Infof("Prepared task %s for launch", task.GetName())
+
+		//In a dry run the task only counts as planned, otherwise it is
+		//recorded now so the next iterations see it as active
+		if s.DryRun {
+			planned[job.ID]++
+		} else {
+			t := s.record(task.TaskId.GetValue())
+			t.hostname = agent.offers[0].GetHostname()
+			t.agentId = agent.id
+			t.fields = offerFields(agent.offers[0])
+			t.cpus = job.needs("cpus")
+			t.mem = job.needs("mem")
+			t.disk = job.needs("disk")
+			t.gpus = job.needs("gpus")
+			t.volume = volumeOfTask(task)
+			t.ports = portsOfTask(task)
+			t.version = s.versions[job.ID]
+			t.launched = time.Now()
+			t.Start(mesosproto.TaskState_TASK_STAGING, t.launched)
+			s.assignRun(job, t)
+			if job.Indexed {
+				t.index = index
+				s.restartState(job.ID).attempts[index]++
+			}
+		}
+	}
+}
+
+//newTask builds the TaskInfo of a new task of the job, taking its resources
+//from the offers of the agent. The index, if not -1, is passed to the task
+//in its environment. The resources to reserve and the volumes to
+//create before launching it are added to the agent
+func (s *ExampleScheduler) newTask(job *JobSpec, agent *agentOffers, index int) *mesosproto.TaskInfo {
+	if len(job.Containers) > 0 {
+		return s.newPod(job, agent, index)
+	}
+
+	offer := agent.offers[0]
+
+	// We have to create a TaskID so we use the go-uuid library to create
+	// a random id, prefixed by the job ID so we know the job of any task.
+	taskId := &mesosproto.TaskID{
+		Value: proto.String(job.newTaskID()),
+	}
+
+	//The volume goes first, it may need a whole part of the reserved disk
+	var volume *mesosproto.Resource
+	if job.Volume != nil {
+		volume = s.takeVolume(agent, job)
+	}
+
+	t := s.resOf(agent, job).take(job)
+	resources, reserve := s.scalarResources(t)
+	agent.reserve = append(agent.reserve, reserve...)
+	resources = append(resources, t.portResources...)
+	if volume != nil {
+		resources = append(resources, volume)
+	}
+
+	//Provide information about the name of the task, id, the slave will
+	//be run of, the executor (that contains the command to execute as well
+	//as the uri to download the executor or executors from and the amount
+	//of resource the taks will use (not neccesary all from the offer)
+	name := "go-task-" + taskId.GetValue()
+	task := &mesosproto.TaskInfo{
+		Name:       proto.String(name),
+		TaskId:     taskId,
+		SlaveId:    offer.SlaveId,
+		Resources:  resources,
+		Discovery:  job.portsDiscovery(name, t.ports),
+		KillPolicy: job.killPolicy(),
+		Data:       []byte("Hello from Server"),
+	}
+
+	//Without an image the task runs in our executor, otherwise the
+	//command is run inside a Docker container
+	if job.Image == "" {
+		task.Executor = s.ExecutorInfo
+	} else {
+		env := job.portsEnv(t.ports)
+		addIndexEnv(env, index)
+		task.Command = job.commandInfo(env)
+		task.Container = job.containerInfo()
+		task.HealthCheck = job.healthCheck(t.ports)
+	}
+
+	return task
+}
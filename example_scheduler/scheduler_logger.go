@@ -1,39 +1,151 @@
 package example_scheduler
 
 import (
+	"strings"
+
 	log "github.com/Sirupsen/logrus"
 	"github.com/mesos/mesos-go/mesosproto"
 	"github.com/mesos/mesos-go/scheduler"
 )
 
 func (s *ExampleScheduler) Registered(driver scheduler.SchedulerDriver, frameworkId *mesosproto.FrameworkID, masterInfo *mesosproto.MasterInfo) {
-	log.Infoln("Scheduler Registered with Master ", masterInfo)
+	log.WithFields(log.Fields{
+		"framework_id": frameworkId.GetValue(),
+		"master":       masterInfo.GetHostname(),
+	}).Infoln("Scheduler Registered with Master")
+
+	s.mutex.Lock()
+	s.reconnect(driver)
+	s.mutex.Unlock()
+
+	if s.FrameworkIDStore != nil {
+		if err := s.FrameworkIDStore.SaveFrameworkID(frameworkId.GetValue()); err != nil {
+			log.WithError(err).Errorln("Unable to save the FrameworkID, a restart will register a new framework")
+		}
+	}
 }
 
 func (s *ExampleScheduler) Reregistered(driver scheduler.SchedulerDriver, masterInfo *mesosproto.MasterInfo) {
-	log.Infoln("Scheduler Re-Registered with Master ", masterInfo)
+	log.WithField("master", masterInfo.GetHostname()).Infoln("Scheduler Re-Registered with Master")
+
+	s.mutex.Lock()
+	s.reconnect(driver)
+	s.mutex.Unlock()
 }
 
 func (s *ExampleScheduler) Disconnected(scheduler.SchedulerDriver) {
-	log.Infoln("Scheduler Disconnected")
+	log.Warnln("Scheduler Disconnected, launches paused until registered again")
+
+	s.mutex.Lock()
+	s.disconnect()
+	s.mutex.Unlock()
 }
 
 func (sched *ExampleScheduler) OfferRescinded(s scheduler.SchedulerDriver, id *mesosproto.OfferID) {
-	log.Infof("Offer '%v' rescinded.\n", *id)
+	log.WithField("offer_id", id.GetValue()).Infoln("Offer rescinded")
+
+	sched.mutex.Lock()
+	sched.removeOffer(id.GetValue())
+	sched.rescindLaunches(id.GetValue())
+	sched.mutex.Unlock()
 }
 
 func (sched *ExampleScheduler) FrameworkMessage(s scheduler.SchedulerDriver, exId *mesosproto.ExecutorID, slvId *mesosproto.SlaveID, msg string) {
-	log.Infof("Received framework message from executor '%v' on slave '%v': %s.\n", *exId, *slvId, msg)
+	log.WithFields(log.Fields{
+		"executor_id": exId.GetValue(),
+		"agent_id":    slvId.GetValue(),
+	}).Debugf("Received framework message: %s", msg)
+
+	sched.mutex.Lock()
+	sched.frameworkMessage(exId.GetValue(), slvId.GetValue(), msg)
+	sched.mutex.Unlock()
 }
 
 func (sched *ExampleScheduler) SlaveLost(s scheduler.SchedulerDriver, id *mesosproto.SlaveID) {
-	log.Infof("Slave '%v' lost.\n", *id)
+	log.WithField("agent_id", id.GetValue()).Warnln("Slave lost")
+
+	sched.mutex.Lock()
+	defer sched.mutex.Unlock()
+	sched.driver = s
+	sched.agentLost(id.GetValue())
+	sched.reviveIfNeeded(true)
 }
 
 func (sched *ExampleScheduler) ExecutorLost(s scheduler.SchedulerDriver, exId *mesosproto.ExecutorID, slvId *mesosproto.SlaveID, i int) {
-	log.Infof("Executor '%v' lost on slave '%v' with exit code: %v.\n", exId.GetValue(), slvId.GetValue(), i)
+	log.WithFields(log.Fields{
+		"executor_id": exId.GetValue(),
+		"agent_id":    slvId.GetValue(),
+		"exit_code":   i,
+	}).Warnln("Executor lost")
+
+	sched.mutex.Lock()
+	sched.executorLost(slvId.GetValue(), exId.GetValue(), i)
+	sched.mutex.Unlock()
+}
+
+//unrecoverableErrors are parts of the errors after which the framework
+//can't go on: the master removed it, another scheduler took it over, or it
+//isn't allowed to register
+var unrecoverableErrors = []string{
+	"Framework has been removed",
+	"Framework failed over",
+	"Not authorized",
+	"Authentication failed",
 }
 
+//isUnrecoverable reports if the error of the driver means the framework
+//can't go on
+func isUnrecoverable(err string) bool {
+	for _, e := range unrecoverableErrors {
+		if strings.Contains(err, e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//Error is called on the errors of the driver and of the master. The tasks
+//ending badly never get here, their restart policy handles them. Only the
+//unrecoverable errors abort the driver, stopping the scheduler
 func (sched *ExampleScheduler) Error(driver scheduler.SchedulerDriver, err string) {
-	log.Infoln("Scheduler received error:", err)
+	log.WithField("error", err).Errorln("Scheduler received error")
+
+	//The master forgets the framework once its failover timeout expires, so
+	//the saved ID can't be used again
+	if sched.FrameworkIDStore != nil && strings.Contains(err, "Framework has been removed") {
+		if err := sched.FrameworkIDStore.SaveFrameworkID(""); err != nil {
+			log.WithError(err).Errorln("Unable to remove the saved FrameworkID")
+		}
+	}
+
+	if !isUnrecoverable(err) {
+		log.Warnln("The scheduler goes on after the error")
+		return
+	}
+
+	log.Errorln("Unrecoverable error, aborting the driver")
+	sched.mutex.Lock()
+	sched.aborted = err
+	sched.mutex.Unlock()
+	if _, err := driver.Abort(); err != nil {
+		log.WithError(err).Errorln("Unable to abort the driver")
+	}
+}
+
+//offerLog returns a logger with the fields that identify an offer
+func offerLog(offer *mesosproto.Offer) *log.Entry {
+	return log.WithFields(log.Fields{
+		"offer_id": offer.Id.GetValue(),
+		"hostname": offer.GetHostname(),
+	})
+}
+
+//taskLog returns a logger with the fields that identify a task
+func taskLog(t *taskRecord) *log.Entry {
+	return log.WithFields(log.Fields{
+		"task_id":  t.id,
+		"job_id":   t.jobId,
+		"hostname": t.hostname,
+	})
 }
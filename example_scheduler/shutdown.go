@@ -0,0 +1,65 @@
+package example_scheduler
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//shutdownPoll is how often Shutdown checks if the killed tasks are gone
+const shutdownPoll = 100 * time.Millisecond
+
+//Shutdown stops launching tasks: from now on every offer is declined. The
+//tasks are launched while holding the mutex, so once Shutdown holds it no
+//LaunchTasks call is in flight. With killTasks every running task is killed
+//and Shutdown waits up to timeout for them to end, or longer if the tasks
+//have a longer grace period to shut down, so the driver can be stopped
+//afterwards
+func (s *ExampleScheduler) Shutdown(killTasks bool, timeout time.Duration) {
+	s.mutex.Lock()
+	s.stopping = true
+	s.teardown = killTasks
+	if s.driver != nil {
+		s.auditAllOffers("shutting down")
+		s.releaseAllOffers(s.driver)
+	}
+
+	if !killTasks || s.driver == nil {
+		s.mutex.Unlock()
+		return
+	}
+
+	for _, t := range s.tasks {
+		if tasks.IsTerminal(t.State) {
+			continue
+		}
+		if err := s.kill(t, "shutdown of the framework"); err != nil {
+			taskLog(t).WithError(err).Errorln("Unable to kill the task")
+		}
+	}
+	if grace := s.longestKillGrace() + killGraceMargin; grace > timeout {
+		timeout = grace
+	}
+	s.mutex.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		s.mutex.Lock()
+		running := 0
+		for _, t := range s.tasks {
+			if !tasks.IsTerminal(t.State) {
+				running++
+			}
+		}
+		s.mutex.Unlock()
+
+		if running == 0 {
+			log.Infoln("Every task is killed")
+			return
+		}
+		time.Sleep(shutdownPoll)
+	}
+
+	log.Warnf("Some tasks are still running after %v", timeout)
+}
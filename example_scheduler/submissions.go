@@ -0,0 +1,93 @@
+package example_scheduler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/store"
+)
+
+//submissionsKept is how long the idempotency key of a submission is
+//remembered, the retries of a client coming after that submit the job
+//again
+const submissionsKept = 24 * time.Hour
+
+//ErrKeyReused is returned when an idempotency key already given to a
+//submission comes again with another spec
+var ErrKeyReused = errors.New("the idempotency key was already used to submit another spec")
+
+//specDigest returns the SHA-256 of the spec, in hexadecimal
+func specDigest(job *JobSpec) (string, error) {
+	spec, err := json.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(spec)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+//SubmitJobOnce submits the job like SubmitJob, unless the idempotency key
+//was already given with the same spec in the last day: then nothing is
+//submitted and it returns the job submitted then and true. The key given
+//with another spec is refused with ErrKeyReused, and ErrUnknownJob is
+//returned if the job submitted with it was removed since
+func (s *ExampleScheduler) SubmitJobOnce(job *JobSpec, key string) (*JobSpec, bool, error) {
+	//The digest is taken before the validation sets the defaults, so a
+	//retry of the same request has the same one
+	digest, err := specDigest(job)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := job.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.expireSubmissions()
+	if submission, ok := s.submissions[key]; ok {
+		if submission.Digest != digest {
+			return nil, false, ErrKeyReused
+		}
+		submitted := s.job(submission.JobID)
+		if submitted == nil {
+			return nil, false, ErrUnknownJob
+		}
+
+		log.WithField("job_id", submitted.ID).Infoln("Submission retried with the same idempotency key, the job is already submitted")
+		replayed := *submitted
+		return &replayed, true, nil
+	}
+
+	if err := s.submitJob(job); err != nil {
+		return nil, false, err
+	}
+
+	submission := &store.Submission{
+		Key:       key,
+		JobID:     job.ID,
+		Digest:    digest,
+		Submitted: time.Now(),
+	}
+	s.submissions[key] = submission
+	s.saveSubmission(submission)
+
+	return job, false, nil
+}
+
+//expireSubmissions forgets the idempotency keys of the submissions older
+//than submissionsKept. The caller must hold the mutex
+func (s *ExampleScheduler) expireSubmissions() {
+	for key, submission := range s.submissions {
+		if time.Since(submission.Submitted) > submissionsKept {
+			delete(s.submissions, key)
+			s.deleteSubmission(key)
+		}
+	}
+}
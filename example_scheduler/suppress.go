@@ -0,0 +1,53 @@
+package example_scheduler
+
+import (
+	log "github.com/Sirupsen/logrus"
+)
+
+//refuseSeconds returns the filter of the offers given back for the reason.
+//When no job needs resources the offers are suppressed: refused for long,
+//so the master stops sending offers we would only decline. The driver has
+//no call to suppress the offers, the long filters do it until ReviveOffers
+//clears them. While the scheduling is paused every offer is suppressed.
+//The caller must hold the mutex
+func (s *ExampleScheduler) refuseSeconds(reason declineReason) float64 {
+	if (reason == declineUnfit || reason == declineMismatch) && !s.needsResources() {
+		reason = declineIdle
+	}
+
+	switch reason {
+	case declineIdle:
+		if !s.suppressed && s.paused {
+			log.Infoln("Suppressing offers, the scheduling is paused")
+		} else if !s.suppressed {
+			log.Infoln("Suppressing offers, no job needs resources")
+		}
+		s.suppressed = true
+	case declineMismatch:
+		s.filtered = true
+	}
+
+	return s.Decline.refuseSeconds(reason)
+}
+
+//reviveIfNeeded revives the offers suppressed before when a job needs
+//resources again. The agents refused for not matching the jobs are only
+//revived with clearFilters, when the jobs or their tasks change, since
+//they still don't match otherwise. Nothing is revived while the scheduling
+//is paused. The caller must hold the mutex
+func (s *ExampleScheduler) reviveIfNeeded(clearFilters bool) {
+	if !s.suppressed && !(clearFilters && s.filtered) {
+		return
+	}
+	if s.driver == nil || s.disconnected || s.paused || !s.needsResources() {
+		return
+	}
+
+	log.Infoln("Reviving offers, there are instances to launch")
+	if _, err := s.driver.ReviveOffers(); err != nil {
+		log.WithError(err).Errorln("Unable to revive the offers")
+		return
+	}
+	s.suppressed = false
+	s.filtered = false
+}
@@ -0,0 +1,240 @@
+package example_scheduler
+
+import (
+	"time"
+
+	"github.com/mesos/mesos-go/mesosproto"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//taskRecord is what the scheduler knows about each task it launched
+type taskRecord struct {
+	id       string
+	jobId    string
+	hostname string
+	agentId  string
+	launched time.Time
+
+	//The state of the task and the transitions that led to it
+	tasks.Lifecycle
+
+	//The hostname and attributes of the agent, to evaluate the
+	//constraints of the job
+	fields map[string]string
+
+	//The resources of the task, 0 if it wasn't launched by this scheduler
+	//instance
+	cpus float64
+	mem  float64
+	disk float64
+	gpus float64
+
+	//The persistence ID of the volume of the task, if any
+	volume string
+
+	//killed is set when the kill was requested by us, so the TASK_KILLED
+	//update isn't treated as a failure. timedOut is set too if the task
+	//ran for longer than the max runtime of its job
+	killed   bool
+	timedOut bool
+
+	//When the last kill was sent and how many were, to retry the kills
+	//that are lost
+	killSent     time.Time
+	killAttempts int
+
+	//When the task became unreachable, zero if it is reachable. After the
+	//grace period it is replaced and stops counting as an instance
+	unreachableSince time.Time
+	replaced         bool
+
+	//The offers the task was launched with, until its first status
+	//update, and whether any of them was rescinded meanwhile. journaled
+	//is set when the launch was restored from the store after a restart
+	//without ever being confirmed
+	offerIds  []string
+	rescinded bool
+	journaled bool
+
+	//The last framework message of the executor about the task
+	message *TaskMessage
+
+	//sidecar is set for the tasks of a pod but the main one, which alone
+	//counts as the instance
+	sidecar bool
+
+	//The run of the cron job the task belongs to, 0 for the other jobs
+	run int
+
+	//The index of the task in its indexed job
+	index int
+
+	//The result of the last health check of the task, nil if it has no
+	//health check or it didn't run yet
+	healthy *bool
+
+	//The host ports of the task, in the order of the ports of its job
+	ports []uint64
+
+	//The version of the spec of its job the task was launched with
+	version int
+
+	//ready is set once the task passed the readiness check of its job.
+	//checkingReadiness is set while a check runs, and readinessChecked is
+	//when the last one ended
+	ready             bool
+	checkingReadiness bool
+	readinessChecked  time.Time
+}
+
+//JobSummary is the state of the instances of a job exposed to the
+//operators
+type JobSummary struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Priority  int    `json:"priority"`
+	Instances int    `json:"instances"`
+
+	//The active tasks of the job, the ones running, ready and failing
+	//their health check, and the instances waiting to launch
+	Tasks     int `json:"tasks"`
+	Running   int `json:"running"`
+	Ready     int `json:"ready"`
+	Unhealthy int `json:"unhealthy"`
+	Pending   int `json:"pending"`
+}
+
+//Jobs returns the state of the instances of every job, in submission
+//order
+func (s *ExampleScheduler) Jobs() []JobSummary {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	summaries := make([]JobSummary, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		summary := JobSummary{
+			ID:        job.ID,
+			Type:      job.Type,
+			Priority:  job.Priority,
+			Instances: job.Instances,
+			Ready:     s.readyInstances(job),
+			Pending:   s.pendingInstances(job),
+		}
+		if summary.Type == "" {
+			summary.Type = JobService
+		}
+
+		for _, t := range s.activeTasks(job.ID) {
+			summary.Tasks++
+			if t.State == mesosproto.TaskState_TASK_RUNNING {
+				summary.Running++
+			}
+			if t.healthy != nil && !*t.healthy {
+				summary.Unhealthy++
+			}
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	return summaries
+}
+
+//TaskSummary is the information about a task exposed to the operators
+type TaskSummary struct {
+	ID       string    `json:"id"`
+	JobID    string    `json:"job_id"`
+	Hostname string    `json:"hostname"`
+	AgentID  string    `json:"agent_id"`
+	State    string    `json:"state"`
+	Launched time.Time `json:"launched"`
+
+	//The last status update, its message and the states of the task
+	Updated       time.Time          `json:"updated"`
+	StatusMessage string             `json:"status_message,omitempty"`
+	History       []tasks.Transition `json:"history"`
+
+	//TimedOut is set when the task was killed for running longer than
+	//the max runtime of its job
+	TimedOut bool `json:"timed_out,omitempty"`
+
+	//Healthy is the result of the last health check, nil without any
+	Healthy *bool `json:"healthy,omitempty"`
+
+	//Ready is set when the task is running and passed the readiness check
+	//of its job, if any
+	Ready bool `json:"ready"`
+
+	//The resources of the task, 0 if it wasn't launched by this scheduler
+	//instance
+	Cpus float64 `json:"cpus"`
+	Mem  float64 `json:"mem"`
+	Disk float64 `json:"disk"`
+	Gpus float64 `json:"gpus"`
+
+	//The last message of the executor about the task, if any
+	Message *TaskMessage `json:"message,omitempty"`
+}
+
+//record returns the record of a task, creating it if it's not tracked yet
+func (s *ExampleScheduler) record(taskId string) *taskRecord {
+	t, ok := s.tasks[taskId]
+	if !ok {
+		t = &taskRecord{
+			id:        taskId,
+			jobId:     jobOfTask(taskId),
+			Lifecycle: tasks.Lifecycle{State: mesosproto.TaskState_TASK_STAGING},
+			sidecar:   isSidecar(taskId),
+		}
+		s.tasks[taskId] = t
+	}
+
+	return t
+}
+
+//activeTasks returns the tasks of the job that are staging or running,
+//including the unreachable ones not replaced yet
+func (s *ExampleScheduler) activeTasks(jobId string) []*taskRecord {
+	var active []*taskRecord
+	for _, t := range s.tasks {
+		if t.jobId == jobId && !tasks.IsTerminal(t.State) && !t.replaced && !t.sidecar {
+			active = append(active, t)
+		}
+	}
+
+	return active
+}
+
+//pendingInstances is the number of tasks to launch to have all the
+//instances of the job running, not counting the instances that won't be
+//replaced by the restart policy
+func (s *ExampleScheduler) pendingInstances(job *JobSpec) int {
+	if state, _ := s.dependencyState(job); state != PipelineReady {
+		return 0
+	}
+	if job.Cron != nil {
+		return s.cronPending(job)
+	}
+
+	pending := job.Instances - len(s.instanceTasks(job))
+	if r, ok := s.restarts[job.ID]; ok {
+		pending -= r.done
+	}
+	if pending < 0 {
+		return 0
+	}
+
+	return pending
+}
+
+//hasPendingInstances reports if any job has tasks to launch besides the ones
+//planned in a dry run
+func (s *ExampleScheduler) hasPendingInstances(planned map[string]int) bool {
+	for _, job := range s.jobs {
+		if !s.inBackoff(job) && s.pendingInstances(job) > planned[job.ID] {
+			return true
+		}
+	}
+
+	return false
+}
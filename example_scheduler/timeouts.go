@@ -0,0 +1,85 @@
+package example_scheduler
+
+import (
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/mesos/mesos-go/mesosproto"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//killExpired kills the tasks running for longer than the max runtime of
+//their job. The caller must hold the mutex
+func (s *ExampleScheduler) killExpired() {
+	for _, t := range s.tasks {
+		if t.killed || t.launched.IsZero() || tasks.IsTerminal(t.State) {
+			continue
+		}
+
+		job := s.job(t.jobId)
+		if job == nil || job.MaxRuntime <= 0 ||
+			time.Since(t.launched) < time.Duration(job.MaxRuntime*float64(time.Second)) {
+			continue
+		}
+
+		tlog := taskLog(t).WithFields(log.Fields{
+			"launched":    t.launched,
+			"max_runtime": job.MaxRuntime,
+		})
+		tlog.Warnln("Task ran for longer than its max runtime, killing it")
+		if err := s.kill(t, "ran for longer than the max runtime of the job"); err != nil {
+			tlog.WithError(err).Errorln("Unable to kill the task")
+			continue
+		}
+		t.timedOut = true
+		s.saveTask(t)
+	}
+}
+
+//defaultLaunchTimeout is how long a task may take to reach TASK_RUNNING
+//when LaunchTimeout isn't set
+const defaultLaunchTimeout = 5 * time.Minute
+
+//launchTimeout returns how long a task may take to reach TASK_RUNNING
+func (s *ExampleScheduler) launchTimeout() time.Duration {
+	if s.LaunchTimeout > 0 {
+		return s.LaunchTimeout
+	}
+
+	return defaultLaunchTimeout
+}
+
+//killStuckLaunches kills the tasks that didn't reach TASK_RUNNING in time.
+//They stop counting as instances right away, so they are replaced even if
+//the kill never completes, and their agent is suspect: it is put in the
+//cool-down of the lost agents so the replacements go elsewhere. The caller
+//must hold the mutex
+func (s *ExampleScheduler) killStuckLaunches() {
+	for _, t := range s.tasks {
+		if t.killed || t.replaced || t.launched.IsZero() ||
+			(t.State != mesosproto.TaskState_TASK_STAGING && t.State != mesosproto.TaskState_TASK_STARTING) ||
+			time.Since(t.launched) < s.launchTimeout() {
+			continue
+		}
+
+		tlog := taskLog(t).WithFields(log.Fields{
+			"state":    t.State.String(),
+			"launched": t.launched,
+		})
+		tlog.Warnln("Task didn't start in time, killing it and replacing it on another agent")
+
+		if err := s.kill(t, "didn't start in time"); err != nil {
+			tlog.WithError(err).Errorln("Unable to kill the task")
+			continue
+		}
+		t.replaced = true
+
+		if t.agentId != "" {
+			until := s.coolDownAgent(t.agentId)
+			log.WithFields(log.Fields{
+				"agent_id": t.agentId,
+				"until":    until,
+			}).Warnln("Agent suspect, no task is placed on it until the end of its cool-down")
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package example_scheduler
+
+import (
+	"errors"
+	"path"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/golang/protobuf/proto"
+	"github.com/mesos/mesos-go/mesosproto"
+	"github.com/satori/go.uuid"
+	"minimal-mesos-go-framework/tasks"
+)
+
+//VolumeSpec is a persistent volume every task of a job gets. It is created
+//from reserved disk the first time an instance is launched and survives
+//the task, so its replacement is launched on the same agent with the same
+//data
+type VolumeSpec struct {
+	//Path of the volume inside the sandbox of the task, relative
+	ContainerPath string `json:"container_path"`
+
+	//Size of the volume in MB
+	Size float64 `json:"size"`
+}
+
+//Validate checks the path and the size of the volume
+func (v *VolumeSpec) Validate() error {
+	switch {
+	case v.ContainerPath == "" || path.IsAbs(v.ContainerPath):
+		return errors.New("the container path of the volume must be a relative path")
+	case v.Size <= 0:
+		return errors.New("the size of the volume must be greater than 0")
+	}
+
+	return nil
+}
+
+//volumeRecord is a persistent volume of a job the scheduler knows about,
+//because it created it or because it was offered
+type volumeRecord struct {
+	id      string
+	jobId   string
+	agentId string
+}
+
+//newVolumeID creates a unique persistence ID for a volume of the job. Like
+//the task IDs, it is prefixed by the job ID
+func (j *JobSpec) newVolumeID() string {
+	return j.ID + taskJobSeparator + "volume-" + uuid.NewV4().String()
+}
+
+//learnVolumes records the volumes of our jobs found in the offers of the
+//agent, for instance after a restart. The caller must hold the mutex
+func (s *ExampleScheduler) learnVolumes(agentId string, res *offerResources) {
+	for _, volume := range res.volumes {
+		id := volume.GetDisk().GetPersistence().GetId()
+		if _, ok := s.volumes[id]; !ok {
+			s.volumes[id] = &volumeRecord{id: id, jobId: jobOfTask(id), agentId: agentId}
+		}
+	}
+}
+
+//volumesOf returns the volumes of the job and the ones not used by any
+//active task. The caller must hold the mutex
+func (s *ExampleScheduler) volumesOf(job *JobSpec) (all, free []*volumeRecord) {
+	used := make(map[string]bool)
+	for _, t := range s.tasks {
+		if t.volume != "" && !tasks.IsTerminal(t.State) {
+			used[t.volume] = true
+		}
+	}
+
+	for _, v := range s.volumes {
+		if v.jobId != job.ID {
+			continue
+		}
+		all = append(all, v)
+		if !used[v.id] {
+			free = append(free, v)
+		}
+	}
+
+	return all, free
+}
+
+//fitsVolume reports if the agent can give a task of the job its volume: a
+//free volume of the job is offered, or there is none anywhere and a new one
+//can be created from the disk of the offers. The caller must hold the mutex
+func (s *ExampleScheduler) fitsVolume(agent *agentOffers, job *JobSpec) bool {
+	if job.Volume == nil {
+		return true
+	}
+
+	res := s.resOf(agent, job)
+	if res.volumeOf(job) != nil {
+		return true
+	}
+
+	//The replacements wait for the offers of the agents holding the free
+	//volumes instead of creating new ones
+	all, free := s.volumesOf(job)
+	if len(free) > 0 || len(all) >= job.Instances {
+		return false
+	}
+
+	//The volume is taken before the disk of the task itself, from a
+	//single part of the reserved disk or from unreserved disk to reserve
+	if res.scalar("disk")-job.Volume.Size < job.Disk {
+		return false
+	}
+
+	return res.scalarPartOf("disk", job.Volume.Size, true) != nil ||
+		(s.Reservation != nil && res.scalarPartOf("disk", job.Volume.Size, false) != nil)
+}
+
+//takeVolume returns the volume resource of a task of the job, reusing a
+//free volume of the offers or adding to the agent the operations that
+//create a new one. The caller must hold the mutex
+func (s *ExampleScheduler) takeVolume(agent *agentOffers, job *JobSpec) *mesosproto.Resource {
+	res := s.resOf(agent, job)
+	if volume := res.volumeOf(job); volume != nil {
+		res.removeVolume(volume)
+		return volume
+	}
+
+	var disk *mesosproto.Resource
+	if part := res.scalarPartOf("disk", job.Volume.Size, true); part != nil {
+		disk = part.resource(job.Volume.Size)
+		part.value -= job.Volume.Size
+	} else {
+		part = res.scalarPartOf("disk", job.Volume.Size, false)
+		part.value -= job.Volume.Size
+		disk = s.Reservation.reserve(part.resource(job.Volume.Size))
+		agent.reserve = append(agent.reserve, disk)
+	}
+
+	id := job.newVolumeID()
+	volume := proto.Clone(disk).(*mesosproto.Resource)
+	volume.Disk = &mesosproto.Resource_DiskInfo{
+		Persistence: &mesosproto.Resource_DiskInfo_Persistence{Id: proto.String(id)},
+		Volume: &mesosproto.Volume{
+			ContainerPath: proto.String(job.Volume.ContainerPath),
+			Mode:          mesosproto.Volume_RW.Enum(),
+		},
+	}
+	if s.Reservation != nil && s.Reservation.Principal != "" {
+		volume.Disk.Persistence.Principal = proto.String(s.Reservation.Principal)
+	}
+
+	agent.create = append(agent.create, volume)
+	if !s.DryRun {
+		s.volumes[id] = &volumeRecord{id: id, jobId: job.ID, agentId: agent.id}
+	}
+	agentLog(agent.offers).WithFields(log.Fields{"job_id": job.ID, "volume_id": id}).Infoln("Creating persistent volume")
+
+	return volume
+}
+
+//excessVolumes returns the volumes in the offers of jobs that have more
+//volumes than instances, after scaling down. The caller must hold the
+//mutex
+func (s *ExampleScheduler) excessVolumes(res *offerResources) []*mesosproto.Resource {
+	var excess []*mesosproto.Resource
+	destroyed := make(map[string]int)
+
+	for _, volume := range res.volumes {
+		id := volume.GetDisk().GetPersistence().GetId()
+		job := s.job(jobOfTask(id))
+		if job == nil {
+			//It may belong to a job not submitted again after a restart
+			continue
+		}
+
+		all, _ := s.volumesOf(job)
+		if len(all)-destroyed[job.ID] > job.Instances {
+			excess = append(excess, volume)
+			destroyed[job.ID]++
+		}
+	}
+
+	return excess
+}
+
+//volumeOfTask returns the persistence ID of the volume of the task, if any
+func volumeOfTask(task *mesosproto.TaskInfo) string {
+	for _, resource := range task.Resources {
+		if id := resource.GetDisk().GetPersistence().GetId(); id != "" {
+			return id
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,115 @@
+package ha
+
+import (
+	"context"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/clientv3/concurrency"
+)
+
+//etcdDialTimeout bounds how long connecting to etcd takes
+const etcdDialTimeout = 5 * time.Second
+
+//EtcdElection elects a single leader among several scheduler instances with
+//the election of etcd: every instance holds a lease, kept alive while it
+//runs, and the instance whose key was created first under the election
+//prefix is the leader. When its lease expires its key is gone and the next
+//one takes over
+type EtcdElection struct {
+	client   *clientv3.Client
+	session  *concurrency.Session
+	election *concurrency.Election
+
+	//Root prefix of the framework in etcd
+	prefix string
+
+	//Value of our key, so the others know who is the leader
+	id string
+}
+
+//NewEtcdElection connects to the etcd servers of url, an
+//etcd://host1:port1,host2:port2/prefix URL. id identifies this instance,
+//usually its hostname and API address
+func NewEtcdElection(url, id string) (*EtcdElection, error) {
+	endpoints, prefix, err := splitURL("etcd", url)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: etcdDialTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	//The lease of the session expires if we can't keep it alive for the
+	//session timeout, as the ZooKeeper session does
+	session, err := concurrency.NewSession(client, concurrency.WithTTL(int(sessionTimeout.Seconds())))
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &EtcdElection{
+		client:   client,
+		session:  session,
+		election: concurrency.NewElection(session, prefix+"/election"),
+		prefix:   prefix,
+		id:       id,
+	}, nil
+}
+
+//Client returns the etcd client of the election, to share it with the
+//state store
+func (e *EtcdElection) Client() *clientv3.Client {
+	return e.client
+}
+
+//Prefix returns the root prefix of the framework in etcd
+func (e *EtcdElection) Prefix() string {
+	return e.prefix
+}
+
+//Campaign implements Candidate
+func (e *EtcdElection) Campaign() error {
+	if leader, err := e.election.Leader(context.Background()); err == nil && len(leader.Kvs) > 0 {
+		log.WithField("leader", string(leader.Kvs[0].Value)).Infoln("Waiting as standby")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-e.session.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := e.election.Campaign(ctx, e.id); err != nil {
+		return err
+	}
+
+	log.WithField("id", e.id).Infoln("Elected as leader")
+	return nil
+}
+
+//Lost implements Candidate. It is closed when the lease expires, our key is
+//gone with it
+func (e *EtcdElection) Lost() <-chan struct{} {
+	return e.session.Done()
+}
+
+//Resign implements Candidate
+func (e *EtcdElection) Resign() {
+	ctx, cancel := context.WithTimeout(context.Background(), etcdDialTimeout)
+	defer cancel()
+
+	e.election.Resign(ctx)
+	e.session.Close()
+	e.client.Close()
+}
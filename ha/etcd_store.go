@@ -0,0 +1,379 @@
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"minimal-mesos-go-framework/store"
+)
+
+//EtcdStore keeps the state of the scheduler in etcd, next to the election:
+//the FrameworkID, and a key for each job, task, deployment and submission,
+//named after the escaped ID of the job or the task or the escaped
+//idempotency key, with its JSON, and the pause of the scheduling at
+//maintenance. Every instance, the standbys too, watches the keys and keeps
+//a copy of the state up to date, so the standby that takes over already
+//has it. It implements store.Store
+type EtcdStore struct {
+	client *clientv3.Client
+	prefix string
+
+	//mutex guards the copy of the state, by key, and the revision of etcd
+	//it reflects
+	mutex    sync.Mutex
+	kvs      map[string]*mvccpb.KeyValue
+	revision int64
+}
+
+//NewEtcdStore creates a store under the root prefix of the election and
+//starts watching it
+func NewEtcdStore(e *EtcdElection) (*EtcdStore, error) {
+	s := &EtcdStore{
+		client: e.Client(),
+		prefix: e.Prefix() + "/state/",
+		kvs:    make(map[string]*mvccpb.KeyValue),
+	}
+
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+	go s.watch()
+
+	return s, nil
+}
+
+//LoadFrameworkID implements store.FrameworkIDStore
+func (s *EtcdStore) LoadFrameworkID() (string, error) {
+	if err := s.sync(); err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if kv, ok := s.kvs[s.prefix+"framework_id"]; ok {
+		return string(kv.Value), nil
+	}
+
+	return "", nil
+}
+
+//SaveFrameworkID implements store.FrameworkIDStore
+func (s *EtcdStore) SaveFrameworkID(id string) error {
+	if id == "" {
+		return s.delete(s.prefix + "framework_id")
+	}
+
+	return s.put(s.prefix+"framework_id", []byte(id))
+}
+
+//SaveJob implements store.Store
+func (s *EtcdStore) SaveJob(id string, spec []byte) error {
+	return s.put(s.prefix+"jobs/"+url.PathEscape(id), spec)
+}
+
+//LoadJobs implements store.Store. The jobs are sorted by the creation of
+//their keys, so in the order they were first saved
+func (s *EtcdStore) LoadJobs() ([][]byte, error) {
+	kvs, err := s.load("jobs/")
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].CreateRevision < kvs[j].CreateRevision })
+
+	specs := make([][]byte, 0, len(kvs))
+	for _, kv := range kvs {
+		specs = append(specs, kv.Value)
+	}
+
+	return specs, nil
+}
+
+//SaveTask implements store.Store
+func (s *EtcdStore) SaveTask(task *store.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	return s.put(s.prefix+"tasks/"+url.PathEscape(task.ID), data)
+}
+
+//Ping implements store.Pinger
+func (s *EtcdStore) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), etcdDialTimeout)
+	defer cancel()
+
+	_, err := s.client.Get(ctx, s.prefix)
+	return err
+}
+
+//DeleteJob implements store.Store
+func (s *EtcdStore) DeleteJob(id string) error {
+	if err := s.delete(s.prefix + "jobs/" + url.PathEscape(id)); err != nil {
+		return err
+	}
+
+	return s.delete(s.prefix + "deployments/" + url.PathEscape(id))
+}
+
+//DeleteTask implements store.Store
+func (s *EtcdStore) DeleteTask(id string) error {
+	return s.delete(s.prefix + "tasks/" + url.PathEscape(id))
+}
+
+//LoadTasks implements store.Store. The tasks are sorted by ID
+func (s *EtcdStore) LoadTasks() ([]*store.Task, error) {
+	kvs, err := s.load("tasks/")
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*store.Task, 0, len(kvs))
+	for _, kv := range kvs {
+		task := &store.Task{}
+		if err := json.Unmarshal(kv.Value, task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	return tasks, nil
+}
+
+//SaveDeployment implements store.Store
+func (s *EtcdStore) SaveDeployment(deployment *store.Deployment) error {
+	data, err := json.Marshal(deployment)
+	if err != nil {
+		return err
+	}
+
+	return s.put(s.prefix+"deployments/"+url.PathEscape(deployment.JobID), data)
+}
+
+//LoadDeployments implements store.Store. The deployments are sorted by job
+//ID
+func (s *EtcdStore) LoadDeployments() ([]*store.Deployment, error) {
+	kvs, err := s.load("deployments/")
+	if err != nil {
+		return nil, err
+	}
+
+	deployments := make([]*store.Deployment, 0, len(kvs))
+	for _, kv := range kvs {
+		deployment := &store.Deployment{}
+		if err := json.Unmarshal(kv.Value, deployment); err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, deployment)
+	}
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].JobID < deployments[j].JobID })
+
+	return deployments, nil
+}
+
+//SaveSubmission implements store.Store
+func (s *EtcdStore) SaveSubmission(submission *store.Submission) error {
+	data, err := json.Marshal(submission)
+	if err != nil {
+		return err
+	}
+
+	return s.put(s.prefix+"submissions/"+url.PathEscape(submission.Key), data)
+}
+
+//DeleteSubmission implements store.Store
+func (s *EtcdStore) DeleteSubmission(key string) error {
+	return s.delete(s.prefix + "submissions/" + url.PathEscape(key))
+}
+
+//LoadSubmissions implements store.Store. The submissions are sorted by key
+func (s *EtcdStore) LoadSubmissions() ([]*store.Submission, error) {
+	kvs, err := s.load("submissions/")
+	if err != nil {
+		return nil, err
+	}
+
+	submissions := make([]*store.Submission, 0, len(kvs))
+	for _, kv := range kvs {
+		submission := &store.Submission{}
+		if err := json.Unmarshal(kv.Value, submission); err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, submission)
+	}
+	sort.Slice(submissions, func(i, j int) bool { return submissions[i].Key < submissions[j].Key })
+
+	return submissions, nil
+}
+
+//SaveMaintenance implements store.Store
+func (s *EtcdStore) SaveMaintenance(maintenance *store.Maintenance) error {
+	if maintenance == nil {
+		return s.delete(s.prefix + "maintenance")
+	}
+
+	data, err := json.Marshal(maintenance)
+	if err != nil {
+		return err
+	}
+
+	return s.put(s.prefix+"maintenance", data)
+}
+
+//LoadMaintenance implements store.Store
+func (s *EtcdStore) LoadMaintenance() (*store.Maintenance, error) {
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	kv, ok := s.kvs[s.prefix+"maintenance"]
+	if !ok {
+		return nil, nil
+	}
+
+	maintenance := &store.Maintenance{}
+	return maintenance, json.Unmarshal(kv.Value, maintenance)
+}
+
+//load returns the keys of the copy of the state under the part of the
+//prefix, after bringing it up to date, so a new leader never misses the
+//last changes of the old one
+func (s *EtcdStore) load(part string) ([]*mvccpb.KeyValue, error) {
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var kvs []*mvccpb.KeyValue
+	for key, kv := range s.kvs {
+		if strings.HasPrefix(key, s.prefix+part) {
+			kvs = append(kvs, kv)
+		}
+	}
+
+	return kvs, nil
+}
+
+//put saves the key in etcd and in the copy
+func (s *EtcdStore) put(key string, value []byte) error {
+	resp, err := s.client.Put(context.Background(), key, string(value))
+	if err != nil {
+		return err
+	}
+
+	s.apply(resp.Header.Revision, &mvccpb.Event{
+		Type: mvccpb.PUT,
+		Kv:   &mvccpb.KeyValue{Key: []byte(key), Value: value, ModRevision: resp.Header.Revision},
+	})
+	return nil
+}
+
+//delete removes the key from etcd and from the copy. Deleting a key that
+//doesn't exist isn't an error
+func (s *EtcdStore) delete(key string) error {
+	resp, err := s.client.Delete(context.Background(), key)
+	if err != nil {
+		return err
+	}
+
+	s.apply(resp.Header.Revision, &mvccpb.Event{
+		Type: mvccpb.DELETE,
+		Kv:   &mvccpb.KeyValue{Key: []byte(key), ModRevision: resp.Header.Revision},
+	})
+	return nil
+}
+
+//sync replaces the copy of the state with the keys in etcd
+func (s *EtcdStore) sync() error {
+	resp, err := s.client.Get(context.Background(), s.prefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+
+	kvs := make(map[string]*mvccpb.KeyValue, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		kvs[string(kv.Key)] = kv
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.kvs, s.revision = kvs, resp.Header.Revision
+	return nil
+}
+
+//apply applies a change to the copy of the state, unless the copy is
+//already more recent. A put keeps the creation revision of the key, which
+//the changes made by this instance don't know
+func (s *EtcdStore) apply(revision int64, event *mvccpb.Event) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := string(event.Kv.Key)
+	current, ok := s.kvs[key]
+	if ok && current.ModRevision >= event.Kv.ModRevision {
+		return
+	}
+
+	switch event.Type {
+	case mvccpb.PUT:
+		kv := *event.Kv
+		if kv.CreateRevision == 0 {
+			kv.CreateRevision = kv.ModRevision
+			if ok {
+				kv.CreateRevision = current.CreateRevision
+			}
+		}
+		s.kvs[key] = &kv
+	case mvccpb.DELETE:
+		delete(s.kvs, key)
+	}
+	if revision > s.revision {
+		s.revision = revision
+	}
+}
+
+//watch keeps the copy of the state up to date with the changes made by the
+//leader, starting again from a full copy whenever the watch breaks, until
+//the client is closed
+func (s *EtcdStore) watch() {
+	for {
+		s.mutex.Lock()
+		revision := s.revision
+		s.mutex.Unlock()
+
+		for resp := range s.client.Watch(context.Background(), s.prefix, clientv3.WithPrefix(), clientv3.WithRev(revision+1)) {
+			if err := resp.Err(); err != nil {
+				log.WithError(err).Warnln("The watch of the state in etcd broke")
+				break
+			}
+			for _, event := range resp.Events {
+				log.WithFields(log.Fields{
+					"key":    strings.TrimPrefix(string(event.Kv.Key), s.prefix),
+					"change": event.Type.String(),
+				}).Debugln("State changed in etcd")
+				s.apply(resp.Header.Revision, event)
+			}
+		}
+
+		if err := s.sync(); err != nil {
+			//The client was closed, or etcd is gone with our lease
+			log.WithError(err).Debugln("Stopped watching the state in etcd")
+			return
+		}
+	}
+}
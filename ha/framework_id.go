@@ -0,0 +1,45 @@
+package ha
+
+import (
+	"github.com/samuel/go-zookeeper/zk"
+)
+
+//FrameworkIDStore keeps the FrameworkID in ZooKeeper, so the standby that
+//takes over fails over to the framework registered by the old leader. It
+//implements store.FrameworkIDStore
+type FrameworkIDStore struct {
+	conn *zk.Conn
+	path string
+}
+
+//NewFrameworkIDStore creates a store that saves the FrameworkID under the
+//root path of the election
+func NewFrameworkIDStore(e *Election) *FrameworkIDStore {
+	return &FrameworkIDStore{conn: e.Conn(), path: e.Path() + "/framework_id"}
+}
+
+//LoadFrameworkID implements store.FrameworkIDStore
+func (s *FrameworkIDStore) LoadFrameworkID() (string, error) {
+	data, _, err := s.conn.Get(s.path)
+	if err == zk.ErrNoNode {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+//SaveFrameworkID implements store.FrameworkIDStore
+func (s *FrameworkIDStore) SaveFrameworkID(id string) error {
+	if id == "" {
+		err := s.conn.Delete(s.path, -1)
+		if err == zk.ErrNoNode {
+			return nil
+		}
+		return err
+	}
+
+	return put(s.conn, s.path, []byte(id))
+}
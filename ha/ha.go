@@ -0,0 +1,218 @@
+package ha
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/samuel/go-zookeeper/zk"
+)
+
+//sessionTimeout is the ZooKeeper session timeout. A leader that can't reach
+//ZooKeeper for this long loses the leadership
+const sessionTimeout = 10 * time.Second
+
+//Candidate is an instance of the scheduler running for the leadership
+type Candidate interface {
+	//Campaign blocks until this instance is the leader
+	Campaign() error
+
+	//Lost is closed when the leadership is lost, a leader must stop acting
+	//as such
+	Lost() <-chan struct{}
+
+	//Resign gives up the leadership, or the candidacy, and closes the
+	//connection
+	Resign()
+}
+
+//candidatePrefix is the name of the ephemeral sequential nodes created by
+//every scheduler instance under the election path
+const candidatePrefix = "candidate-"
+
+//Election elects a single leader among several scheduler instances using the
+//ZooKeeper leader election recipe: every instance creates an ephemeral
+//sequential node and the one with the lowest sequence is the leader. The
+//others watch the node just before theirs and take over when it's gone
+type Election struct {
+	conn *zk.Conn
+
+	//Root path of the framework in ZooKeeper
+	path string
+
+	//Data of our candidate node, so the others know who is the leader
+	id string
+
+	node string
+
+	lostOnce sync.Once
+	lost     chan struct{}
+}
+
+//ParseURL splits a zk://host1:port1,host2:port2/path URL in the list of
+//servers and the path
+func ParseURL(url string) ([]string, string, error) {
+	return splitURL("zk", url)
+}
+
+//splitURL splits a scheme://host1:port1,host2:port2/path URL in the list of
+//servers and the path
+func splitURL(scheme, url string) ([]string, string, error) {
+	if !strings.HasPrefix(url, scheme+"://") {
+		return nil, "", fmt.Errorf("%s is not a %s:// URL", url, scheme)
+	}
+
+	hosts := strings.TrimPrefix(url, scheme+"://")
+	zkPath := "/"
+	if i := strings.Index(hosts, "/"); i >= 0 {
+		hosts, zkPath = hosts[:i], hosts[i:]
+	}
+
+	if hosts == "" {
+		return nil, "", fmt.Errorf("%s has no servers", url)
+	}
+
+	return strings.Split(hosts, ","), path.Clean(zkPath), nil
+}
+
+//NewElection connects to the ZooKeeper servers of url. id identifies this
+//instance, usually its hostname and API address
+func NewElection(url, id string) (*Election, error) {
+	servers, zkPath, err := ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, events, err := zk.Connect(servers, sessionTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Election{
+		conn: conn,
+		path: zkPath,
+		id:   id,
+		lost: make(chan struct{}),
+	}
+
+	go e.watchSession(events)
+
+	if err := ensurePath(conn, e.electionPath()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return e, nil
+}
+
+//Conn returns the ZooKeeper connection of the election, to share it with
+//other users like the FrameworkID store
+func (e *Election) Conn() *zk.Conn {
+	return e.conn
+}
+
+//Path returns the root path of the framework in ZooKeeper
+func (e *Election) Path() string {
+	return e.path
+}
+
+//Campaign blocks until this instance is the leader
+func (e *Election) Campaign() error {
+	node, err := e.conn.Create(e.electionPath()+"/"+candidatePrefix, []byte(e.id),
+		zk.FlagEphemeral|zk.FlagSequence, zk.WorldACL(zk.PermAll))
+	if err != nil {
+		return err
+	}
+	e.node = path.Base(node)
+
+	for {
+		candidates, _, err := e.conn.Children(e.electionPath())
+		if err != nil {
+			return err
+		}
+
+		//The sequence numbers have a fixed width, sorting the names sorts
+		//them by sequence
+		sort.Strings(candidates)
+
+		i := sort.SearchStrings(candidates, e.node)
+		if i == len(candidates) || candidates[i] != e.node {
+			return fmt.Errorf("our candidate node %s is gone", e.node)
+		}
+
+		if i == 0 {
+			log.WithField("id", e.id).Infoln("Elected as leader")
+			return nil
+		}
+
+		//Watch only our predecessor to avoid waking up every standby when
+		//the leader goes away
+		predecessor := e.electionPath() + "/" + candidates[i-1]
+		if data, _, err := e.conn.Get(e.electionPath() + "/" + candidates[0]); err == nil {
+			log.WithField("leader", string(data)).Infoln("Waiting as standby")
+		}
+
+		exists, _, events, err := e.conn.ExistsW(predecessor)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+
+		select {
+		case <-events:
+		case <-e.lost:
+			return fmt.Errorf("ZooKeeper session expired during the election")
+		}
+	}
+}
+
+//Lost implements Candidate. It is closed when the ZooKeeper session
+//expires, our candidate node is gone with the session
+func (e *Election) Lost() <-chan struct{} {
+	return e.lost
+}
+
+//Resign implements Candidate
+func (e *Election) Resign() {
+	if e.node != "" {
+		e.conn.Delete(e.electionPath()+"/"+e.node, -1)
+	}
+	e.conn.Close()
+}
+
+func (e *Election) electionPath() string {
+	return e.path + "/election"
+}
+
+func (e *Election) watchSession(events <-chan zk.Event) {
+	for event := range events {
+		if event.Type == zk.EventSession && event.State == zk.StateExpired {
+			log.Errorln("ZooKeeper session expired")
+			e.lostOnce.Do(func() { close(e.lost) })
+		}
+	}
+}
+
+//ensurePath creates every missing node of p
+func ensurePath(conn *zk.Conn, p string) error {
+	current := ""
+	for _, part := range strings.Split(strings.Trim(p, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		current += "/" + part
+
+		_, err := conn.Create(current, nil, 0, zk.WorldACL(zk.PermAll))
+		if err != nil && err != zk.ErrNodeExists {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,289 @@
+package ha
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+
+	"github.com/samuel/go-zookeeper/zk"
+	"minimal-mesos-go-framework/store"
+)
+
+//Store keeps the state of the scheduler in ZooKeeper, next to the election,
+//so the standby that takes over knows the jobs, the tasks and the
+//deployments of the old leader. Each job, task, deployment and submission
+//is a node, named after the escaped ID of the job or the task or the
+//escaped idempotency key, with its JSON, and the pause of the scheduling
+//is the maintenance node. It implements store.Store
+type Store struct {
+	*FrameworkIDStore
+	conn *zk.Conn
+	path string
+}
+
+//NewStore creates a store under the root path of the election
+func NewStore(e *Election) (*Store, error) {
+	s := &Store{
+		FrameworkIDStore: NewFrameworkIDStore(e),
+		conn:             e.Conn(),
+		path:             e.Path(),
+	}
+
+	for _, p := range []string{s.jobsPath(), s.tasksPath(), s.deploymentsPath(), s.submissionsPath()} {
+		if err := ensurePath(s.conn, p); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+//SaveJob implements store.Store
+func (s *Store) SaveJob(id string, spec []byte) error {
+	return put(s.conn, s.jobsPath()+"/"+url.PathEscape(id), spec)
+}
+
+//LoadJobs implements store.Store. The jobs are sorted by the creation of
+//their nodes, so in the order they were first saved
+func (s *Store) LoadJobs() ([][]byte, error) {
+	names, _, err := s.conn.Children(s.jobsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	type node struct {
+		data  []byte
+		czxid int64
+	}
+	nodes := make([]node, 0, len(names))
+	for _, name := range names {
+		data, stat, err := s.conn.Get(s.jobsPath() + "/" + name)
+		if err == zk.ErrNoNode {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node{data: data, czxid: stat.Czxid})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].czxid < nodes[j].czxid })
+
+	specs := make([][]byte, 0, len(nodes))
+	for _, n := range nodes {
+		specs = append(specs, n.data)
+	}
+
+	return specs, nil
+}
+
+//SaveTask implements store.Store
+func (s *Store) SaveTask(task *store.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+
+	return put(s.conn, s.taskPath(task.ID), data)
+}
+
+//Ping implements store.Pinger. The store is reachable while the session
+//with ZooKeeper is up
+func (s *Store) Ping() error {
+	if s.conn.State() != zk.StateHasSession {
+		return errors.New("no session with ZooKeeper")
+	}
+
+	return nil
+}
+
+//DeleteJob implements store.Store
+func (s *Store) DeleteJob(id string) error {
+	for _, p := range []string{s.jobsPath(), s.deploymentsPath()} {
+		err := s.conn.Delete(p+"/"+url.PathEscape(id), -1)
+		if err != nil && err != zk.ErrNoNode {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//DeleteTask implements store.Store
+func (s *Store) DeleteTask(id string) error {
+	err := s.conn.Delete(s.taskPath(id), -1)
+	if err == zk.ErrNoNode {
+		return nil
+	}
+
+	return err
+}
+
+//LoadTasks implements store.Store. The tasks are sorted by ID
+func (s *Store) LoadTasks() ([]*store.Task, error) {
+	var tasks []*store.Task
+	err := s.loadEach(s.tasksPath(), func(data []byte) error {
+		task := &store.Task{}
+		tasks = append(tasks, task)
+		return json.Unmarshal(data, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	return tasks, nil
+}
+
+//SaveDeployment implements store.Store
+func (s *Store) SaveDeployment(deployment *store.Deployment) error {
+	data, err := json.Marshal(deployment)
+	if err != nil {
+		return err
+	}
+
+	return put(s.conn, s.deploymentsPath()+"/"+url.PathEscape(deployment.JobID), data)
+}
+
+//LoadDeployments implements store.Store. The deployments are sorted by job
+//ID
+func (s *Store) LoadDeployments() ([]*store.Deployment, error) {
+	var deployments []*store.Deployment
+	err := s.loadEach(s.deploymentsPath(), func(data []byte) error {
+		deployment := &store.Deployment{}
+		deployments = append(deployments, deployment)
+		return json.Unmarshal(data, deployment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].JobID < deployments[j].JobID })
+
+	return deployments, nil
+}
+
+//SaveSubmission implements store.Store
+func (s *Store) SaveSubmission(submission *store.Submission) error {
+	data, err := json.Marshal(submission)
+	if err != nil {
+		return err
+	}
+
+	return put(s.conn, s.submissionsPath()+"/"+url.PathEscape(submission.Key), data)
+}
+
+//DeleteSubmission implements store.Store
+func (s *Store) DeleteSubmission(key string) error {
+	err := s.conn.Delete(s.submissionsPath()+"/"+url.PathEscape(key), -1)
+	if err == zk.ErrNoNode {
+		return nil
+	}
+
+	return err
+}
+
+//LoadSubmissions implements store.Store. The submissions are sorted by key
+func (s *Store) LoadSubmissions() ([]*store.Submission, error) {
+	var submissions []*store.Submission
+	err := s.loadEach(s.submissionsPath(), func(data []byte) error {
+		submission := &store.Submission{}
+		submissions = append(submissions, submission)
+		return json.Unmarshal(data, submission)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(submissions, func(i, j int) bool { return submissions[i].Key < submissions[j].Key })
+
+	return submissions, nil
+}
+
+//SaveMaintenance implements store.Store
+func (s *Store) SaveMaintenance(maintenance *store.Maintenance) error {
+	if maintenance == nil {
+		err := s.conn.Delete(s.maintenancePath(), -1)
+		if err == zk.ErrNoNode {
+			return nil
+		}
+		return err
+	}
+
+	data, err := json.Marshal(maintenance)
+	if err != nil {
+		return err
+	}
+
+	return put(s.conn, s.maintenancePath(), data)
+}
+
+//LoadMaintenance implements store.Store
+func (s *Store) LoadMaintenance() (*store.Maintenance, error) {
+	data, _, err := s.conn.Get(s.maintenancePath())
+	if err == zk.ErrNoNode {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	maintenance := &store.Maintenance{}
+	return maintenance, json.Unmarshal(data, maintenance)
+}
+
+//loadEach calls load with the data of each child of the node at p. The
+//children deleted meanwhile are skipped
+func (s *Store) loadEach(p string, load func(data []byte) error) error {
+	names, _, err := s.conn.Children(p)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		data, _, err := s.conn.Get(p + "/" + name)
+		if err == zk.ErrNoNode {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := load(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) jobsPath() string {
+	return s.path + "/jobs"
+}
+
+func (s *Store) tasksPath() string {
+	return s.path + "/tasks"
+}
+
+func (s *Store) deploymentsPath() string {
+	return s.path + "/deployments"
+}
+
+func (s *Store) submissionsPath() string {
+	return s.path + "/submissions"
+}
+
+func (s *Store) maintenancePath() string {
+	return s.path + "/maintenance"
+}
+
+func (s *Store) taskPath(id string) string {
+	return s.tasksPath() + "/" + url.PathEscape(id)
+}
+
+//put sets the data of the node at p, creating it if it doesn't exist
+func put(conn *zk.Conn, p string, data []byte) error {
+	_, err := conn.Set(p, data, -1)
+	if err == zk.ErrNoNode {
+		_, err = conn.Create(p, data, 0, zk.WorldACL(zk.PermAll))
+	}
+
+	return err
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"strings"
+)
+
+//Metric is a value measured by the scheduler. Gauges are the current value
+//of something, counters a total since the scheduler started
+type Metric struct {
+	Name    string
+	Value   float64
+	Counter bool
+}
+
+//Source gives the current value of the metrics
+type Source interface {
+	Metrics() []Metric
+}
+
+//Name joins the parts of the name of a metric with dots, replacing in each
+//part the characters the sinks don't take, like the dots of a job ID
+func Name(parts ...string) string {
+	clean := make([]string, len(parts))
+	for i, part := range parts {
+		clean[i] = strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+				return r
+			}
+			return '_'
+		}, part)
+	}
+
+	return strings.Join(clean, ".")
+}
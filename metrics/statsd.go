@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+//maxPacket is the most bytes sent in a single datagram, so the metrics
+//aren't fragmented on the usual networks
+const maxPacket = 1432
+
+//StatsD pushes the metrics to a StatsD server over UDP. The gauges are
+//sent as they are and the counters as the increase since the last flush
+type StatsD struct {
+	conn     net.Conn
+	prefix   string
+	interval time.Duration
+
+	//The value of each counter on the last flush
+	last map[string]float64
+}
+
+//NewStatsD creates the sink sending to the address, host:port, with the
+//prefix in front of the name of every metric
+func NewStatsD(address, prefix string, interval time.Duration) (*StatsD, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatsD{
+		conn:     conn,
+		prefix:   prefix,
+		interval: interval,
+		last:     make(map[string]float64),
+	}, nil
+}
+
+//Run flushes the metrics of the source every interval. It never returns,
+//run it in its own goroutine
+func (s *StatsD) Run(source Source) {
+	for range time.Tick(s.interval) {
+		if err := s.Flush(source.Metrics()); err != nil {
+			log.WithError(err).Warnln("Unable to send the metrics to StatsD")
+		}
+	}
+}
+
+//Flush sends the metrics, as few datagrams as possible
+func (s *StatsD) Flush(metrics []Metric) error {
+	var packet bytes.Buffer
+	for _, m := range metrics {
+		line := s.line(m)
+		if line == "" {
+			continue
+		}
+
+		if packet.Len() > 0 && packet.Len()+1+len(line) > maxPacket {
+			if err := s.send(&packet); err != nil {
+				return err
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+
+	return s.send(&packet)
+}
+
+//line formats the metric in the StatsD protocol, empty for a counter that
+//didn't increase
+func (s *StatsD) line(m Metric) string {
+	name := m.Name
+	if s.prefix != "" {
+		name = s.prefix + "." + name
+	}
+
+	if !m.Counter {
+		return fmt.Sprintf("%s:%s|g", name, strconv.FormatFloat(m.Value, 'f', -1, 64))
+	}
+
+	//A counter lower than on the last flush was reset, all of it is new
+	delta := m.Value - s.last[m.Name]
+	if delta < 0 {
+		delta = m.Value
+	}
+	s.last[m.Name] = m.Value
+	if delta == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%s|c", name, strconv.FormatFloat(delta, 'f', -1, 64))
+}
+
+func (s *StatsD) send(packet *bytes.Buffer) error {
+	if packet.Len() == 0 {
+		return nil
+	}
+
+	_, err := s.conn.Write(packet.Bytes())
+	packet.Reset()
+	return err
+}
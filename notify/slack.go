@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/example_scheduler"
+)
+
+//slackTimeout is how long Slack has to take a message
+const slackTimeout = 10 * time.Second
+
+//Slack posts the task failures, the restart loops, the deployments ended
+//and the agents blacklisted to a channel through an incoming webhook of
+//Slack. The messages about the same thing, like the failures of the tasks
+//of a job, are throttled so a crashing job doesn't flood the channel
+type Slack struct {
+	url      string
+	throttle time.Duration
+
+	//A job is in a restart loop when loopFailures of its tasks fail
+	//within loopWindow
+	loopFailures int
+	loopWindow   time.Duration
+
+	client *http.Client
+
+	//When a message was last posted about each subject, the messages
+	//throttled since then and the recent failures of each job. Only the
+	//goroutine running Run uses them
+	posted     map[string]time.Time
+	suppressed map[string]int
+	failures   map[string][]time.Time
+}
+
+//NewSlack creates the notifier posting to the incoming webhook URL, at
+//most one message by subject every throttle
+func NewSlack(url string, throttle time.Duration, loopFailures int, loopWindow time.Duration) *Slack {
+	return &Slack{
+		url:          url,
+		throttle:     throttle,
+		loopFailures: loopFailures,
+		loopWindow:   loopWindow,
+		client:       &http.Client{Timeout: slackTimeout},
+		posted:       make(map[string]time.Time),
+		suppressed:   make(map[string]int),
+		failures:     make(map[string][]time.Time),
+	}
+}
+
+//Run posts the notable events of the source. It never returns, run it in
+//its own goroutine
+func (s *Slack) Run(source Source) {
+	Follow(source, s.notify)
+}
+
+//notify posts the message about the event, unless one about the same
+//subject was posted less than the throttle ago
+func (s *Slack) notify(e *example_scheduler.Event) {
+	subject, text := s.message(e)
+
+	if time.Since(s.posted[subject]) < s.throttle {
+		s.suppressed[subject]++
+		return
+	}
+	if n := s.suppressed[subject]; n > 0 {
+		text += fmt.Sprintf(" (%d more since the last message)", n)
+	}
+
+	if err := s.post(text); err != nil {
+		log.WithFields(log.Fields{
+			"seq":     e.Seq,
+			"subject": subject,
+		}).WithError(err).Errorln("Unable to post to Slack")
+		return
+	}
+	s.posted[subject] = time.Now()
+	delete(s.suppressed, subject)
+}
+
+//message returns the subject of the event, the messages about the same one
+//being throttled together, and its text
+func (s *Slack) message(e *example_scheduler.Event) (string, string) {
+	switch e.Type {
+	case example_scheduler.EventDeployment:
+		icon := ":white_check_mark:"
+		switch e.State {
+		case example_scheduler.DeploymentAborted:
+			icon = ":warning:"
+		case example_scheduler.DeploymentRolledBack:
+			icon = ":leftwards_arrow_with_hook:"
+		}
+		return "deployment " + e.DeploymentID, fmt.Sprintf("%s Deployment %s of job %s %s", icon, e.DeploymentID, e.JobID, e.State)
+
+	case example_scheduler.EventAgent:
+		return "agent " + e.AgentID, fmt.Sprintf(":no_entry: Agent %s (%s) %s: %s", e.Hostname, e.AgentID, e.State, e.Reason)
+	}
+
+	if n := s.taskFailed(e.JobID); n >= s.loopFailures {
+		return "loop " + e.JobID, fmt.Sprintf(":repeat: Job %s is in a restart loop, %d tasks failed in %v, the last one %s on %s: %s",
+			e.JobID, n, s.loopWindow, e.TaskID, e.Hostname, why(e))
+	}
+
+	return "failures " + e.JobID, fmt.Sprintf(":x: Task %s of job %s %s on %s: %s", e.TaskID, e.JobID, e.State, e.Hostname, why(e))
+}
+
+//why returns the message of the status update of the task, or its reason
+//without a message
+func why(e *example_scheduler.Event) string {
+	if e.Message != "" {
+		return e.Message
+	}
+
+	return e.Reason
+}
+
+//taskFailed counts a failure of a task of the job and returns the failures
+//of its tasks within the loop window
+func (s *Slack) taskFailed(jobId string) int {
+	failures := []time.Time{time.Now()}
+	for _, at := range s.failures[jobId] {
+		if time.Since(at) < s.loopWindow {
+			failures = append(failures, at)
+		}
+	}
+	s.failures[jobId] = failures
+
+	return len(failures)
+}
+
+//post sends the text to the channel of the webhook
+func (s *Slack) post(text string) error {
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack answered %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+//Package notify tells the external systems, paging or chat, about what
+//happens in the scheduler that may need a human
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/example_scheduler"
+)
+
+//firstRetry is the wait before the first retry of a payload, doubled for
+//each of the next ones
+const firstRetry = time.Second
+
+//SignatureHeader has the HMAC-SHA256 of the payload with the secret of the
+//webhook, in hexadecimal after sha256=
+const SignatureHeader = "X-Scheduler-Signature"
+
+//Source gives the events of the scheduler
+type Source interface {
+	Subscribe(filter example_scheduler.EventFilter, since int64) ([]example_scheduler.Event, <-chan example_scheduler.Event, func(), error)
+}
+
+//notified are the types of the events that may be notified
+var notified = example_scheduler.EventFilter{
+	Types: []string{
+		example_scheduler.EventStatus,
+		example_scheduler.EventDeployment,
+		example_scheduler.EventAgent,
+	},
+}
+
+//Notable reports if the event needs to be notified: a task failed, a
+//deployment ended or an agent was blacklisted
+func Notable(e *example_scheduler.Event) bool {
+	switch e.Type {
+	case example_scheduler.EventStatus:
+		switch e.State {
+		case "TASK_FAILED", "TASK_LOST", "TASK_ERROR", "TASK_DROPPED", "TASK_GONE":
+			return true
+		}
+	case example_scheduler.EventDeployment:
+		switch e.State {
+		case example_scheduler.DeploymentFinished, example_scheduler.DeploymentAborted, example_scheduler.DeploymentRolledBack:
+			return true
+		}
+	case example_scheduler.EventAgent:
+		return true
+	}
+
+	return false
+}
+
+//Follow calls notify with the notable events of the source, one after the
+//other, from the subscription on. It subscribes again when it falls too
+//far behind, from the last event notified. It never returns, run it in its
+//own goroutine
+func Follow(source Source, notify func(e *example_scheduler.Event)) {
+	var last int64
+	for {
+		replay, events, cancel, err := source.Subscribe(notified, last)
+		if err == example_scheduler.ErrEventsGone {
+			log.WithField("seq", last).Warnln("Events missed by the notifications, following the new ones")
+			last = 0
+			continue
+		}
+		if err != nil {
+			log.WithError(err).Errorln("Unable to follow the events to notify")
+			time.Sleep(firstRetry)
+			continue
+		}
+
+		for i := range replay {
+			if Notable(&replay[i]) {
+				notify(&replay[i])
+			}
+			last = replay[i].Seq
+		}
+		for e := range events {
+			if Notable(&e) {
+				notify(&e)
+			}
+			last = e.Seq
+		}
+		cancel()
+	}
+}
+
+//Webhook posts the notable events as JSON to a URL
+type Webhook struct {
+	url     string
+	secret  string
+	retries int
+	client  *http.Client
+}
+
+//NewWebhook creates the webhook posting to the URL. With a secret every
+//payload is signed in the SignatureHeader. A payload the URL doesn't
+//accept in time is sent again up to retries times, waiting twice as long
+//each time
+func NewWebhook(url, secret string, retries int, timeout time.Duration) *Webhook {
+	return &Webhook{
+		url:     url,
+		secret:  secret,
+		retries: retries,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+//Run posts the notable events of the source. It never returns, run it in
+//its own goroutine
+func (w *Webhook) Run(source Source) {
+	Follow(source, func(e *example_scheduler.Event) {
+		wlog := log.WithFields(log.Fields{
+			"url":  w.url,
+			"seq":  e.Seq,
+			"type": e.Type,
+		})
+		if err := w.Post(e); err != nil {
+			wlog.WithError(err).Errorln("Webhook not notified, dropping the event")
+			return
+		}
+		wlog.Debugln("Webhook notified")
+	})
+}
+
+//Post sends the event, retrying while the URL fails to answer, answers
+//with a server error or asks to slow down
+func (w *Webhook) Post(e *example_scheduler.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	wait := firstRetry
+	for attempt := 0; ; attempt++ {
+		retry, err := w.post(e.Type, payload)
+		if err == nil || !retry || attempt >= w.retries {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"url":     w.url,
+			"seq":     e.Seq,
+			"attempt": attempt + 1,
+		}).WithError(err).Warnf("Webhook failed, retrying in %v", wait)
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+//post sends the payload once. It reports if it may be retried when it
+//fails
+func (w *Webhook) post(eventType string, payload []byte) (bool, error) {
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Scheduler-Event", eventType)
+	if w.secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(w.secret, payload))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500, resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("webhook answered %s", resp.Status)
+	}
+
+	return false, fmt.Errorf("webhook answered %s", resp.Status)
+}
+
+//Sign returns the HMAC-SHA256 of the payload with the secret, in
+//hexadecimal, for the receivers to check the payloads come from the
+//scheduler
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+	"minimal-mesos-go-framework/api"
+	"minimal-mesos-go-framework/config"
+	"minimal-mesos-go-framework/example_scheduler"
+)
+
+//reloadOnSighup reloads the configuration every time the process receives
+//SIGHUP and applies the changes of the job to the running scheduler, so
+//instances and resources can change without losing the registration with
+//the master. The API tokens, if any, are read again too
+func reloadOnSighup(s *example_scheduler.ExampleScheduler, tokens *api.Tokens, current *config.Config) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		log.Infoln("SIGHUP received, reloading the configuration")
+		if tokens != nil {
+			if err := tokens.Reload(); err != nil {
+				log.Errorln("API tokens not reloaded:", err)
+			}
+		}
+
+		//Only the static checks, an outage of the master or of the server
+		//of the executor URIs doesn't hold back a change of the job
+		cfg, err := loadConfig()
+		if err == nil {
+			err = cfg.Validate()
+		}
+		if err != nil {
+			log.Errorln("Configuration not reloaded:", err)
+			continue
+		}
+
+		if changed := restartSettings(cfg, current); len(changed) > 0 {
+			log.WithField("settings", strings.Join(changed, ", ")).Warnln("Changes in these settings need a restart to apply")
+		}
+
+		job := jobFromConfig(cfg)
+		err = s.UpdateJob(job)
+		if err == example_scheduler.ErrUnknownJob {
+			//The job ID changed, the old job keeps running until scaled down
+			err = s.SubmitJob(job)
+		}
+		if err != nil {
+			log.Errorln("Configuration not reloaded:", err)
+			continue
+		}
+
+		s.SetHostFilter(hostFilterFromConfig(cfg))
+		setupLogging(cfg)
+		current = cfg
+		log.Infof("Configuration reloaded: job %s with %d instances of cpus=%v mem=%v", job.ID, job.Instances, job.Cpus, job.Mem)
+	}
+}
+
+//restartSettings returns the settings changed between the configurations
+//that are only applied on start, by their key in the config file. Every
+//setting but the job, the host filter and the logging is compared, so a new
+//one can't be missed
+func restartSettings(cfg, current *config.Config) []string {
+	a, b := *cfg, *current
+	for _, c := range []*config.Config{&a, &b} {
+		c.Task = config.TaskConfig{}
+		c.Hosts = config.HostsConfig{}
+		c.LogLevel = ""
+		c.LogFormat = ""
+	}
+
+	var changed []string
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	for i := 0; i < va.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			key := va.Type().Field(i).Tag.Get("json")
+			changed = append(changed, strings.Split(key, ",")[0])
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,700 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"minimal-mesos-go-framework/api"
+	"minimal-mesos-go-framework/cli"
+	"minimal-mesos-go-framework/example_scheduler"
+)
+
+//The commands below talk to the management API of a running scheduler
+
+var submitCommand = &cli.Command{
+	Name:  "submit",
+	Args:  "<job.json | ->",
+	Short: "Submit a new job, or an array of jobs all at once, read as JSON from a file or the standard input",
+	Flags: remoteFlags("submit"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 1 {
+			return cli.ErrUsage
+		}
+
+		data, err := readJob(args[0])
+		if err != nil {
+			return err
+		}
+
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			var batch api.BatchResponse
+			if err := callAPI(cmd, "POST", "/v1/jobs", json.RawMessage(data), &batch); err != nil {
+				return err
+			}
+			for _, job := range batch.Jobs {
+				fmt.Printf("Job %s submitted with %d instances\n", job.ID, job.Instances)
+			}
+			return nil
+		}
+
+		var job example_scheduler.JobSpec
+		if err := callAPI(cmd, "POST", "/v1/jobs", json.RawMessage(data), &job); err != nil {
+			return err
+		}
+
+		fmt.Printf("Job %s submitted with %d instances\n", job.ID, job.Instances)
+		return nil
+	},
+}
+
+var updateCommand = &cli.Command{
+	Name:  "update",
+	Args:  "<job.json | ->",
+	Short: "Deploy a new spec of a job, read as JSON from a file or the standard input",
+	Flags: remoteFlags("update"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 1 {
+			return cli.ErrUsage
+		}
+
+		data, err := readJob(args[0])
+		if err != nil {
+			return err
+		}
+
+		var job example_scheduler.JobSpec
+		if err := json.Unmarshal(data, &job); err != nil {
+			return fmt.Errorf("invalid job spec: %v", err)
+		}
+		if err := callAPI(cmd, "PUT", "/v1/jobs/"+job.ID, json.RawMessage(data), &job); err != nil {
+			return err
+		}
+
+		fmt.Printf("Job %s updated\n", job.ID)
+		return nil
+	},
+}
+
+var deploymentsCommand = &cli.Command{
+	Name:  "deployments",
+	Short: "List the last deployment of every job",
+	Flags: remoteFlags("deployments"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 0 {
+			return cli.ErrUsage
+		}
+
+		var deployments []example_scheduler.DeploymentSummary
+		if err := callAPI(cmd, "GET", "/v1/deployments", nil, &deployments); err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "JOB\tVERSION\tSTRATEGY\tSTATE\tOLD\tNEW\tREADY\tFAILURES\tCANARY")
+		for _, d := range deployments {
+			canary := "-"
+			if d.Canary != nil {
+				canary = d.Canary.TaskID + " " + d.Canary.State
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d\t%d\t%d\t%d\t%s\n", d.JobID, d.Version, d.Strategy, d.State, d.OldTasks, d.NewTasks, d.ReadyTasks, d.Failures, canary)
+		}
+
+		return w.Flush()
+	},
+}
+
+var queueCommand = &cli.Command{
+	Name:  "queue",
+	Short: "List the instances waiting in the launch queue",
+	Flags: remoteFlags("queue"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 0 {
+			return cli.ErrUsage
+		}
+
+		var queue example_scheduler.QueueSummary
+		if err := callAPI(cmd, "GET", "/v1/queue", nil, &queue); err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "JOB\tPRIORITY\tWAIT\tATTEMPTS\tBACKOFF")
+		for _, q := range queue.Launches {
+			fmt.Fprintf(w, "%s\t%d\t%.0fs\t%d\t%t\n", q.JobID, q.Priority, q.Wait, q.Attempts, q.Backoff)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		fmt.Printf("\n%d queued, oldest wait %.0fs. %d launched, mean wait %.1fs, max wait %.1fs\n", queue.Depth, queue.OldestWait, queue.Launched, queue.MeanWait, queue.MaxWait)
+		return nil
+	},
+}
+
+var runsCommand = &cli.Command{
+	Name:  "runs",
+	Args:  "<job>",
+	Short: "List the runs of a cron job",
+	Flags: remoteFlags("runs"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 1 {
+			return cli.ErrUsage
+		}
+
+		var runs []example_scheduler.CronRunSummary
+		if err := callAPI(cmd, "GET", "/v1/jobs/"+args[0]+"/runs", nil, &runs); err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "RUN\tSCHEDULED\tSTATE\tTASKS\tSUCCEEDED\tENDED")
+		for _, r := range runs {
+			ended := "-"
+			if !r.Ended.IsZero() {
+				ended = r.Ended.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%s\n", r.Run, r.Scheduled.Format(time.RFC3339), r.State, len(r.Tasks), r.Succeeded, ended)
+		}
+
+		return w.Flush()
+	},
+}
+
+var indexesCommand = &cli.Command{
+	Name:  "indexes",
+	Args:  "<job>",
+	Short: "List the indexes of an indexed job and their state",
+	Flags: remoteFlags("indexes"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 1 {
+			return cli.ErrUsage
+		}
+
+		var indexes []example_scheduler.IndexSummary
+		if err := callAPI(cmd, "GET", "/v1/jobs/"+args[0]+"/indexes", nil, &indexes); err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "INDEX\tSTATE\tATTEMPTS\tTASK")
+		for _, i := range indexes {
+			task := i.TaskID
+			if task == "" {
+				task = "-"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%d\t%s\n", i.Index, i.State, i.Attempts, task)
+		}
+
+		return w.Flush()
+	},
+}
+
+var retryCommand = &cli.Command{
+	Name:  "retry",
+	Args:  "<job>",
+	Short: "Launch again the failed indexes of an indexed job",
+	Flags: remoteFlags("retry"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 1 {
+			return cli.ErrUsage
+		}
+
+		var retried []int
+		if err := callAPI(cmd, "POST", "/v1/jobs/"+args[0]+"/retry", nil, &retried); err != nil {
+			return err
+		}
+
+		if len(retried) == 0 {
+			fmt.Printf("Job %s has no failed indexes\n", args[0])
+			return nil
+		}
+		fmt.Printf("Job %s retrying indexes %v\n", args[0], retried)
+		return nil
+	},
+}
+
+var pipelineCommand = &cli.Command{
+	Name:  "pipeline",
+	Short: "List the jobs with dependencies and what they wait for",
+	Flags: remoteFlags("pipeline"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 0 {
+			return cli.ErrUsage
+		}
+
+		var jobs []example_scheduler.PipelineSummary
+		if err := callAPI(cmd, "GET", "/v1/pipeline", nil, &jobs); err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "JOB\tDEPENDS ON\tSTATE\tUPSTREAM\tSUCCEEDED")
+		for _, j := range jobs {
+			upstream := j.Upstream
+			if upstream == "" {
+				upstream = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n", j.JobID, strings.Join(j.DependsOn, ","), j.State, upstream, j.Succeeded)
+		}
+
+		return w.Flush()
+	},
+}
+
+var approveCommand = &cli.Command{
+	Name:  "approve",
+	Args:  "<job>",
+	Short: "Approve the deployment of a job waiting for it",
+	Flags: remoteFlags("approve"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 1 {
+			return cli.ErrUsage
+		}
+
+		if err := callAPI(cmd, "POST", "/v1/deployments/"+args[0]+"/approve", nil, nil); err != nil {
+			return err
+		}
+
+		fmt.Printf("Deployment of job %s approved\n", args[0])
+		return nil
+	},
+}
+
+var rollbackCommand = &cli.Command{
+	Name:  "rollback",
+	Args:  "<job>",
+	Short: "Roll back a job to its spec before the last deployment",
+	Flags: remoteFlags("rollback"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 1 {
+			return cli.ErrUsage
+		}
+
+		if err := callAPI(cmd, "POST", "/v1/deployments/"+args[0]+"/rollback", nil, nil); err != nil {
+			return err
+		}
+
+		fmt.Printf("Job %s rolling back\n", args[0])
+		return nil
+	},
+}
+
+//readJob reads a job spec from a file, or from the standard input if the
+//path is -
+func readJob(path string) ([]byte, error) {
+	if path == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+
+	return ioutil.ReadFile(path)
+}
+
+var statusCommand = &cli.Command{
+	Name:  "status",
+	Args:  "[job]",
+	Short: "List the tasks of every job or of the given one",
+	Flags: remoteFlags("status"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) > 1 {
+			return cli.ErrUsage
+		}
+
+		query := url.Values{}
+		if len(args) == 1 {
+			query.Set("job", args[0])
+		}
+		if state := cmd.Flags.Lookup("state").Value.String(); state != "" {
+			query.Set("state", state)
+		}
+
+		var tasks []example_scheduler.TaskSummary
+		if err := callAPI(cmd, "GET", "/v1/tasks?"+query.Encode(), nil, &tasks); err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "TASK\tJOB\tHOST\tSTATE")
+		for _, t := range tasks {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.ID, t.JobID, t.Hostname, t.State)
+		}
+
+		return w.Flush()
+	},
+}
+
+var killCommand = &cli.Command{
+	Name:  "kill",
+	Args:  "<task>",
+	Short: "Kill a task. Its job launches a replacement",
+	Flags: remoteFlags("kill"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 1 {
+			return cli.ErrUsage
+		}
+
+		path := "/v1/tasks/" + args[0]
+		if cmd.Flags.Lookup("scale").Value.String() == "true" {
+			path += "?scale=true"
+		}
+		var kill api.KillResponse
+		if err := callAPI(cmd, "DELETE", path, nil, &kill); err != nil {
+			return err
+		}
+
+		fmt.Printf("Kill of task %s requested as %s\n", args[0], kill.ID)
+		return waitKill(cmd, kill.ID)
+	},
+}
+
+var removeCommand = &cli.Command{
+	Name:  "remove",
+	Args:  "<job>",
+	Short: "Kill every task of a job and forget it",
+	Flags: remoteFlags("remove"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 1 {
+			return cli.ErrUsage
+		}
+
+		var kill api.KillResponse
+		if err := callAPI(cmd, "DELETE", "/v1/jobs/"+args[0], nil, &kill); err != nil {
+			return err
+		}
+
+		fmt.Printf("Removal of job %s requested as %s\n", args[0], kill.ID)
+		return waitKill(cmd, kill.ID)
+	},
+}
+
+//waitKill polls the kill until every task killed ended, if the command
+//has --wait set
+func waitKill(cmd *cli.Command, id string) error {
+	if cmd.Flags.Lookup("wait").Value.String() != "true" {
+		return nil
+	}
+
+	for {
+		var summary example_scheduler.KillSummary
+		if err := callAPI(cmd, "GET", "/v1/kills/"+id, nil, &summary); err != nil {
+			return err
+		}
+		if summary.Done {
+			fmt.Printf("Every task killed ended (%d)\n", len(summary.Tasks))
+			return nil
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+var scaleCommand = &cli.Command{
+	Name:  "scale",
+	Args:  "<job> <instances>",
+	Short: "Change the number of instances of a job",
+	Flags: remoteFlags("scale"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 2 {
+			return cli.ErrUsage
+		}
+
+		instances, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid number of instances %q", args[1])
+		}
+
+		req := &api.ScaleRequest{
+			Instances:     instances,
+			KillSelection: cmd.Flags.Lookup("kill-selection").Value.String(),
+		}
+		var resp api.ScaleResponse
+		if err := callAPI(cmd, "PUT", "/v1/jobs/"+args[0]+"/scale", req, &resp); err != nil {
+			return err
+		}
+
+		fmt.Printf("Job %s scaled to %d instances by deployment %s\n", args[0], instances, resp.DeploymentID)
+		return nil
+	},
+}
+
+var auditCommand = &cli.Command{
+	Name:  "audit",
+	Args:  "[job]",
+	Short: "List the last scheduling decisions, of every job or of the given one",
+	Flags: remoteFlags("audit"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) > 1 {
+			return cli.ErrUsage
+		}
+
+		query := url.Values{}
+		if len(args) == 1 {
+			query.Set("job", args[0])
+		}
+		for _, name := range []string{"action", "task", "agent", "limit"} {
+			if value := cmd.Flags.Lookup(name).Value.String(); value != "" {
+				query.Set(name, value)
+			}
+		}
+
+		var entries []example_scheduler.AuditEntry
+		if err := callAPI(cmd, "GET", "/v1/audit?"+query.Encode(), nil, &entries); err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME	ACTION	JOB	TASK	HOST	REASON")
+		for _, e := range entries {
+			reason := e.Reason
+			if e.Principal != "" {
+				reason = e.Principal + ": " + reason
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Time.Format(time.RFC3339), e.Action, orDash(e.JobID), orDash(e.TaskID), orDash(e.Hostname), orDash(reason))
+		}
+
+		return w.Flush()
+	},
+}
+
+var eventsCommand = &cli.Command{
+	Name:  "events",
+	Args:  "[job]",
+	Short: "Print the events of the scheduler as they happen, of every job or of the given one, one JSON object per line",
+	Flags: remoteFlags("events"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) > 1 {
+			return cli.ErrUsage
+		}
+
+		query := url.Values{}
+		if len(args) == 1 {
+			query.Set("job", args[0])
+		}
+		for _, name := range []string{"type", "since"} {
+			if value := cmd.Flags.Lookup(name).Value.String(); value != "" && value != "0" {
+				query.Set(name, value)
+			}
+		}
+
+		path := strings.TrimSuffix(cmd.Flags.Lookup("api").Value.String(), "/") + "/v1/events?" + query.Encode()
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			return err
+		}
+		setToken(cmd, req)
+
+		client, err := apiClient(cmd)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			var apiErr api.Error
+			if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Error == "" {
+				return fmt.Errorf("GET /v1/events: %s", resp.Status)
+			}
+			return fmt.Errorf("GET /v1/events: %s", apiErr.Error)
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data := strings.TrimPrefix(scanner.Text(), "data: "); data != scanner.Text() {
+				fmt.Println(data)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+
+		return errors.New("the scheduler closed the event stream")
+	},
+}
+
+//orDash returns the value, or - if it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+var pauseCommand = &cli.Command{
+	Name:  "pause",
+	Args:  "[reason]",
+	Short: "Pause the scheduling for maintenance: suppress the offers and launch no task, while the running tasks are still tracked",
+	Flags: remoteFlags("pause"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) > 1 {
+			return cli.ErrUsage
+		}
+
+		req := &api.PauseRequest{}
+		if len(args) == 1 {
+			req.Reason = args[0]
+		}
+		var m example_scheduler.Maintenance
+		if err := callAPI(cmd, "POST", "/v1/maintenance/pause", req, &m); err != nil {
+			return err
+		}
+
+		fmt.Printf("Scheduling paused since %s\n", m.Since.Format(time.RFC3339))
+		return nil
+	},
+}
+
+var resumeCommand = &cli.Command{
+	Name:  "resume",
+	Short: "Resume the scheduling paused with pause",
+	Flags: remoteFlags("resume"),
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 0 {
+			return cli.ErrUsage
+		}
+
+		var m example_scheduler.Maintenance
+		if err := callAPI(cmd, "POST", "/v1/maintenance/resume", nil, &m); err != nil {
+			return err
+		}
+
+		fmt.Println("Scheduling resumed")
+		return nil
+	},
+}
+
+//The flags of a single command
+func init() {
+	statusCommand.Flags.String("state", "", "Only the tasks in the states, separated by commas, like running or TASK_FAILED")
+	killCommand.Flags.Bool("scale", false, "Scale the job down instead of replacing the task")
+	killCommand.Flags.Bool("wait", false, "Wait until the task ended")
+	removeCommand.Flags.Bool("wait", false, "Wait until every task of the job ended")
+	scaleCommand.Flags.String("kill-selection", "", "Tasks killed when scaling down: newest-first or least-healthy-first (the default)")
+	auditCommand.Flags.String("action", "", "Only the decisions of the action: accept, decline, launch, kill, unplaced or request")
+	auditCommand.Flags.String("task", "", "Only the decisions about the task")
+	auditCommand.Flags.String("agent", "", "Only the decisions about the agent, by ID")
+	auditCommand.Flags.Int("limit", 50, "Most decisions listed, the last ones. 0 for all those kept")
+	eventsCommand.Flags.String("type", "", "Only the events of the types, separated by commas: status, accept, decline, deployment or agent")
+	eventsCommand.Flags.Int64("since", 0, "Replay first the events kept after the sequence number")
+}
+
+//remoteFlags creates the flags shared by the commands that use the API
+func remoteFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+
+	apiURL := os.Getenv("SCHEDULER_API")
+	if apiURL == "" {
+		apiURL = "http://127.0.0.1:8000"
+	}
+	fs.String("api", apiURL, "URL of the scheduler API [$SCHEDULER_API]")
+	fs.String("token", os.Getenv("SCHEDULER_API_TOKEN"), "Bearer token of the scheduler API [$SCHEDULER_API_TOKEN]")
+	fs.String("ca", os.Getenv("SCHEDULER_API_CA"), "File of the authorities of the certificate of the API, the system ones if empty [$SCHEDULER_API_CA]")
+	fs.String("cert", os.Getenv("SCHEDULER_API_CERT"), "Client certificate file, for an API verifying the clients [$SCHEDULER_API_CERT]")
+	fs.String("key", os.Getenv("SCHEDULER_API_KEY"), "Key file of the client certificate [$SCHEDULER_API_KEY]")
+
+	return fs
+}
+
+//setToken authenticates the request with the token of the command, if any
+func setToken(cmd *cli.Command, req *http.Request) {
+	if token := cmd.Flags.Lookup("token").Value.String(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+//apiClient returns the HTTP client talking to the API with the authorities
+//and the client certificate of the command, if any
+func apiClient(cmd *cli.Command) (*http.Client, error) {
+	ca := cmd.Flags.Lookup("ca").Value.String()
+	cert := cmd.Flags.Lookup("cert").Value.String()
+	key := cmd.Flags.Lookup("key").Value.String()
+	if ca == "" && cert == "" && key == "" {
+		return http.DefaultClient, nil
+	}
+
+	config := &tls.Config{}
+	if ca != "" {
+		pem, err := ioutil.ReadFile(ca)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", ca)
+		}
+	}
+	if cert != "" || key != "" {
+		if cert == "" || key == "" {
+			return nil, errors.New("the client certificate needs both --cert and --key")
+		}
+		pair, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{pair}
+	}
+
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: config}}, nil
+}
+
+//callAPI sends body as JSON to the API of the scheduler and decodes the
+//response in result, if not nil
+func callAPI(cmd *cli.Command, method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	url := strings.TrimSuffix(cmd.Flags.Lookup("api").Value.String(), "/") + path
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setToken(cmd, req)
+
+	client, err := apiClient(cmd)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr api.Error
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil || apiErr.Error == "" {
+			return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, apiErr.Error)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/mesos/mesos-go/scheduler"
+	"minimal-mesos-go-framework/config"
+	"minimal-mesos-go-framework/example_scheduler"
+)
+
+//shutdownTimeout bounds how long the shutdown waits for the killed tasks
+const shutdownTimeout = 30 * time.Second
+
+//stopOnSignal shuts the framework down in order when the process receives
+//SIGINT or SIGTERM: no more tasks are launched, the running ones are killed
+//if configured so and the driver is stopped. With failover the master keeps
+//the framework, and its tasks, for the failover timeout. A teardown kills
+//the tasks, unregisters the framework and forgets its FrameworkID
+func stopOnSignal(s *example_scheduler.ExampleScheduler, driver scheduler.SchedulerDriver, cfg *config.Config) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	killTasks, failover := cfg.Shutdown.KillTasks, cfg.Shutdown.Failover
+	if cfg.Shutdown.Teardown {
+		killTasks, failover = true, false
+	}
+
+	sig := <-signals
+	log.WithFields(log.Fields{
+		"signal":     sig.String(),
+		"kill_tasks": killTasks,
+		"failover":   failover,
+		"teardown":   cfg.Shutdown.Teardown,
+	}).Infoln("Shutting down")
+
+	//A second signal exits right away
+	go func() {
+		<-signals
+		log.Fatalln("Shutdown interrupted")
+	}()
+
+	s.Shutdown(killTasks, shutdownTimeout)
+
+	if _, err := driver.Stop(failover); err != nil {
+		log.Errorln("Unable to stop the driver:", err)
+	}
+
+	//The framework is gone, the next start registers a new one instead of
+	//failing over to it
+	if cfg.Shutdown.Teardown && s.FrameworkIDStore != nil {
+		if err := s.FrameworkIDStore.SaveFrameworkID(""); err != nil {
+			log.Errorln("Unable to remove the FrameworkID:", err)
+		}
+	}
+}
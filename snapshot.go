@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"minimal-mesos-go-framework/cli"
+	"minimal-mesos-go-framework/config"
+	"minimal-mesos-go-framework/store"
+)
+
+//The commands below read and write the state store of the configuration
+//directly, they take the flags of run to find it
+
+var exportCommand = &cli.Command{
+	Name:  "export",
+	Args:  "[snapshot.json | -]",
+	Short: "Write the saved jobs, tasks and deployments as a JSON snapshot, to the standard output by default",
+	Flags: runFlags,
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) > 1 {
+			return cli.ErrUsage
+		}
+
+		cfg, stateStore, err := openSnapshotStore()
+		if err != nil {
+			return err
+		}
+
+		snapshot, err := store.Export(stateStore)
+		if err != nil {
+			return err
+		}
+		if idFile := frameworkIDFile(cfg); idFile != nil {
+			if snapshot.FrameworkID, err = idFile.LoadFrameworkID(); err != nil {
+				return err
+			}
+		}
+
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+
+		if len(args) == 0 || args[0] == "-" {
+			_, err = os.Stdout.Write(data)
+		} else {
+			err = ioutil.WriteFile(args[0], data, 0600)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Exported %d jobs, %d tasks, %d deployments and %d idempotency keys\n", len(snapshot.Jobs), len(snapshot.Tasks), len(snapshot.Deployments), len(snapshot.Submissions))
+		return nil
+	},
+}
+
+var importCommand = &cli.Command{
+	Name:  "import",
+	Args:  "<snapshot.json | ->",
+	Short: "Load a JSON snapshot into an empty state store, with the scheduler stopped",
+	Flags: runFlags,
+	Run: func(cmd *cli.Command, args []string) error {
+		if len(args) != 1 {
+			return cli.ErrUsage
+		}
+
+		data, err := readJob(args[0])
+		if err != nil {
+			return err
+		}
+		snapshot := &store.Snapshot{}
+		if err := json.Unmarshal(data, snapshot); err != nil {
+			return fmt.Errorf("invalid snapshot: %v", err)
+		}
+
+		cfg, stateStore, err := openSnapshotStore()
+		if err != nil {
+			return err
+		}
+
+		//The FrameworkID goes to its own file when there is one
+		idFile := frameworkIDFile(cfg)
+		id := snapshot.FrameworkID
+		if idFile != nil {
+			saved, err := idFile.LoadFrameworkID()
+			if err != nil {
+				return err
+			}
+			if saved != "" {
+				return store.ErrNotEmpty
+			}
+			snapshot.FrameworkID = ""
+		}
+
+		if err := store.Import(stateStore, snapshot); err != nil {
+			return err
+		}
+		if idFile != nil && id != "" {
+			if err := idFile.SaveFrameworkID(id); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintf(os.Stderr, "Imported %d jobs, %d tasks, %d deployments and %d idempotency keys\n", len(snapshot.Jobs), len(snapshot.Tasks), len(snapshot.Deployments), len(snapshot.Submissions))
+		return nil
+	},
+}
+
+//openSnapshotStore loads the configuration and opens its state store,
+//without campaigning in the leader election
+func openSnapshotStore() (*config.Config, store.Store, error) {
+	cfg, err := loadConfig()
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, stateStore, err := openState(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stateStore == nil {
+		return nil, nil, errors.New("no state store configured, set --state-file, --state-store postgres, --ha-zk or --ha-etcd")
+	}
+
+	return cfg, stateStore, nil
+}
+
+//frameworkIDFile returns the file of the FrameworkID, nil when it is kept in
+//the state store, as run decides
+func frameworkIDFile(cfg *config.Config) *store.FrameworkIDFile {
+	if cfg.HA.ZK != "" || cfg.HA.Etcd != "" || cfg.Framework.IDFile == "" {
+		return nil
+	}
+
+	return &store.FrameworkIDFile{Path: cfg.Framework.IDFile}
+}
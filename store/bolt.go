@@ -0,0 +1,275 @@
+package store
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+//boltOpenTimeout bounds how long opening the database waits for the lock
+//of another process holding it
+const boltOpenTimeout = time.Second
+
+//The buckets of a BoltStore
+var (
+	frameworkBucket   = []byte("framework")
+	jobsBucket        = []byte("jobs")
+	tasksBucket       = []byte("tasks")
+	deploymentsBucket = []byte("deployments")
+	submissionsBucket = []byte("submissions")
+)
+
+//frameworkIDKey is the key of the FrameworkID in the framework bucket,
+//maintenanceKey the key of the pause of the scheduling
+var (
+	frameworkIDKey = []byte("id")
+	maintenanceKey = []byte("maintenance")
+)
+
+//BoltStore is a Store that keeps the state in a BoltDB database, a single
+//file embedded in the scheduler, for a single scheduler that doesn't want
+//an external dependency. Unlike the FileStore, a change only writes the
+//job, task or deployment it changes. The FrameworkID and the pause are in
+//the framework bucket, and the jobs, tasks, deployments and submissions in their own
+//buckets, by the ID of the job or the task and by the idempotency key, in
+//JSON
+type BoltStore struct {
+	db *bolt.DB
+}
+
+//boltJob is a job of a BoltStore: its spec, as it was given, and the
+//sequence of its first save, to load the jobs in that order
+type boltJob struct {
+	Seq  uint64          `json:"seq"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+//NewBoltStore opens the database at path, creating it and its buckets if
+//needed. Only one process can open it at a time
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{frameworkBucket, jobsBucket, tasksBucket, deploymentsBucket, submissionsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+//Close closes the database
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+//LoadFrameworkID implements FrameworkIDStore
+func (b *BoltStore) LoadFrameworkID() (string, error) {
+	var id string
+	err := b.db.View(func(tx *bolt.Tx) error {
+		id = string(tx.Bucket(frameworkBucket).Get(frameworkIDKey))
+		return nil
+	})
+
+	return id, err
+}
+
+//SaveFrameworkID implements FrameworkIDStore
+func (b *BoltStore) SaveFrameworkID(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		if id == "" {
+			return tx.Bucket(frameworkBucket).Delete(frameworkIDKey)
+		}
+		return tx.Bucket(frameworkBucket).Put(frameworkIDKey, []byte(id))
+	})
+}
+
+//SaveJob implements Store
+func (b *BoltStore) SaveJob(id string, spec []byte) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(jobsBucket)
+
+		job := boltJob{Spec: spec}
+		if data := bucket.Get([]byte(id)); data != nil {
+			var saved boltJob
+			if err := json.Unmarshal(data, &saved); err != nil {
+				return err
+			}
+			job.Seq = saved.Seq
+		} else {
+			seq, err := bucket.NextSequence()
+			if err != nil {
+				return err
+			}
+			job.Seq = seq
+		}
+
+		data, err := json.Marshal(&job)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(id), data)
+	})
+}
+
+//LoadJobs implements Store
+func (b *BoltStore) LoadJobs() ([][]byte, error) {
+	var jobs []boltJob
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, data []byte) error {
+			var job boltJob
+			if err := json.Unmarshal(data, &job); err != nil {
+				return err
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Seq < jobs[j].Seq })
+
+	specs := make([][]byte, 0, len(jobs))
+	for _, job := range jobs {
+		specs = append(specs, job.Spec)
+	}
+
+	return specs, nil
+}
+
+//SaveTask implements Store
+func (b *BoltStore) SaveTask(task *Task) error {
+	return b.put(tasksBucket, task.ID, task)
+}
+
+//DeleteJob implements Store
+func (b *BoltStore) DeleteJob(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(jobsBucket).Delete([]byte(id)); err != nil {
+			return err
+		}
+		return tx.Bucket(deploymentsBucket).Delete([]byte(id))
+	})
+}
+
+//DeleteTask implements Store
+func (b *BoltStore) DeleteTask(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(tasksBucket).Delete([]byte(id))
+	})
+}
+
+//LoadTasks implements Store. The tasks are sorted by ID, the order of the
+//keys
+func (b *BoltStore) LoadTasks() ([]*Task, error) {
+	var tasks []*Task
+	err := b.each(tasksBucket, func(data []byte) error {
+		task := &Task{}
+		tasks = append(tasks, task)
+		return json.Unmarshal(data, task)
+	})
+
+	return tasks, err
+}
+
+//SaveDeployment implements Store
+func (b *BoltStore) SaveDeployment(deployment *Deployment) error {
+	return b.put(deploymentsBucket, deployment.JobID, deployment)
+}
+
+//LoadDeployments implements Store. The deployments are sorted by job ID,
+//the order of the keys
+func (b *BoltStore) LoadDeployments() ([]*Deployment, error) {
+	var deployments []*Deployment
+	err := b.each(deploymentsBucket, func(data []byte) error {
+		deployment := &Deployment{}
+		deployments = append(deployments, deployment)
+		return json.Unmarshal(data, deployment)
+	})
+
+	return deployments, err
+}
+
+//SaveSubmission implements Store
+func (b *BoltStore) SaveSubmission(submission *Submission) error {
+	return b.put(submissionsBucket, submission.Key, submission)
+}
+
+//DeleteSubmission implements Store
+func (b *BoltStore) DeleteSubmission(key string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(submissionsBucket).Delete([]byte(key))
+	})
+}
+
+//LoadSubmissions implements Store. The submissions are sorted by key
+func (b *BoltStore) LoadSubmissions() ([]*Submission, error) {
+	var submissions []*Submission
+	err := b.each(submissionsBucket, func(data []byte) error {
+		submission := &Submission{}
+		submissions = append(submissions, submission)
+		return json.Unmarshal(data, submission)
+	})
+
+	return submissions, err
+}
+
+//SaveMaintenance implements Store
+func (b *BoltStore) SaveMaintenance(maintenance *Maintenance) error {
+	if maintenance == nil {
+		return b.db.Update(func(tx *bolt.Tx) error {
+			return tx.Bucket(frameworkBucket).Delete(maintenanceKey)
+		})
+	}
+
+	return b.put(frameworkBucket, string(maintenanceKey), maintenance)
+}
+
+//LoadMaintenance implements Store
+func (b *BoltStore) LoadMaintenance() (*Maintenance, error) {
+	var maintenance *Maintenance
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(frameworkBucket).Get(maintenanceKey)
+		if data == nil {
+			return nil
+		}
+		maintenance = &Maintenance{}
+		return json.Unmarshal(data, maintenance)
+	})
+
+	return maintenance, err
+}
+
+//put saves the value, in JSON, at the key of the bucket
+func (b *BoltStore) put(bucket []byte, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), data)
+	})
+}
+
+//each calls load with each value of the bucket, in the order of the keys
+func (b *BoltStore) each(bucket []byte, load func(data []byte) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(_, data []byte) error {
+			return load(data)
+		})
+	})
+}
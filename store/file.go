@@ -0,0 +1,278 @@
+package store
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+)
+
+//FileStore is a Store that keeps the whole state in a JSON file, written
+//again on every change. It is meant for a single scheduler with a handful
+//of jobs, not for a big cluster
+type FileStore struct {
+	path  string
+	mutex sync.Mutex
+	state fileState
+}
+
+//fileState is the content of the file of a FileStore
+type fileState struct {
+	FrameworkID string           `json:"framework_id,omitempty"`
+	Jobs        []savedJob       `json:"jobs"`
+	Tasks       map[string]*Task `json:"tasks"`
+
+	Deployments map[string]*Deployment `json:"deployments"`
+	Submissions map[string]*Submission `json:"submissions,omitempty"`
+	Maintenance *Maintenance           `json:"maintenance,omitempty"`
+}
+
+//savedJob is a job of a FileStore, its spec kept as it was given
+type savedJob struct {
+	ID   string          `json:"id"`
+	Spec json.RawMessage `json:"spec"`
+}
+
+//NewFileStore opens the store kept in the file at path, which is created on
+//the first change if it doesn't exist
+func NewFileStore(path string) (*FileStore, error) {
+	f := &FileStore{
+		path: path,
+		state: fileState{
+			Tasks:       make(map[string]*Task),
+			Deployments: make(map[string]*Deployment),
+			Submissions: make(map[string]*Submission),
+		},
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &f.state); err != nil {
+		return nil, err
+	}
+	if f.state.Tasks == nil {
+		f.state.Tasks = make(map[string]*Task)
+	}
+	if f.state.Deployments == nil {
+		f.state.Deployments = make(map[string]*Deployment)
+	}
+	if f.state.Submissions == nil {
+		f.state.Submissions = make(map[string]*Submission)
+	}
+
+	return f, nil
+}
+
+//LoadFrameworkID implements FrameworkIDStore
+func (f *FileStore) LoadFrameworkID() (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.state.FrameworkID, nil
+}
+
+//SaveFrameworkID implements FrameworkIDStore
+func (f *FileStore) SaveFrameworkID(id string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.state.FrameworkID = id
+	return f.write()
+}
+
+//SaveJob implements Store
+func (f *FileStore) SaveJob(id string, spec []byte) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	saved := false
+	for i := range f.state.Jobs {
+		if f.state.Jobs[i].ID == id {
+			f.state.Jobs[i].Spec = spec
+			saved = true
+		}
+	}
+	if !saved {
+		f.state.Jobs = append(f.state.Jobs, savedJob{ID: id, Spec: spec})
+	}
+
+	return f.write()
+}
+
+//DeleteJob implements Store
+func (f *FileStore) DeleteJob(id string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	jobs := f.state.Jobs[:0]
+	for _, job := range f.state.Jobs {
+		if job.ID != id {
+			jobs = append(jobs, job)
+		}
+	}
+	_, deployed := f.state.Deployments[id]
+	if len(jobs) == len(f.state.Jobs) && !deployed {
+		return nil
+	}
+
+	f.state.Jobs = jobs
+	delete(f.state.Deployments, id)
+	return f.write()
+}
+
+//LoadJobs implements Store
+func (f *FileStore) LoadJobs() ([][]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	specs := make([][]byte, 0, len(f.state.Jobs))
+	for _, job := range f.state.Jobs {
+		specs = append(specs, append([]byte(nil), job.Spec...))
+	}
+
+	return specs, nil
+}
+
+//SaveTask implements Store
+func (f *FileStore) SaveTask(task *Task) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	saved := *task
+	f.state.Tasks[task.ID] = &saved
+	return f.write()
+}
+
+//DeleteTask implements Store
+func (f *FileStore) DeleteTask(id string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, ok := f.state.Tasks[id]; !ok {
+		return nil
+	}
+
+	delete(f.state.Tasks, id)
+	return f.write()
+}
+
+//LoadTasks implements Store. The tasks are sorted by ID
+func (f *FileStore) LoadTasks() ([]*Task, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	tasks := make([]*Task, 0, len(f.state.Tasks))
+	for _, task := range f.state.Tasks {
+		saved := *task
+		tasks = append(tasks, &saved)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	return tasks, nil
+}
+
+//SaveDeployment implements Store
+func (f *FileStore) SaveDeployment(deployment *Deployment) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	saved := *deployment
+	f.state.Deployments[deployment.JobID] = &saved
+	return f.write()
+}
+
+//LoadDeployments implements Store. The deployments are sorted by job ID
+func (f *FileStore) LoadDeployments() ([]*Deployment, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	deployments := make([]*Deployment, 0, len(f.state.Deployments))
+	for _, deployment := range f.state.Deployments {
+		saved := *deployment
+		deployments = append(deployments, &saved)
+	}
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].JobID < deployments[j].JobID })
+
+	return deployments, nil
+}
+
+//SaveSubmission implements Store
+func (f *FileStore) SaveSubmission(submission *Submission) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	saved := *submission
+	f.state.Submissions[submission.Key] = &saved
+	return f.write()
+}
+
+//DeleteSubmission implements Store
+func (f *FileStore) DeleteSubmission(key string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, ok := f.state.Submissions[key]; !ok {
+		return nil
+	}
+
+	delete(f.state.Submissions, key)
+	return f.write()
+}
+
+//LoadSubmissions implements Store. The submissions are sorted by key
+func (f *FileStore) LoadSubmissions() ([]*Submission, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	submissions := make([]*Submission, 0, len(f.state.Submissions))
+	for _, submission := range f.state.Submissions {
+		saved := *submission
+		submissions = append(submissions, &saved)
+	}
+	sort.Slice(submissions, func(i, j int) bool { return submissions[i].Key < submissions[j].Key })
+
+	return submissions, nil
+}
+
+//SaveMaintenance implements Store
+func (f *FileStore) SaveMaintenance(maintenance *Maintenance) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.state.Maintenance = nil
+	if maintenance != nil {
+		saved := *maintenance
+		f.state.Maintenance = &saved
+	}
+	return f.write()
+}
+
+//LoadMaintenance implements Store
+func (f *FileStore) LoadMaintenance() (*Maintenance, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.state.Maintenance == nil {
+		return nil, nil
+	}
+
+	saved := *f.state.Maintenance
+	return &saved, nil
+}
+
+//write saves the state to the file. The caller must hold the mutex
+func (f *FileStore) write() error {
+	data, err := json.MarshalIndent(&f.state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(f.path, append(data, '\n'))
+}
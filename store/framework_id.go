@@ -0,0 +1,72 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//FrameworkIDStore persists the FrameworkID received at registration, so a
+//restarted scheduler can fail over to the framework that runs its tasks
+//instead of registering a new one
+type FrameworkIDStore interface {
+	//LoadFrameworkID returns the saved ID or an empty string if there is none
+	LoadFrameworkID() (string, error)
+
+	//SaveFrameworkID saves the ID. An empty ID removes the saved one
+	SaveFrameworkID(id string) error
+}
+
+//FrameworkIDFile is a FrameworkIDStore that keeps the ID in a file
+type FrameworkIDFile struct {
+	Path string
+}
+
+//LoadFrameworkID implements FrameworkIDStore
+func (f *FrameworkIDFile) LoadFrameworkID() (string, error) {
+	data, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+//SaveFrameworkID implements FrameworkIDStore
+func (f *FrameworkIDFile) SaveFrameworkID(id string) error {
+	if id == "" {
+		err := os.Remove(f.Path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	return writeFileAtomic(f.Path, []byte(id+"\n"))
+}
+
+//writeFileAtomic writes data to a temporary file and renames it to path, so
+//a crash never leaves a half written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,170 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//snapshotVersion is the version of the format of the snapshots
+const snapshotVersion = 1
+
+//ErrNotEmpty is returned when importing a snapshot into a store that
+//already holds a state
+var ErrNotEmpty = errors.New("the store already holds a state")
+
+//Snapshot is the whole state of a store, to move it to another store or to
+//keep it for disaster recovery
+type Snapshot struct {
+	Version     int               `json:"version"`
+	Created     time.Time         `json:"created"`
+	FrameworkID string            `json:"framework_id,omitempty"`
+	Jobs        []json.RawMessage `json:"jobs"`
+	Tasks       []*Task           `json:"tasks"`
+	Deployments []*Deployment     `json:"deployments"`
+
+	//The submissions with an idempotency key, absent from the snapshots
+	//taken before they were kept
+	Submissions []*Submission `json:"submissions,omitempty"`
+
+	//Maintenance is the pause of the scheduling, if paused
+	Maintenance *Maintenance `json:"maintenance,omitempty"`
+}
+
+//Export reads the whole state of the store
+func Export(s Store) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Version:     snapshotVersion,
+		Created:     time.Now(),
+		Jobs:        []json.RawMessage{},
+		Tasks:       []*Task{},
+		Deployments: []*Deployment{},
+	}
+
+	var err error
+	if snapshot.FrameworkID, err = s.LoadFrameworkID(); err != nil {
+		return nil, err
+	}
+
+	specs, err := s.LoadJobs()
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range specs {
+		snapshot.Jobs = append(snapshot.Jobs, json.RawMessage(spec))
+	}
+
+	tasks, err := s.LoadTasks()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Tasks = append(snapshot.Tasks, tasks...)
+
+	deployments, err := s.LoadDeployments()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Deployments = append(snapshot.Deployments, deployments...)
+
+	if snapshot.Submissions, err = s.LoadSubmissions(); err != nil {
+		return nil, err
+	}
+	if snapshot.Maintenance, err = s.LoadMaintenance(); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+//Import writes the state of the snapshot into an empty store, the jobs in
+//their order. It fails with ErrNotEmpty if the store holds a state already
+func Import(s Store, snapshot *Snapshot) error {
+	if snapshot.Version != snapshotVersion {
+		return fmt.Errorf("unknown snapshot version %d", snapshot.Version)
+	}
+	if err := checkEmpty(s); err != nil {
+		return err
+	}
+
+	for _, spec := range snapshot.Jobs {
+		var job struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(spec, &job); err != nil {
+			return err
+		}
+		if job.ID == "" {
+			return errors.New("a job of the snapshot has no id")
+		}
+		if err := s.SaveJob(job.ID, spec); err != nil {
+			return err
+		}
+	}
+
+	for _, task := range snapshot.Tasks {
+		if err := s.SaveTask(task); err != nil {
+			return err
+		}
+	}
+
+	for _, deployment := range snapshot.Deployments {
+		if err := s.SaveDeployment(deployment); err != nil {
+			return err
+		}
+	}
+
+	for _, submission := range snapshot.Submissions {
+		if err := s.SaveSubmission(submission); err != nil {
+			return err
+		}
+	}
+
+	if snapshot.Maintenance != nil {
+		if err := s.SaveMaintenance(snapshot.Maintenance); err != nil {
+			return err
+		}
+	}
+
+	//The FrameworkID last, a store with it is one a scheduler can fail
+	//over with
+	if snapshot.FrameworkID != "" {
+		return s.SaveFrameworkID(snapshot.FrameworkID)
+	}
+
+	return nil
+}
+
+//checkEmpty returns ErrNotEmpty if the store holds any state
+func checkEmpty(s Store) error {
+	id, err := s.LoadFrameworkID()
+	if err != nil {
+		return err
+	}
+	jobs, err := s.LoadJobs()
+	if err != nil {
+		return err
+	}
+	tasks, err := s.LoadTasks()
+	if err != nil {
+		return err
+	}
+	deployments, err := s.LoadDeployments()
+	if err != nil {
+		return err
+	}
+	submissions, err := s.LoadSubmissions()
+	if err != nil {
+		return err
+	}
+	maintenance, err := s.LoadMaintenance()
+	if err != nil {
+		return err
+	}
+
+	if id != "" || len(jobs) > 0 || len(tasks) > 0 || len(deployments) > 0 || len(submissions) > 0 || maintenance != nil {
+		return ErrNotEmpty
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//Task is what is kept of a task across restarts of the scheduler, enough to
+//find it again in the reconciliation and to keep counting it as an instance
+//of its job. The ended tasks are kept too, for the task history
+type Task struct {
+	ID       string    `json:"id"`
+	JobID    string    `json:"job_id"`
+	Hostname string    `json:"hostname,omitempty"`
+	AgentID  string    `json:"agent_id,omitempty"`
+	State    string    `json:"state"`
+	Launched time.Time `json:"launched"`
+
+	//The time and the message of the last status update
+	Updated time.Time `json:"updated,omitempty"`
+	Message string    `json:"message,omitempty"`
+
+	//Offers are the offers the task is launched with, saved before the
+	//launch is sent and until its first status update confirms it
+	Offers []string `json:"offers,omitempty"`
+
+	//The hostname and attributes of the agent
+	Fields map[string]string `json:"fields,omitempty"`
+
+	//The resources of the task
+	Cpus float64 `json:"cpus,omitempty"`
+	Mem  float64 `json:"mem,omitempty"`
+	Disk float64 `json:"disk,omitempty"`
+	Gpus float64 `json:"gpus,omitempty"`
+
+	//The persistence ID of its volume and its host ports
+	Volume string   `json:"volume,omitempty"`
+	Ports  []uint64 `json:"ports,omitempty"`
+
+	//The version of the spec of its job it was launched with, the run of
+	//its cron job and its index in its indexed job
+	Version int `json:"version,omitempty"`
+	Run     int `json:"run,omitempty"`
+	Index   int `json:"index,omitempty"`
+
+	//Killed is set when the scheduler killed the task, TimedOut too if it
+	//was for running longer than the max runtime of its job
+	Killed   bool `json:"killed,omitempty"`
+	TimedOut bool `json:"timed_out,omitempty"`
+}
+
+//Deployment is what is kept of the last deployment of a job, so a restarted
+//scheduler goes on with it
+type Deployment struct {
+	JobID    string    `json:"job_id"`
+	Version  int       `json:"version"`
+	Strategy string    `json:"strategy"`
+	State    string    `json:"state"`
+	Started  time.Time `json:"started"`
+	Updated  time.Time `json:"updated"`
+
+	//Cutover is set once the old tasks of a blue-green deployment are
+	//killed
+	Cutover bool `json:"cutover,omitempty"`
+
+	//The canary task, since when it is ready and whether it was promoted
+	Canary      string    `json:"canary,omitempty"`
+	CanaryReady time.Time `json:"canary_ready,omitempty"`
+	Promoted    bool      `json:"promoted,omitempty"`
+
+	//The spec, in JSON, the job had before the deployment, and whether the
+	//deployment rolls back to it
+	Previous json.RawMessage `json:"previous,omitempty"`
+	Rollback bool            `json:"rollback,omitempty"`
+
+	Failures int `json:"failures,omitempty"`
+}
+
+//Submission is a job submitted with an idempotency key given by the
+//client, kept for a while so the retries of the submission don't submit
+//the job twice
+type Submission struct {
+	Key   string `json:"key"`
+	JobID string `json:"job_id"`
+
+	//Digest is the SHA-256 of the spec submitted, to refuse the key given
+	//again with another spec
+	Digest    string    `json:"digest"`
+	Submitted time.Time `json:"submitted"`
+}
+
+//Maintenance is the pause of the scheduling, kept so a restarted
+//scheduler, or the standby taking over, doesn't resume it
+type Maintenance struct {
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+//Store persists the state of the scheduler, so a restarted scheduler knows
+//its jobs and tasks before the master tells it about them
+type Store interface {
+	FrameworkIDStore
+
+	//SaveJob saves the spec of a job, in JSON. The jobs are loaded in the
+	//order they were first saved
+	SaveJob(id string, spec []byte) error
+
+	//LoadJobs returns the specs of the jobs saved
+	LoadJobs() ([][]byte, error)
+
+	//DeleteJob forgets a job and its deployment. Deleting a job not saved
+	//isn't an error
+	DeleteJob(id string) error
+
+	//SaveTask saves a task, replacing the one with its ID if any
+	SaveTask(task *Task) error
+
+	//DeleteTask forgets a task, never launched or pruned from the task
+	//history. Deleting a task not saved isn't an error
+	DeleteTask(id string) error
+
+	//LoadTasks returns the tasks saved
+	LoadTasks() ([]*Task, error)
+
+	//SaveDeployment saves the last deployment of a job, replacing the
+	//previous one
+	SaveDeployment(deployment *Deployment) error
+
+	//LoadDeployments returns the deployments saved
+	LoadDeployments() ([]*Deployment, error)
+
+	//SaveSubmission saves a submission with an idempotency key
+	SaveSubmission(submission *Submission) error
+
+	//DeleteSubmission forgets the submission of the key once it expired.
+	//Deleting a submission not saved isn't an error
+	DeleteSubmission(key string) error
+
+	//LoadSubmissions returns the submissions saved
+	LoadSubmissions() ([]*Submission, error)
+
+	//SaveMaintenance saves the pause of the scheduling, nil forgets it
+	//once the scheduling is resumed
+	SaveMaintenance(maintenance *Maintenance) error
+
+	//LoadMaintenance returns the pause saved, nil if the scheduling isn't
+	//paused
+	LoadMaintenance() (*Maintenance, error)
+}
+
+//TaskHistory is implemented by the stores that record when the tasks
+//ended. EndTask is called with the last state of a task instead of SaveTask
+type TaskHistory interface {
+	EndTask(task *Task) error
+}
+
+//Pinger is implemented by the stores on the network. Ping fails when the
+//store can't be reached. The local stores are always reachable
+type Pinger interface {
+	Ping() error
+}
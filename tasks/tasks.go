@@ -0,0 +1,102 @@
+package tasks
+
+import (
+	"time"
+
+	"github.com/mesos/mesos-go/mesosproto"
+)
+
+//Lifecycle tracks a task through its states: STAGING, STARTING, RUNNING
+//and the terminal ones. It records when each state was reached, in order,
+//and the time and message of the last status update
+type Lifecycle struct {
+	State         mesosproto.TaskState
+	History       []Transition
+	Updated       time.Time
+	StatusMessage string
+}
+
+//Transition is a state reached by a task and when
+type Transition struct {
+	State string    `json:"state"`
+	At    time.Time `json:"at"`
+}
+
+//Start records the state of a task known since the given time, without
+//any status update yet: a task just launched or restored from the store
+func (l *Lifecycle) Start(state mesosproto.TaskState, at time.Time) {
+	l.State = state
+	l.History = []Transition{{State: state.String(), At: at}}
+}
+
+//Update moves the task to the state of the status update. It returns
+//false for the updates arriving out of order, which would take the task
+//back, like a STAGING after a RUNNING or a RUNNING after the task ended.
+//Reconciliations may repeat the terminal state, it is recorded again
+func (l *Lifecycle) Update(status *mesosproto.TaskStatus) bool {
+	state := status.GetState()
+	if Rank(state) < Rank(l.State) {
+		return false
+	}
+
+	l.Updated = time.Now()
+	l.StatusMessage = status.GetMessage()
+	if state != l.State || len(l.History) == 0 {
+		l.History = append(l.History, Transition{State: state.String(), At: l.Updated})
+	}
+	l.State = state
+
+	return true
+}
+
+//IsTerminal reports if a task in the given state will never run again
+func IsTerminal(state mesosproto.TaskState) bool {
+	switch state {
+	case mesosproto.TaskState_TASK_FINISHED,
+		mesosproto.TaskState_TASK_FAILED,
+		mesosproto.TaskState_TASK_KILLED,
+		mesosproto.TaskState_TASK_ERROR,
+		mesosproto.TaskState_TASK_LOST,
+		mesosproto.TaskState_TASK_DROPPED,
+		mesosproto.TaskState_TASK_GONE,
+		mesosproto.TaskState_TASK_GONE_BY_OPERATOR:
+		return true
+	}
+
+	return false
+}
+
+//IsGone reports if the task was lost with its agent, or never reached it,
+//so it ended without any fault of its own
+func IsGone(state mesosproto.TaskState) bool {
+	switch state {
+	case mesosproto.TaskState_TASK_DROPPED,
+		mesosproto.TaskState_TASK_GONE,
+		mesosproto.TaskState_TASK_GONE_BY_OPERATOR:
+		return true
+	}
+
+	return false
+}
+
+//IsUnreachable reports if the agent of the task can't be reached, or the
+//master doesn't know it anymore. The task may still be running there and
+//come back with its agent, so it isn't ended
+func IsUnreachable(state mesosproto.TaskState) bool {
+	return state == mesosproto.TaskState_TASK_UNREACHABLE || state == mesosproto.TaskState_TASK_UNKNOWN
+}
+
+//Rank orders the states of a task: STAGING, STARTING, the states of a
+//started task and the terminal ones. A task never goes back to a lower rank
+func Rank(state mesosproto.TaskState) int {
+	switch {
+	case IsTerminal(state):
+		return 3
+	case state == mesosproto.TaskState_TASK_STAGING:
+		return 0
+	case state == mesosproto.TaskState_TASK_STARTING:
+		return 1
+	}
+
+	return 2
+}